@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,10 +15,57 @@ type AppConfig struct {
 	WebhookURL   string
 	Port         string
 	DatabasePath string
+	// DatabaseDriver selects the database: "sqlite3" (DatabasePath) or "postgres" (DatabaseURL)
+	DatabaseDriver string
+	// DatabaseURL is the PostgreSQL connection URL used when DatabaseDriver is "postgres"
+	DatabaseURL  string
 	ReminderTime string
 	Timezone     string
 	SkipWeekends bool
 	LogLevel     string
+	// LogFormat selects the log output: "json" for structured JSON lines, otherwise plain text
+	LogFormat string
+	// LeaveRetentionDays enables the nightly purge of completed/cancelled
+	// leaves older than this many days (0 disables it)
+	LeaveRetentionDays int
+	// DisplayField selects which user field names people in reminders ("display_name" or "email")
+	DisplayField string
+	// MaxActiveStandups caps the number of active standups (0 = unlimited)
+	MaxActiveStandups int
+	// MessageLocale adds a localized date line to reminders (e.g. "en", "de"; empty = no date line)
+	MessageLocale string
+	// HistoryPageSize is the default number of runs returned per page of standup history
+	HistoryPageSize int
+	// SendConcurrency limits how many reminder webhooks may be posted at the same time
+	SendConcurrency int
+	// CardFormat selects the card payload sent to Google Chat: "cardsV2" or "cards" (deprecated legacy format)
+	CardFormat string
+	// AdminWebhookURL receives an alert whenever a standup send fails (empty = only owned standups are alerted)
+	AdminWebhookURL string
+	// WebhookTimeoutSeconds bounds how long a webhook POST to Google Chat may take
+	WebhookTimeoutSeconds int
+	// PublicURL is the base URL Google Chat can reach the bot at; card reminders only get action buttons when set
+	PublicURL string
+	// ChatAudience is the audience (Google Cloud project number) Chat bearer tokens must be issued for;
+	// when set, inbound Chat requests are verified by their JWT instead of ChatVerificationToken
+	ChatAudience string
+	// ChatVerificationToken must match the token in Chat action events (empty = card actions are rejected)
+	ChatVerificationToken string
+	// APIKeys are the accepted X-API-Key values for /api/* routes (empty = API left open)
+	APIKeys []string
+	// SlowQueryMs is the duration in milliseconds after which a timed query is logged as slow (0 disables)
+	SlowQueryMs int
+	// MaxBodyBytes caps the size of request bodies accepted by the API
+	MaxBodyBytes int64
+	// IdempotencyKeyTTLHours is how long a create response is kept for replay to a request with the same Idempotency-Key
+	IdempotencyKeyTTLHours int
+	// CORSAllowedOrigins are the browser origins allowed to call /api/* cross-origin ("*" = any; empty = same-origin only)
+	CORSAllowedOrigins []string
+	// CORSAllowedMethods and CORSAllowedHeaders are answered to CORS preflight requests
+	CORSAllowedMethods []string
+	CORSAllowedHeaders []string
+
+	location *time.Location
 }
 
 var Config *AppConfig
@@ -35,26 +85,179 @@ func LoadConfig() error {
 		Timezone:     getEnv("TIMEZONE", "UTC"),
 		SkipWeekends: getEnv("SKIP_WEEKENDS", "true") == "true",
 		LogLevel:     getEnv("LOG_LEVEL", "info"),
+		LogFormat:    getEnv("LOG_FORMAT", "text"),
+
+		LeaveRetentionDays: getEnvInt("LEAVE_RETENTION_DAYS", 0),
+		DatabaseDriver:     getEnv("DB_DRIVER", "sqlite3"),
+		DatabaseURL:        getEnv("DATABASE_URL", ""),
+		DisplayField:       getEnv("DISPLAY_FIELD", "display_name"),
+		MaxActiveStandups:  getEnvInt("MAX_ACTIVE_STANDUPS", 0),
+		SendConcurrency:    getEnvInt("SEND_CONCURRENCY", 2),
+		MessageLocale:      getEnv("MESSAGE_LOCALE", ""),
+		HistoryPageSize:    getEnvInt("HISTORY_PAGE_SIZE", 20),
+		SlowQueryMs:        getEnvInt("SLOW_QUERY_MS", 200),
+		CardFormat:         getEnv("CARD_FORMAT", "cardsV2"),
+
+		WebhookTimeoutSeconds: getEnvInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+		AdminWebhookURL:       getEnv("ADMIN_WEBHOOK_URL", ""),
+		PublicURL:             strings.TrimRight(getEnv("PUBLIC_URL", ""), "/"),
+		ChatVerificationToken: getEnv("CHAT_VERIFICATION_TOKEN", ""),
+		ChatAudience:          getEnv("CHAT_AUDIENCE", ""),
+		APIKeys:               getEnvList("API_KEY"),
+
+		IdempotencyKeyTTLHours: getEnvInt("IDEMPOTENCY_KEY_TTL_HOURS", 24),
+
+		CORSAllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS"),
+		CORSAllowedMethods: getEnvListDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE"),
+		CORSAllowedHeaders: getEnvListDefault("CORS_ALLOWED_HEADERS", "Content-Type,X-API-Key,Idempotency-Key"),
+	}
+	Config.MaxBodyBytes = int64(getEnvInt("MAX_BODY_BYTES", 1<<20))
+
+	loc, err := time.LoadLocation(Config.Timezone)
+	if err != nil {
+		log.Printf("Warning: invalid TIMEZONE=%q, using UTC: %v", Config.Timezone, err)
+		loc = time.UTC
+	}
+	Config.location = loc
+
+	if Config.SendConcurrency < 1 {
+		log.Printf("Warning: invalid SEND_CONCURRENCY=%d, using 1", Config.SendConcurrency)
+		Config.SendConcurrency = 1
+	}
+
+	if Config.HistoryPageSize < 1 {
+		log.Printf("Warning: invalid HISTORY_PAGE_SIZE=%d, using 20", Config.HistoryPageSize)
+		Config.HistoryPageSize = 20
+	}
+
+	if Config.WebhookTimeoutSeconds < 1 {
+		log.Printf("Warning: invalid WEBHOOK_TIMEOUT_SECONDS=%d, using 10", Config.WebhookTimeoutSeconds)
+		Config.WebhookTimeoutSeconds = 10
+	}
+
+	if Config.SlowQueryMs < 0 {
+		log.Printf("Warning: invalid SLOW_QUERY_MS=%d, using 200", Config.SlowQueryMs)
+		Config.SlowQueryMs = 200
+	}
+
+	if Config.MaxBodyBytes < 1 {
+		log.Printf("Warning: invalid MAX_BODY_BYTES=%d, using 1048576", Config.MaxBodyBytes)
+		Config.MaxBodyBytes = 1 << 20
+	}
+
+	if Config.IdempotencyKeyTTLHours < 1 {
+		log.Printf("Warning: invalid IDEMPOTENCY_KEY_TTL_HOURS=%d, using 24", Config.IdempotencyKeyTTLHours)
+		Config.IdempotencyKeyTTLHours = 24
+	}
+
+	if Config.CardFormat != "cards" && Config.CardFormat != "cardsV2" {
+		log.Printf("Warning: invalid CARD_FORMAT=%q, using cardsV2", Config.CardFormat)
+		Config.CardFormat = "cardsV2"
+	}
+
+	if Config.DisplayField != "display_name" && Config.DisplayField != "email" {
+		log.Printf("Warning: invalid DISPLAY_FIELD=%q, using display_name", Config.DisplayField)
+		Config.DisplayField = "display_name"
 	}
 
 	// Validate required config
 	if Config.WebhookURL == "" {
 		log.Fatal("GOOGLE_CHAT_WEBHOOK_URL environment variable is required")
 	}
+	if Config.DatabaseDriver == "postgres" && Config.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required when DB_DRIVER=postgres")
+	}
 
 	log.Printf("Configuration loaded successfully")
 	log.Printf("  Port: %s", Config.Port)
-	log.Printf("  Database: %s", Config.DatabasePath)
+	if Config.DatabaseDriver == "postgres" {
+		log.Printf("  Database: postgres")
+	} else {
+		log.Printf("  Database: %s", Config.DatabasePath)
+	}
 	log.Printf("  Reminder Time: %s", Config.ReminderTime)
 	log.Printf("  Timezone: %s", Config.Timezone)
 	log.Printf("  Skip Weekends: %t", Config.SkipWeekends)
+	if Config.LeaveRetentionDays > 0 {
+		log.Printf("  Leave Retention: %d days", Config.LeaveRetentionDays)
+	}
+	if Config.MaxActiveStandups > 0 {
+		log.Printf("  Max Active Standups: %d", Config.MaxActiveStandups)
+	}
+	if len(Config.APIKeys) > 0 {
+		log.Printf("  API Keys: %d configured", len(Config.APIKeys))
+	} else {
+		log.Printf("⚠️  WARNING: API_KEY is not set - the /api/* endpoints are open to anyone who can reach this port. Set API_KEY outside local development.")
+	}
+
+	if len(Config.CORSAllowedOrigins) > 0 {
+		log.Printf("  CORS Allowed Origins: %s", strings.Join(Config.CORSAllowedOrigins, ", "))
+	}
 
 	return nil
 }
 
+// DatabaseDSN returns what the configured driver connects with: DATABASE_URL for postgres,
+// DATABASE_PATH otherwise
+func (c *AppConfig) DatabaseDSN() string {
+	if c.DatabaseDriver == "postgres" {
+		return c.DatabaseURL
+	}
+	return c.DatabasePath
+}
+
+// Location returns the configured timezone, or UTC if none was loaded. A config built without
+// LoadConfig (as in tests) resolves Timezone on each call.
+func (c *AppConfig) Location() *time.Location {
+	if c == nil {
+		return time.UTC
+	}
+	if c.location == nil {
+		loc, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return time.UTC
+		}
+		return loc
+	}
+	return c.location
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+// getEnvList splits a comma-separated variable into its non-empty, trimmed values
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvListDefault is getEnvList, falling back to the comma-separated defaultValue when the variable is unset or empty
+func getEnvListDefault(key, defaultValue string) []string {
+	if values := getEnvList(key); len(values) > 0 {
+		return values
+	}
+	return strings.Split(defaultValue, ",")
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
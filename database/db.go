@@ -1,37 +1,94 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/lib/pq"
+	"github.com/lib/pq/pqerror"
+	"github.com/mattn/go-sqlite3"
 )
 
 var DB *sql.DB
 
-// InitDB initializes the database connection and runs migrations
-func InitDB(dbPath string) error {
+// Database drivers accepted by InitDB (DB_DRIVER)
+const (
+	DriverSQLite   = "sqlite3"
+	DriverPostgres = "postgres"
+)
+
+// Driver is the driver of the open database. Queries are written for SQLite with "?"
+// placeholders; the few statements that differ on PostgreSQL check it.
+var Driver = DriverSQLite
+
+// ErrUnsupportedDriver is returned by InitDB for a DB_DRIVER other than sqlite3 or postgres
+var ErrUnsupportedDriver = errors.New("unsupported database driver")
+
+// InitDB initializes the database connection and runs migrations. dsn is the SQLite file path
+// for sqlite3 and the connection URL for postgres.
+func InitDB(driver, dsn string) error {
 	var err error
-	DB, err = sql.Open("sqlite3", dbPath)
+	switch driver {
+	case DriverSQLite:
+		DB, err = sql.Open(DriverSQLite, dsn)
+	case DriverPostgres:
+		DB, err = openPostgres(dsn)
+	default:
+		return fmt.Errorf("%w %q: use %q or %q", ErrUnsupportedDriver, driver, DriverSQLite, DriverPostgres)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
+	Driver = driver
 
 	// Test the connection
 	if err = DB.Ping(); err != nil {
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Printf("Database connection established: %s", dbPath)
+	if driver == DriverSQLite {
+		slog.Info("Database connection established", "driver", driver, "path", dsn)
+	} else {
+		slog.Info("Database connection established", "driver", driver)
+	}
 
 	// Run migrations
 	if err = RunMigrations(); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	log.Println("Database migrations completed successfully")
+	slog.Info("Database migrations completed successfully")
+
+	return nil
+}
+
+// IsUniqueViolation reports whether err comes from a UNIQUE constraint failing
+func IsUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqerror.UniqueViolation
+}
 
+// Ping checks that the database is reachable and can answer a query
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return errors.New("database not initialized")
+	}
+	if err := DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	var one int
+	if err := DB.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		return fmt.Errorf("failed to query database: %w", err)
+	}
 	return nil
 }
 
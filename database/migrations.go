@@ -3,28 +3,395 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
+	"time"
 )
 
-// RunMigrations runs all database migrations
+// RunMigrations applies every schema migration that has not been recorded in schema_migrations yet
 func RunMigrations() error {
-	migrations := []string{
+	if _, err := DB.Exec(createSchemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := appliedMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range schemaMigrations {
+		if applied[m.version] {
+			continue
+		}
+		if err := runMigration(m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		slog.Info("Applied migration", "version", m.version, "name", m.name)
+	}
+
+	slog.Info("All migrations completed successfully")
+	return nil
+}
+
+// migration is one numbered schema change. Each runs once, in a transaction that also records
+// it in schema_migrations. Steps must tolerate a database that already has the change, since
+// databases created before schema_migrations existed replay every step once.
+type migration struct {
+	version int
+	name    string
+	apply   func(tx *sql.Tx) error
+}
+
+// schemaMigrations lists every schema change in order. Append new steps with the next version;
+// never renumber or change a step that has been released.
+var schemaMigrations = []migration{
+	{1, "create tables", createTables},
+	{2, "add standups.webhook_url", addColumn("standups", "webhook_url", "TEXT DEFAULT ''")},
+	{3, "add standups.membership", addColumn("standups", "membership", "TEXT DEFAULT 'explicit'")},
+	{4, "add standups.skip_when_alone", addColumn("standups", "skip_when_alone", "BOOLEAN DEFAULT 0")},
+	{5, "add standups.show_returning", addColumn("standups", "show_returning", "BOOLEAN DEFAULT 0")},
+	{6, "add standups.full_team_message", addColumn("standups", "full_team_message", "TEXT DEFAULT ''")},
+	{7, "add standups.manual_send_rotates", addColumn("standups", "manual_send_rotates", "BOOLEAN DEFAULT 1")},
+	{8, "add standup_members.alias", addColumn("standup_members", "alias", "TEXT DEFAULT ''")},
+	{9, "add standups.timezone", addColumn("standups", "timezone", "TEXT DEFAULT ''")},
+	{10, "add standups.facilitator_only", addColumn("standups", "facilitator_only", "BOOLEAN DEFAULT 0")},
+	{11, "add standups.days_of_week", addColumn("standups", "days_of_week", "TEXT DEFAULT ''")},
+	{12, "add standup_members.can_facilitate", addColumn("standup_members", "can_facilitate", "BOOLEAN DEFAULT 1")},
+	{13, "add standups.owner_user_id", addColumn("standups", "owner_user_id", "INTEGER REFERENCES users(id)")},
+	{14, "add standups.daily_thread", addColumn("standups", "daily_thread", "BOOLEAN DEFAULT 0")},
+	{15, "add standups.empty_retry_minutes", addColumn("standups", "empty_retry_minutes", "INTEGER DEFAULT 0")},
+	{16, "add standups.message_format", addColumn("standups", "message_format", "TEXT DEFAULT 'text'")},
+	{17, "add leaves.day_portion", addColumn("leaves", "day_portion", "TEXT DEFAULT 'full'")},
+	{18, "add standups.template", addColumn("standups", "template", "TEXT DEFAULT ''")},
+	{19, "add leaves status check", addLeaveStatusCheck},
+	{20, "index standups.webhook_url", execStatements(`CREATE INDEX IF NOT EXISTS idx_standups_webhook_url ON standups(webhook_url);`)},
+	{21, "add standups.webhook_urls", addColumn("standups", "webhook_urls", "TEXT DEFAULT ''")},
+	{22, "add standups.mention_facilitator", addColumn("standups", "mention_facilitator", "BOOLEAN DEFAULT 0")},
+	{23, "add standups.thread_key", addColumn("standups", "thread_key", "TEXT DEFAULT ''")},
+	{24, "add leave approval", addLeaveApproval},
+	{25, "create idempotency table", execStatements(createIdempotencyTable)},
+	{26, "add facilitator rotation counter", addFacilitatorRotation},
+	{27, "add standups.snoozed_until", addColumn("standups", "snoozed_until", "TEXT DEFAULT ''")},
+	{28, "add standups.nominated_facilitator_id", addColumn("standups", "nominated_facilitator_id", "INTEGER REFERENCES users(id)")},
+}
+
+const createSchemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+// appliedMigrations returns the versions recorded in schema_migrations
+func appliedMigrations() (map[int]bool, error) {
+	rows, err := DB.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan migration version: %w", err)
+		}
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+// runMigration applies one step and records it, both or neither
+func runMigration(m migration) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.apply(tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", m.version, m.name); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// createTables creates the tables as they were before numbered migrations were introduced
+func createTables(tx *sql.Tx) error {
+	return execStatements(
 		createUsersTable,
 		createLeavesTable,
 		createStandupsTable,
 		createStandupMembersTable,
+		createStandupRunsTable,
+		createFacilitatorSubstitutionsTable,
+		createHolidaysTable,
+		createFacilitatorHistoryTable,
+	)(tx)
+}
+
+// execStatements returns a step that executes the given SQL in order
+func execStatements(statements ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, statement := range statements {
+			if _, err := tx.Exec(ddl(statement)); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
+}
 
-	for i, migration := range migrations {
-		if _, err := DB.Exec(migration); err != nil {
-			return fmt.Errorf("migration %d failed: %w", i, err)
+// addColumn returns a step that adds a column to a table unless it already exists
+func addColumn(table, column, definition string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		columns, err := tableColumns(tx, table)
+		if err != nil {
+			return err
 		}
+
+		for _, name := range columns {
+			if name == column {
+				return nil
+			}
+		}
+
+		_, err = tx.Exec(ddl(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)))
+		return err
+	}
+}
+
+// postgresDDL rewrites the SQLite column definitions used by the migrations into their
+// PostgreSQL equivalents
+var postgresDDL = strings.NewReplacer(
+	"INTEGER PRIMARY KEY AUTOINCREMENT", "SERIAL PRIMARY KEY",
+	"BOOLEAN DEFAULT 1", "BOOLEAN DEFAULT TRUE",
+	"BOOLEAN DEFAULT 0", "BOOLEAN DEFAULT FALSE",
+)
+
+// ddl adapts a schema statement, written for SQLite, to the open database's driver
+func ddl(statement string) string {
+	if Driver == DriverPostgres {
+		return postgresDDL.Replace(statement)
+	}
+	return statement
+}
+
+// addLeaveStatusCheck rebuilds a leaves table created before the status CHECK constraint existed.
+// SQLite cannot add a constraint in place, so rows are copied into a new table; any status
+// outside the known set is treated as cancelled. PostgreSQL databases were created with the
+// current leaves table, so there is nothing to do there.
+func addLeaveStatusCheck(tx *sql.Tx) error {
+	if Driver == DriverPostgres {
+		return nil
+	}
+
+	var tableSQL string
+	if err := tx.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'leaves'").Scan(&tableSQL); err != nil {
+		return err
+	}
+	if strings.Contains(tableSQL, "CHECK") {
+		return nil
+	}
+
+	result, err := tx.Exec(`
+		UPDATE leaves SET status = 'cancelled'
+		WHERE status IS NOT NULL AND status NOT IN ('active', 'completed', 'cancelled')
+	`)
+	if err != nil {
+		return err
+	}
+	if fixed, _ := result.RowsAffected(); fixed > 0 {
+		slog.Info("Marked leaves with an unknown status as cancelled", "count", fixed)
+	}
+
+	if err := rebuildLeavesTable(tx); err != nil {
+		return err
 	}
 
-	log.Println("All migrations completed successfully")
+	slog.Info("Added status constraint to leaves table")
 	return nil
 }
 
+// addLeaveApproval rebuilds the leaves table so its status CHECK allows 'pending' and 'rejected'
+// and it has the approved_by/approved_at columns. Existing leaves keep their status, so active
+// ones stay active without approval; only leaves created from now on start out pending.
+// Like addLeaveStatusCheck, it has nothing to do on PostgreSQL.
+func addLeaveApproval(tx *sql.Tx) error {
+	if Driver == DriverPostgres {
+		return nil
+	}
+
+	var tableSQL string
+	if err := tx.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'leaves'").Scan(&tableSQL); err != nil {
+		return err
+	}
+	if strings.Contains(tableSQL, "'pending'") {
+		return nil
+	}
+
+	if err := rebuildLeavesTable(tx); err != nil {
+		return err
+	}
+
+	slog.Info("Added approval status and columns to leaves table")
+	return nil
+}
+
+// addFacilitatorRotation adds the per-standup turn counter and the table recording the turn each
+// member last took. Existing standups start a fresh cycle with everyone due, so the rotation
+// carries on after last_facilitator_id as before.
+func addFacilitatorRotation(tx *sql.Tx) error {
+	steps := []func(tx *sql.Tx) error{
+		addColumn("standups", "rotation_counter", "INTEGER DEFAULT 0"),
+		addColumn("standups", "rotation_cycle_start", "INTEGER DEFAULT 1"),
+		execStatements(createFacilitatorTurnsTable),
+	}
+	for _, step := range steps {
+		if err := step(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildLeavesTable copies the leaves into a new table created from leavesColumns, for changes
+// SQLite cannot make in place. Columns the old table lacks get their defaults.
+func rebuildLeavesTable(tx *sql.Tx) error {
+	return execStatements(
+		`CREATE TABLE leaves_new (`+leavesColumns+`)`,
+		`INSERT INTO leaves_new (id, user_id, leave_type, start_date, end_date, reason, status, day_portion, created_at, updated_at)
+		 SELECT id, user_id, leave_type, start_date, end_date, reason, status, day_portion, created_at, updated_at FROM leaves`,
+		`DROP TABLE leaves`,
+		`ALTER TABLE leaves_new RENAME TO leaves`,
+		createLeavesIndexes,
+	)(tx)
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// tableColumns returns the column names of a table in declaration order
+func tableColumns(q querier, table string) ([]string, error) {
+	if Driver == DriverPostgres {
+		return queryNames(q, `
+			SELECT column_name FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ?
+			ORDER BY ordinal_position
+		`, table)
+	}
+
+	rows, err := q.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, rows.Err()
+}
+
+// queryNames returns the single text column of every row a query returns
+func queryNames(q querier, query string, args ...interface{}) ([]string, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// SchemaStatus describes the tables in the database and which migrations are still pending
+type SchemaStatus struct {
+	// Version is the highest applied migration; it equals LatestVersion when up to date
+	Version           int                 `json:"version"`
+	LatestVersion     int                 `json:"latest_version"`
+	PendingMigrations []string            `json:"pending_migrations"`
+	Tables            map[string][]string `json:"tables"`
+}
+
+// GetSchemaStatus inspects the live schema without changing it
+func GetSchemaStatus() (*SchemaStatus, error) {
+	query := "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name"
+	if Driver == DriverPostgres {
+		query = `
+			SELECT table_name FROM information_schema.tables
+			WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'
+			ORDER BY table_name
+		`
+	}
+
+	tables, err := queryNames(DB, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	status := &SchemaStatus{
+		LatestVersion:     schemaMigrations[len(schemaMigrations)-1].version,
+		PendingMigrations: []string{},
+		Tables:            make(map[string][]string),
+	}
+
+	for _, table := range tables {
+		columns, err := tableColumns(DB, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		status.Tables[table] = columns
+	}
+
+	// Before the first migration run there is no schema_migrations table, so nothing is applied
+	applied := make(map[int]bool)
+	if _, ok := status.Tables["schema_migrations"]; ok {
+		if applied, err = appliedMigrations(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range schemaMigrations {
+		if applied[m.version] {
+			status.Version = m.version
+		} else {
+			status.PendingMigrations = append(status.PendingMigrations, fmt.Sprintf("%d: %s", m.version, m.name))
+		}
+	}
+
+	return status, nil
+}
+
 const createUsersTable = `
 CREATE TABLE IF NOT EXISTS users (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -42,25 +409,33 @@ CREATE INDEX IF NOT EXISTS idx_users_active ON users(is_active);
 CREATE INDEX IF NOT EXISTS idx_users_google_chat_id ON users(google_chat_user_id);
 `
 
-const createLeavesTable = `
-CREATE TABLE IF NOT EXISTS leaves (
+// leavesColumns is the leaves table definition, shared by table creation and the leaves rebuilds
+const leavesColumns = `
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     user_id INTEGER NOT NULL,
     leave_type TEXT NOT NULL,
     start_date DATE NOT NULL,
     end_date DATE NOT NULL,
     reason TEXT,
-    status TEXT DEFAULT 'active',
+    status TEXT DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'completed', 'cancelled', 'rejected')),
+    day_portion TEXT DEFAULT 'full',
+    approved_by TEXT,
+    approved_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (user_id) REFERENCES users(id)
-);
+`
 
+const createLeavesIndexes = `
 CREATE INDEX IF NOT EXISTS idx_leaves_user ON leaves(user_id);
 CREATE INDEX IF NOT EXISTS idx_leaves_dates ON leaves(start_date, end_date);
 CREATE INDEX IF NOT EXISTS idx_leaves_status ON leaves(status);
 `
 
+const createLeavesTable = `
+CREATE TABLE IF NOT EXISTS leaves (` + leavesColumns + `);
+` + createLeavesIndexes
+
 const createStandupsTable = `
 CREATE TABLE IF NOT EXISTS standups (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -95,24 +470,162 @@ CREATE INDEX IF NOT EXISTS idx_standup_members_user ON standup_members(user_id);
 CREATE INDEX IF NOT EXISTS idx_standup_members_order ON standup_members(standup_id, display_order);
 `
 
+const createStandupRunsTable = `
+CREATE TABLE IF NOT EXISTS standup_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    standup_id INTEGER NOT NULL,
+    run_date TEXT NOT NULL,
+    status TEXT NOT NULL,
+    skip_reason TEXT DEFAULT '',
+    trigger TEXT NOT NULL,
+    facilitator_id INTEGER,
+    detail TEXT DEFAULT '',
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (standup_id) REFERENCES standups(id) ON DELETE CASCADE,
+    FOREIGN KEY (facilitator_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_standup_runs_standup_date ON standup_runs(standup_id, run_date);
+CREATE INDEX IF NOT EXISTS idx_standup_runs_sent_at ON standup_runs(standup_id, sent_at, id);
+`
+
+const createFacilitatorSubstitutionsTable = `
+CREATE TABLE IF NOT EXISTS facilitator_substitutions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    standup_id INTEGER NOT NULL,
+    original_user_id INTEGER NOT NULL,
+    substitute_user_id INTEGER NOT NULL,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (standup_id) REFERENCES standups(id) ON DELETE CASCADE,
+    FOREIGN KEY (original_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (substitute_user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_facilitator_substitutions_standup ON facilitator_substitutions(standup_id, start_date, end_date);
+`
+
+const createHolidaysTable = `
+CREATE TABLE IF NOT EXISTS holidays (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    date TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const createFacilitatorHistoryTable = `
+CREATE TABLE IF NOT EXISTS facilitator_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    standup_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    facilitated_on TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (standup_id) REFERENCES standups(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_facilitator_history_standup ON facilitator_history(standup_id, facilitated_on);
+`
+
+// createFacilitatorTurnsTable records, per standup, the rotation_counter value of the turn each
+// member last took
+const createFacilitatorTurnsTable = `
+CREATE TABLE IF NOT EXISTS facilitator_turns (
+    standup_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    turn INTEGER NOT NULL,
+    PRIMARY KEY (standup_id, user_id),
+    FOREIGN KEY (standup_id) REFERENCES standups(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+`
+
+// createIdempotencyTable stores the responses to create requests sent with an Idempotency-Key,
+// so a retried request gets the original response. status_code 0 marks a request still running.
+const createIdempotencyTable = `
+CREATE TABLE IF NOT EXISTS idempotency (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    key TEXT NOT NULL,
+    endpoint TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    response_body TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (key, endpoint)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_created_at ON idempotency(created_at);
+`
+
+// StandupMemberFilter is a WHERE condition matching users (aliased u) who belong to the standup
+// bound to its single placeholder: the standup_members roster for 'explicit' standups, or every
+// active user for 'all_active' standups
+const StandupMemberFilter = `EXISTS (
+			SELECT 1 FROM standups s
+			WHERE s.id = ?
+			AND (
+				(s.membership = 'all_active' AND u.is_active = TRUE)
+				OR (s.membership != 'all_active'
+					AND u.id IN (SELECT user_id FROM standup_members WHERE standup_id = s.id))
+			)
+		)`
+
+// JSONListContains is a WHERE condition true when column, a JSON list of strings ("" for none),
+// contains the value bound to its single placeholder
+func JSONListContains(column string) string {
+	if Driver == DriverPostgres {
+		return `EXISTS (
+			SELECT 1 FROM json_array_elements_text(CAST(NULLIF(` + column + `, '') AS json)) AS element(value)
+			WHERE value = ?
+		)`
+	}
+	return `EXISTS (
+			SELECT 1 FROM json_each(CASE WHEN json_valid(` + column + `) THEN ` + column + ` ELSE '[]' END)
+			WHERE value = ?
+		)`
+}
+
+// StandupMemberOrder is an ORDER BY clause listing a standup's members in rotation order: roster
+// display_order for 'explicit' standups, display_name for 'all_active' ones. Binds the standup ID once.
+const StandupMemberOrder = `(
+			SELECT sm.display_order FROM standup_members sm
+			INNER JOIN standups s ON s.id = sm.standup_id
+			WHERE sm.standup_id = ? AND sm.user_id = u.id AND s.membership != 'all_active'
+		), u.display_name`
+
+// LeaveCoversRunAt is a WHERE condition true when a leave (aliased l) covers the run_at of a
+// standup (aliased s): full-day leaves always, morning leaves before 12:00, afternoon leaves from 12:00
+const LeaveCoversRunAt = `(
+			l.day_portion = 'full'
+			OR (l.day_portion = 'morning' AND s.run_at < '12:00')
+			OR (l.day_portion = 'afternoon' AND s.run_at >= '12:00')
+		)`
+
 // GetEligibleUsersForStandup returns users assigned to a standup who are active and not on leave
-func GetEligibleUsersForStandup(standupID int) ([]User, error) {
+// on the given day (YYYY-MM-DD, in the team's timezone) at the standup's run_at, in rotation order
+func GetEligibleUsersForStandup(standupID int, today string) ([]User, error) {
+	defer TimeQuery("GetEligibleUsersForStandup", time.Now())
+
 	query := `
-		SELECT DISTINCT u.id, u.google_chat_user_id, u.display_name, u.email, u.is_active,
+		SELECT u.id, u.google_chat_user_id, u.display_name, u.email, u.is_active,
 		       u.joined_at, u.left_at, u.created_at, u.updated_at
 		FROM users u
-		INNER JOIN standup_members sm ON u.id = sm.user_id
-		WHERE sm.standup_id = ?
-		AND u.is_active = 1
+		WHERE ` + StandupMemberFilter + `
+		AND u.is_active = TRUE
 		AND u.id NOT IN (
-			SELECT user_id FROM leaves
-			WHERE status = 'active'
-			AND start_date <= date('now')
-			AND end_date >= date('now')
+			SELECT l.user_id FROM leaves l
+			INNER JOIN standups s ON s.id = ?
+			WHERE l.status = 'active'
+			AND date(l.start_date) <= ?
+			AND date(l.end_date) >= ?
+			AND ` + LeaveCoversRunAt + `
 		)
+		ORDER BY ` + StandupMemberOrder + `
 	`
 
-	rows, err := DB.Query(query, standupID)
+	rows, err := DB.Query(query, standupID, standupID, today, today, standupID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query eligible users: %w", err)
 	}
@@ -147,23 +660,43 @@ func GetEligibleUsersForStandup(standupID int) ([]User, error) {
 	return users, nil
 }
 
-// ExpireOldLeaves marks leaves as completed if their end_date has passed
-func ExpireOldLeaves() error {
+// ExpireOldLeaves marks leaves as completed if their end_date is before the given day (YYYY-MM-DD).
+// Pending leaves that ended without being approved are rejected, so they don't wait for approval forever.
+func ExpireOldLeaves(today string) error {
+	defer TimeQuery("ExpireOldLeaves", time.Now())
+
 	query := `
 		UPDATE leaves
 		SET status = 'completed', updated_at = CURRENT_TIMESTAMP
 		WHERE status = 'active'
-		AND end_date < date('now')
+		AND date(end_date) < ?
 	`
 
-	result, err := DB.Exec(query)
+	result, err := DB.Exec(query, today)
 	if err != nil {
 		return fmt.Errorf("failed to expire old leaves: %w", err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected > 0 {
-		log.Printf("Expired %d old leave(s)", rowsAffected)
+		slog.Info("Expired old leaves", "count", rowsAffected)
+	}
+
+	query = `
+		UPDATE leaves
+		SET status = 'rejected', updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'pending'
+		AND date(end_date) < ?
+	`
+
+	result, err = DB.Exec(query, today)
+	if err != nil {
+		return fmt.Errorf("failed to reject unapproved past leaves: %w", err)
+	}
+
+	rowsAffected, _ = result.RowsAffected()
+	if rowsAffected > 0 {
+		slog.Info("Rejected pending leaves that ended without approval", "count", rowsAffected)
 	}
 
 	return nil
@@ -172,27 +705,80 @@ func ExpireOldLeaves() error {
 // LeaveWithUser represents a leave record with user information
 type LeaveWithUser struct {
 	Leave
-	User User
+	User User `json:"user"`
+}
+
+// GetActiveLeavesForStandup returns active leaves for standup members on a specific date (YYYY-MM-DD)
+// that cover the standup's run_at, so a half-day leave only shows for standups in that half
+func GetActiveLeavesForStandup(standupID int, today string) ([]LeaveWithUser, error) {
+	leaves, err := GetLeavesForStandupInRange(standupID, today, today)
+	if err != nil {
+		return nil, err
+	}
+
+	var runAt string
+	if err := DB.QueryRow("SELECT run_at FROM standups WHERE id = ?", standupID).Scan(&runAt); err != nil {
+		return nil, fmt.Errorf("failed to get standup run_at: %w", err)
+	}
+
+	var covering []LeaveWithUser
+	for _, leave := range leaves {
+		if leave.CoversRunAt(runAt) {
+			covering = append(covering, leave)
+		}
+	}
+
+	return covering, nil
+}
+
+// CoversRunAt reports whether the leave covers a standup running at runAt (HH:MM), matching LeaveCoversRunAt
+func (l Leave) CoversRunAt(runAt string) bool {
+	switch l.DayPortion {
+	case DayPortionMorning:
+		return runAt < "12:00"
+	case DayPortionAfternoon:
+		return runAt >= "12:00"
+	default:
+		return true
+	}
 }
 
-// GetActiveLeavesForStandup returns active leaves for standup members on a specific date
-func GetActiveLeavesForStandup(standupID int) ([]LeaveWithUser, error) {
+// GetLeavesEndingSoon returns active leaves of standup members whose last day (end_date) is the given date (YYYY-MM-DD)
+func GetLeavesEndingSoon(standupID int, date string) ([]LeaveWithUser, error) {
+	leaves, err := GetLeavesForStandupInRange(standupID, date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	var ending []LeaveWithUser
+	for _, leave := range leaves {
+		if leave.EndDate.Format("2006-01-02") == date {
+			ending = append(ending, leave)
+		}
+	}
+
+	return ending, nil
+}
+
+// GetLeavesForStandupInRange returns active leaves for standup members that overlap [from, to] (YYYY-MM-DD)
+func GetLeavesForStandupInRange(standupID int, from, to string) ([]LeaveWithUser, error) {
+	defer TimeQuery("GetLeavesForStandupInRange", time.Now())
+
 	query := `
 		SELECT l.id, l.user_id, l.leave_type, l.start_date, l.end_date, l.reason, l.status,
-		       l.created_at, l.updated_at,
+		       l.day_portion, l.created_at, l.updated_at,
 		       u.id, u.google_chat_user_id, u.display_name, u.email, u.is_active,
 		       u.joined_at, u.left_at, u.created_at, u.updated_at
 		FROM leaves l
 		INNER JOIN users u ON l.user_id = u.id
-		INNER JOIN standup_members sm ON u.id = sm.user_id
-		WHERE sm.standup_id = ?
+		WHERE ` + StandupMemberFilter + `
 		AND l.status = 'active'
-		AND l.start_date <= date('now')
-		AND l.end_date >= date('now')
-		ORDER BY u.display_name
+		AND date(l.start_date) <= ?
+		AND date(l.end_date) >= ?
+		ORDER BY u.display_name, l.start_date
 	`
 
-	rows, err := DB.Query(query, standupID)
+	rows, err := DB.Query(query, standupID, to, from)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query active leaves: %w", err)
 	}
@@ -210,6 +796,7 @@ func GetActiveLeavesForStandup(standupID int) ([]LeaveWithUser, error) {
 			&lwu.Leave.EndDate,
 			&lwu.Leave.Reason,
 			&lwu.Leave.Status,
+			&lwu.Leave.DayPortion,
 			&lwu.Leave.CreatedAt,
 			&lwu.Leave.UpdatedAt,
 			&lwu.User.ID,
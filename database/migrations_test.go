@@ -0,0 +1,120 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+)
+
+// openMemoryDB points DB at a fresh in-memory database. The pool is kept to one connection,
+// since every connection to ":memory:" gets its own empty database.
+func openMemoryDB(t *testing.T) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	DB = db
+	t.Cleanup(func() { db.Close() })
+}
+
+func TestRunMigrationsTwice(t *testing.T) {
+	openMemoryDB(t)
+
+	for run := 1; run <= 2; run++ {
+		if err := RunMigrations(); err != nil {
+			t.Fatalf("RunMigrations (run %d): %v", run, err)
+		}
+	}
+
+	rows, err := DB.Query("SELECT version, name FROM schema_migrations ORDER BY version")
+	if err != nil {
+		t.Fatalf("query schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	var versions []int
+	for rows.Next() {
+		var version int
+		var name string
+		if err := rows.Scan(&version, &name); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		if want := schemaMigrations[len(versions)]; version != want.version || name != want.name {
+			t.Errorf("schema_migrations row %d = (%d, %q), want (%d, %q)", len(versions), version, name, want.version, want.name)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows: %v", err)
+	}
+
+	if len(versions) != 28 || len(schemaMigrations) != 28 {
+		t.Errorf("%d migrations recorded of %d defined, want 28", len(versions), len(schemaMigrations))
+	}
+}
+
+func TestMigratedSchema(t *testing.T) {
+	openMemoryDB(t)
+	if err := RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+
+	tests := []struct {
+		table   string
+		columns []string
+	}{
+		{"users", []string{"id", "google_chat_user_id", "display_name", "email", "is_active", "left_at"}},
+		{"leaves", []string{"id", "user_id", "leave_type", "start_date", "end_date", "status", "day_portion", "approved_by", "approved_at"}},
+		{"standups", []string{
+			"id", "name", "message", "run_at", "is_active", "last_facilitator_id", "webhook_url", "membership",
+			"skip_when_alone", "show_returning", "full_team_message", "manual_send_rotates", "timezone",
+			"facilitator_only", "days_of_week", "owner_user_id", "daily_thread", "empty_retry_minutes",
+			"message_format", "template", "webhook_urls", "mention_facilitator", "thread_key",
+			"rotation_counter", "rotation_cycle_start", "snoozed_until", "nominated_facilitator_id",
+		}},
+		{"standup_members", []string{"standup_id", "user_id", "display_order", "alias", "can_facilitate"}},
+		{"facilitator_turns", []string{"standup_id", "user_id", "turn"}},
+		{"idempotency", nil},
+		{"standup_runs", nil},
+		{"facilitator_substitutions", nil},
+		{"facilitator_history", nil},
+		{"holidays", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			columns, err := tableColumns(DB, tt.table)
+			if err != nil {
+				t.Fatalf("tableColumns: %v", err)
+			}
+			if len(columns) == 0 {
+				t.Fatalf("table %s does not exist", tt.table)
+			}
+
+			have := make(map[string]bool)
+			for _, column := range columns {
+				have[column] = true
+			}
+			for _, column := range tt.columns {
+				if !have[column] {
+					t.Errorf("%s has no column %s", tt.table, column)
+				}
+			}
+		})
+	}
+
+	// The approval migration leaves a status CHECK that accepts the pending/rejected states
+	if _, err := DB.Exec("INSERT INTO users (google_chat_user_id, display_name) VALUES ('users/a', 'a')"); err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	for _, status := range []string{"pending", "active", "completed", "cancelled", "rejected"} {
+		if _, err := DB.Exec("INSERT INTO leaves (user_id, leave_type, start_date, end_date, status) VALUES (1, 'vacation', '2025-01-01', '2025-01-02', ?)", status); err != nil {
+			t.Errorf("insert leave with status %s: %v", status, err)
+		}
+	}
+	if _, err := DB.Exec("INSERT INTO leaves (user_id, leave_type, start_date, end_date, status) VALUES (1, 'vacation', '2025-01-01', '2025-01-02', 'bogus')"); err == nil {
+		t.Error("insert leave with status bogus succeeded, want the CHECK to reject it")
+	}
+}
@@ -6,54 +6,145 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID               int       `json:"id"`
-	GoogleChatUserID string    `json:"google_chat_user_id"`
-	DisplayName      string    `json:"display_name"`
-	Email            string    `json:"email"`
-	IsActive         bool      `json:"is_active"`
-	JoinedAt         time.Time `json:"joined_at"`
+	ID               int        `json:"id"`
+	GoogleChatUserID string     `json:"google_chat_user_id"`
+	DisplayName      string     `json:"display_name"`
+	Email            string     `json:"email"`
+	IsActive         bool       `json:"is_active"`
+	JoinedAt         time.Time  `json:"joined_at"`
 	LeftAt           *time.Time `json:"left_at,omitempty"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 // Leave represents a leave record for a user
 type Leave struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	LeaveType string    `json:"leave_type"` // 'sick', 'vacation', 'pto', 'personal', etc.
-	StartDate time.Time `json:"start_date"`
-	EndDate   time.Time `json:"end_date"`
-	Reason    string    `json:"reason"`
-	Status    string    `json:"status"` // 'active', 'completed', 'cancelled'
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	LeaveType  string    `json:"leave_type"` // 'sick', 'vacation', 'pto', 'personal', etc.
+	StartDate  time.Time `json:"start_date"`
+	EndDate    time.Time `json:"end_date"`
+	Reason     string    `json:"reason"`
+	Status     string    `json:"status"`      // 'pending', 'active', 'completed', 'cancelled', 'rejected' (enforced by a CHECK constraint)
+	DayPortion string    `json:"day_portion"` // 'full', 'morning' (before 12:00) or 'afternoon' (from 12:00), on each day of the leave
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Set when a pending leave is approved; leaves only count once they are active
+	ApprovedBy string     `json:"approved_by,omitempty"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
 }
 
 // Standup represents a standup meeting with its own schedule and roster
 type Standup struct {
-	ID                 int       `json:"id"`
-	Name               string    `json:"name"`
-	Message            string    `json:"message"`
-	RunAt              string    `json:"run_at"` // Time in HH:MM format (e.g., "09:00")
-	IsActive           bool      `json:"is_active"`
-	LastFacilitatorID  *int      `json:"last_facilitator_id,omitempty"`
-	CreatedBy          string    `json:"created_by"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ID                     int       `json:"id"`
+	Name                   string    `json:"name"`
+	Message                string    `json:"message"`
+	RunAt                  string    `json:"run_at"` // Time in HH:MM format (e.g., "09:00")
+	IsActive               bool      `json:"is_active"`
+	LastFacilitatorID      *int      `json:"last_facilitator_id,omitempty"`
+	NominatedFacilitatorID *int      `json:"nominated_facilitator_id,omitempty"` // Facilitates the next send instead of the rotation, for one turn
+	OwnerUserID            *int      `json:"owner_user_id,omitempty"`
+	WebhookURL             string    `json:"webhook_url,omitempty"`  // Overrides GOOGLE_CHAT_WEBHOOK_URL when set
+	WebhookURLs            []string  `json:"webhook_urls,omitempty"` // Extra webhooks the reminder is also posted to (stored as a JSON array)
+	Membership             string    `json:"membership"`             // 'explicit' (standup_members) or 'all_active' (every active user)
+	SkipWhenAlone          bool      `json:"skip_when_alone"`        // Skip the reminder when only one member is eligible
+	ShowReturning          bool      `json:"show_returning"`         // List members whose leave ends today as returning tomorrow
+	FullTeamMessage        string    `json:"full_team_message"`      // Line shown when nobody is on leave (empty = omit)
+	ManualSendRotates      bool      `json:"manual_send_rotates"`    // Whether "send now" advances the rotation by default
+	Timezone               string    `json:"timezone"`               // IANA zone overriding TIMEZONE for this standup ("" = global default)
+	EffectiveTimezone      string    `json:"effective_timezone"`     // Timezone, or the global TIMEZONE when empty (not stored)
+	FacilitatorOnly        bool      `json:"facilitator_only"`       // Reminder only @-mentions today's facilitator, without team or leave sections
+	DaysOfWeek             string    `json:"days_of_week"`           // Days the reminder fires, e.g. "MON,WED,FRI" ("" = every day)
+	DailyThread            bool      `json:"daily_thread"`           // Post each day's reminder into its own thread, keyed by standup and date
+	EmptyRetryMinutes      int       `json:"empty_retry_minutes"`    // Re-check once this many minutes after a scheduled send finds nobody eligible (0 = skip the day)
+	MessageFormat          string    `json:"message_format"`         // 'text' (plain reminder), 'card' (cardsV2 reminder with a rotate button) or 'status_card' (per-member team board)
+	Template               string    `json:"template"`               // Go text/template for the text reminder; empty uses the built-in format
+	MentionFacilitator     bool      `json:"mention_facilitator"`    // @-mention today's facilitator in text reminders (by google_chat_user_id)
+	ThreadKey              string    `json:"thread_key"`             // Google Chat thread every reminder is posted into ("" = main timeline, or daily_thread)
+	CreatedBy              string    `json:"created_by"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
 }
 
+// Leave day portions
+const (
+	DayPortionFull      = "full"
+	DayPortionMorning   = "morning"
+	DayPortionAfternoon = "afternoon"
+)
+
+// Standup membership modes
+const (
+	MembershipExplicit  = "explicit"
+	MembershipAllActive = "all_active"
+)
+
+// Standup reminder message formats
+const (
+	MessageFormatText       = "text"
+	MessageFormatCard       = "card"
+	MessageFormatStatusCard = "status_card"
+)
+
 // StandupMember represents a user assigned to a standup meeting
 type StandupMember struct {
-	StandupID int       `json:"standup_id"`
-	UserID    int       `json:"user_id"`
-	CreatedAt time.Time `json:"created_at"`
+	StandupID     int       `json:"standup_id"`
+	UserID        int       `json:"user_id"`
+	Alias         string    `json:"alias,omitempty"` // Name shown in this standup's reminders instead of display_name
+	CanFacilitate bool      `json:"can_facilitate"`  // Whether the member takes facilitation turns in this standup
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 // StandupWithMembers represents a standup with its assigned members
 type StandupWithMembers struct {
 	Standup
-	Members           []User `json:"members"`
-	LastFacilitator   *User  `json:"last_facilitator,omitempty"`
-	CurrentFacilitator *User  `json:"current_facilitator,omitempty"` // Dynamically calculated, not from DB
+	Members            []User     `json:"members"`
+	LastFacilitator    *User      `json:"last_facilitator,omitempty"`
+	CurrentFacilitator *User      `json:"current_facilitator,omitempty"` // Dynamically calculated, not from DB
+	RotationReset      bool       `json:"rotation_reset,omitempty"`      // Last facilitator is no longer a member, so rotation restarts from the top
+	NextRunAt          *time.Time `json:"next_run_at"`                   // Next scheduled send after weekend/holiday skips; null when not scheduled
+}
+
+// StandupRun records the outcome of a single reminder attempt for a standup
+type StandupRun struct {
+	ID            int       `json:"id"`
+	StandupID     int       `json:"standup_id"`
+	RunDate       string    `json:"run_date"`              // YYYY-MM-DD in the team's timezone
+	Status        string    `json:"status"`                // 'sent', 'skipped', 'failed', 'snoozed', 'attempting'
+	SkipReason    string    `json:"skip_reason,omitempty"` // Set when status is 'skipped'
+	Trigger       string    `json:"trigger"`               // 'scheduled', 'manual', 'snooze', 'forced'
+	FacilitatorID *int      `json:"facilitator_id,omitempty"`
+	Detail        string    `json:"detail,omitempty"`
+	SentAt        time.Time `json:"sent_at"`
+}
+
+// FacilitatorSubstitution hands a member's facilitation turns to another member for a date range.
+// The substitute runs the standup, but the rotation still counts the turn as the original member's.
+type FacilitatorSubstitution struct {
+	ID               int       `json:"id"`
+	StandupID        int       `json:"standup_id"`
+	OriginalUserID   int       `json:"original_user_id"`
+	SubstituteUserID int       `json:"substitute_user_id"`
+	StartDate        string    `json:"start_date"` // YYYY-MM-DD, inclusive
+	EndDate          string    `json:"end_date"`   // YYYY-MM-DD, inclusive
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// FacilitatorHistoryEntry records who facilitated a standup's sent reminder on a given day
+type FacilitatorHistoryEntry struct {
+	ID            int       `json:"id"`
+	StandupID     int       `json:"standup_id"`
+	UserID        int       `json:"user_id"`
+	FacilitatedOn string    `json:"facilitated_on"` // YYYY-MM-DD
+	CreatedAt     time.Time `json:"created_at"`
+	User          *User     `json:"user,omitempty"`
+}
+
+// Holiday is a company holiday on which no standup reminders are sent
+type Holiday struct {
+	ID        int       `json:"id"`
+	Date      string    `json:"date"` // YYYY-MM-DD, in each standup's timezone
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
 }
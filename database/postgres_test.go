@@ -0,0 +1,107 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/lib/pq/pqerror"
+)
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"no placeholders", "SELECT 1", "SELECT 1"},
+		{"numbered in order", "SELECT id FROM users WHERE id = ? AND is_active = ?", "SELECT id FROM users WHERE id = $1 AND is_active = $2"},
+		{"quoted string", "UPDATE standups SET message = 'Ready?' WHERE id = ?", "UPDATE standups SET message = 'Ready?' WHERE id = $1"},
+		{"escaped quote", "SELECT 'it''s ?' WHERE a = ? AND b = ?", "SELECT 'it''s ?' WHERE a = $1 AND b = $2"},
+		{"quoted identifier", `SELECT "odd?name" FROM t WHERE x = ?`, `SELECT "odd?name" FROM t WHERE x = $1`},
+		{"ten or more", "VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rebind(tt.query); got != tt.want {
+				t.Errorf("rebind(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDDL(t *testing.T) {
+	statement := `CREATE TABLE t (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    is_active BOOLEAN DEFAULT 1,
+    skip BOOLEAN DEFAULT 0,
+    name TEXT DEFAULT ''
+)`
+
+	tests := []struct {
+		driver string
+		want   string
+	}{
+		{DriverSQLite, statement},
+		{DriverPostgres, `CREATE TABLE t (
+    id SERIAL PRIMARY KEY,
+    is_active BOOLEAN DEFAULT TRUE,
+    skip BOOLEAN DEFAULT FALSE,
+    name TEXT DEFAULT ''
+)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			Driver = tt.driver
+			t.Cleanup(func() { Driver = DriverSQLite })
+
+			if got := ddl(statement); got != tt.want {
+				t.Errorf("ddl() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	openMemoryDB(t)
+	if err := RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+	insert := "INSERT INTO holidays (date, name) VALUES ('2025-12-25', 'Christmas')"
+	if _, err := DB.Exec(insert); err != nil {
+		t.Fatalf("insert holiday: %v", err)
+	}
+	_, sqliteDuplicate := DB.Exec(insert)
+	_, sqliteNotNull := DB.Exec("INSERT INTO holidays (date, name) VALUES (NULL, 'Nothing')")
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"sqlite unique", sqliteDuplicate, true},
+		{"sqlite not null", sqliteNotNull, false},
+		{"postgres unique", &pq.Error{Code: pqerror.UniqueViolation}, true},
+		{"postgres unique wrapped", fmt.Errorf("failed to create user: %w", &pq.Error{Code: pqerror.UniqueViolation}), true},
+		{"postgres foreign key", &pq.Error{Code: pqerror.ForeignKeyViolation}, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUniqueViolation(tt.err); got != tt.want {
+				t.Errorf("IsUniqueViolation(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInitDBRejectsUnknownDriver(t *testing.T) {
+	err := InitDB("mysql", "user@/standups")
+	if !errors.Is(err, ErrUnsupportedDriver) {
+		t.Errorf("InitDB(mysql) error = %v, want ErrUnsupportedDriver", err)
+	}
+}
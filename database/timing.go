@@ -0,0 +1,76 @@
+package database
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// SlowQueryThreshold is how long a timed query may take before it is logged as slow (0 disables)
+	SlowQueryThreshold = 200 * time.Millisecond
+	// LogQueryTimings logs the duration of every timed query (LOG_LEVEL=debug)
+	LogQueryTimings = false
+)
+
+// QueryStat aggregates the timings of one named query since startup
+type QueryStat struct {
+	Name      string  `json:"name"`
+	Count     int64   `json:"count"`
+	SlowCount int64   `json:"slow_count"`
+	TotalMs   float64 `json:"total_ms"`
+	MaxMs     float64 `json:"max_ms"`
+}
+
+var (
+	queryStatsMu sync.Mutex
+	queryStats   = make(map[string]*QueryStat)
+)
+
+// TimeQuery records how long a named query took. Call it deferred at the top of a query function:
+//
+//	defer database.TimeQuery("GetEligibleUsersForStandup", time.Now())
+func TimeQuery(name string, start time.Time) {
+	elapsed := time.Since(start)
+	ms := float64(elapsed) / float64(time.Millisecond)
+	slow := SlowQueryThreshold > 0 && elapsed >= SlowQueryThreshold
+
+	queryStatsMu.Lock()
+	stat, ok := queryStats[name]
+	if !ok {
+		stat = &QueryStat{Name: name}
+		queryStats[name] = stat
+	}
+	stat.Count++
+	stat.TotalMs += ms
+	if ms > stat.MaxMs {
+		stat.MaxMs = ms
+	}
+	if slow {
+		stat.SlowCount++
+	}
+	queryStatsMu.Unlock()
+
+	if slow {
+		slog.Warn("🐢 [SLOW QUERY]", "query", name, "elapsed", elapsed, "threshold", SlowQueryThreshold)
+	} else if LogQueryTimings {
+		slog.Info("[QUERY]", "query", name, "elapsed", elapsed)
+	}
+}
+
+// GetQueryStats returns the timings collected by TimeQuery, slowest total first
+func GetQueryStats() []QueryStat {
+	queryStatsMu.Lock()
+	defer queryStatsMu.Unlock()
+
+	stats := make([]QueryStat, 0, len(queryStats))
+	for _, stat := range queryStats {
+		stats = append(stats, *stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].TotalMs > stats[j].TotalMs
+	})
+	return stats
+}
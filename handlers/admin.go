@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"google-chat-bot/database"
+)
+
+// GetSchemaHandler reports the current schema version, pending migrations and table columns
+func GetSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	status, err := database.GetSchemaStatus()
+	if err != nil {
+		slog.Error("Failed to get schema status", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get schema status"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(status)
+}
+
+// MigrateHandler applies any pending migrations. Migrations are idempotent, so repeated calls are safe.
+func MigrateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	before, err := database.GetSchemaStatus()
+	if err != nil {
+		slog.Error("Failed to get schema status", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get schema status"})
+		return
+	}
+
+	if err := database.RunMigrations(); err != nil {
+		slog.Error("Failed to run migrations", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to run migrations"})
+		return
+	}
+
+	after, err := database.GetSchemaStatus()
+	if err != nil {
+		slog.Error("Failed to get schema status", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get schema status"})
+		return
+	}
+
+	slog.Info("Migrations run via admin API", "from_version", before.Version, "to_version", after.Version)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"applied": before.PendingMigrations,
+		"schema":  after,
+	})
+}
+
+// GetQueryStatsHandler reports per-query call counts and timings collected since startup
+func GetQueryStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(database.GetQueryStats())
+}
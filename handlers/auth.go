@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"google-chat-bot/config"
+)
+
+// RequireAPIKey rejects requests whose X-API-Key header is not one of the configured API_KEY
+// values with 401. With no keys configured every request is let through (local development).
+func RequireAPIKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(config.Config.APIKeys) > 0 && !validAPIKey(r.Header.Get("X-API-Key")) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Missing or invalid API key"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+// validAPIKey reports whether key matches any configured key, comparing in constant time
+func validAPIKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	valid := false
+	for _, configured := range config.Config.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(configured)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google-chat-bot/config"
+)
+
+// decodeJSON decodes a JSON request body into v. The body is capped at MAX_BODY_BYTES and
+// fields v does not have are rejected, so a misnamed field like "runAt" for "run_at" is an
+// error instead of being silently ignored.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, config.Config.MaxBodyBytes))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// writeBodyError writes the response for a request body decodeJSON rejected:
+// 413 when it was too large, 400 with the reason otherwise
+func writeBodyError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Request body too large (max %d bytes)", tooLarge.Limit)})
+		return
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body: " + strings.TrimPrefix(err.Error(), "json: ")})
+}
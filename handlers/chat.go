@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+	"google-chat-bot/integrations"
+	"google-chat-bot/services"
+)
+
+// ChatActionEvent is the part of a Google Chat interaction event the bot reads when a card
+// button is clicked. Parameters arrive in common.parameters and, for older clients, action.parameters.
+type ChatActionEvent struct {
+	Type  string `json:"type"`
+	Token string `json:"token"`
+	User  struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	Common struct {
+		InvokedFunction string            `json:"invokedFunction"`
+		Parameters      map[string]string `json:"parameters"`
+	} `json:"common"`
+	Action struct {
+		ActionMethodName string `json:"actionMethodName"`
+		Parameters       []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"parameters"`
+	} `json:"action"`
+}
+
+// parameter returns an action parameter by key, or "" when the event doesn't carry it
+func (e *ChatActionEvent) parameter(key string) string {
+	if value, ok := e.Common.Parameters[key]; ok {
+		return value
+	}
+	for _, param := range e.Action.Parameters {
+		if param.Key == key {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+// authenticateChatAction verifies a Chat event came from Google Chat: by its bearer JWT when
+// CHAT_AUDIENCE is set, otherwise by the legacy CHAT_VERIFICATION_TOKEN in the event body.
+// With neither configured every event is rejected, so the endpoint can't be called anonymously.
+func authenticateChatAction(r *http.Request, event *ChatActionEvent) error {
+	if config.Config.ChatAudience != "" {
+		return integrations.VerifyChatRequest(r)
+	}
+
+	if config.Config.ChatVerificationToken == "" ||
+		subtle.ConstantTimeCompare([]byte(event.Token), []byte(config.Config.ChatVerificationToken)) != 1 {
+		return integrations.ErrUnauthenticatedChatRequest
+	}
+	return nil
+}
+
+// ChatActionHandler handles the "Rotate facilitator" button on card reminders. The request must be
+// authenticated (see authenticateChatAction); the reply is a Chat message posted back into the space.
+func ChatActionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// Unknown fields are allowed here: Google Chat events carry far more than ChatActionEvent models
+	var event ChatActionEvent
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, config.Config.MaxBodyBytes)).Decode(&event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	if err := authenticateChatAction(r, &event); err != nil {
+		slog.Warn("Rejected chat action", "error", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Unauthenticated"})
+		return
+	}
+
+	if event.Type != "CARD_CLICKED" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Unsupported event type %q", event.Type)})
+		return
+	}
+
+	standupID, err := strconv.Atoi(event.parameter(services.ChatActionParamStandupID))
+	if err != nil || standupID <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	standup, err := services.GetStandupByID(standupID)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	eligibleUsers, err := database.GetEligibleUsersForStandup(standupID, services.StandupToday(standupID))
+	if err != nil || len(eligibleUsers) == 0 {
+		json.NewEncoder(w).Encode(map[string]string{"text": fmt.Sprintf("⚠️ Nobody is eligible to facilitate '%s' today.", standup.Name)})
+		return
+	}
+
+	// Rotate past whoever's slot is current, exactly like POST /api/standups/:id/facilitator/rotate
+	currentSlot, _, err := services.GetCurrentFacilitatorSlot(standupID, eligibleUsers)
+	if err == nil {
+		err = services.RotateFacilitator(standupID, currentSlot.ID)
+	}
+	if err != nil {
+		slog.Error("Failed to rotate facilitator from chat action", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to rotate facilitator: %v", err)})
+		return
+	}
+
+	_, facilitator, err := services.GetCurrentFacilitatorSlot(standupID, eligibleUsers)
+	if err == nil && facilitator != nil {
+		slog.Info("🔄 [CHAT ACTION] Standup rotated", "standup_id", standupID, "by", event.User.DisplayName, "facilitator", facilitator.DisplayName)
+		json.NewEncoder(w).Encode(map[string]string{
+			"text": fmt.Sprintf("🔄 %s rotated the facilitator for '%s'. Up next: %s", event.User.DisplayName, standup.Name, facilitator.DisplayName),
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"text": fmt.Sprintf("🔄 %s rotated the facilitator for '%s'.", event.User.DisplayName, standup.Name)})
+}
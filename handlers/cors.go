@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"google-chat-bot/config"
+)
+
+// corsExposedHeaders are response headers the API sets that a cross-origin page may read
+const corsExposedHeaders = "X-Total-Count, Content-Disposition, Idempotent-Replayed"
+
+// CORS lets the origins in CORS_ALLOWED_ORIGINS call the wrapped route from a browser. Preflight
+// OPTIONS requests from an allowed origin are answered here with 204, before RequireAPIKey, since
+// browsers never send the X-API-Key header on them. Requests from other origins (and every request
+// when no origins are configured) pass through without CORS headers, so browsers keep them same-origin.
+func CORS(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !allowedOrigin(origin) {
+			next(w, r)
+			return
+		}
+
+		// The response depends on the Origin, so caches must not share it between origins
+		w.Header().Add("Vary", "Origin")
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(config.Config.CORSAllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(config.Config.CORSAllowedHeaders, ", "))
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		next(w, r)
+	}
+}
+
+// allowedOrigin reports whether origin is listed in CORS_ALLOWED_ORIGINS, or "*" is
+func allowedOrigin(origin string) bool {
+	for _, allowed := range config.Config.CORSAllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"google-chat-bot/database"
+	"google-chat-bot/services"
+)
+
+// HolidayRequest represents the request to create or update a holiday
+type HolidayRequest struct {
+	Date string `json:"date"` // Format: YYYY-MM-DD
+	Name string `json:"name"`
+}
+
+// ImportHolidaysRequest represents a bulk holiday import: bare dates and/or named holidays
+type ImportHolidaysRequest struct {
+	Dates    []string         `json:"dates"`
+	Holidays []HolidayRequest `json:"holidays"`
+}
+
+// GetHolidaysHandler lists all holidays
+func GetHolidaysHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	holidays, err := services.GetHolidays()
+	if err != nil {
+		slog.Error("Failed to get holidays", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get holidays"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(holidays)
+}
+
+// GetHolidayHandler retrieves a single holiday by ID
+func GetHolidayHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parsePositiveID(strings.TrimPrefix(r.URL.Path, "/api/holidays/"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid holiday ID"})
+		return
+	}
+
+	holiday, err := services.GetHolidayByID(id)
+	if errors.Is(err, services.ErrHolidayNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Holiday not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to get holiday", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get holiday"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(holiday)
+}
+
+// CreateHolidayHandler adds a holiday
+func CreateHolidayHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req HolidayRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid date format (use YYYY-MM-DD)"})
+		return
+	}
+
+	holiday, err := services.CreateHoliday(req.Date, strings.TrimSpace(req.Name))
+	if errors.Is(err, services.ErrHolidayExists) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to create holiday", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to create holiday"})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(holiday)
+}
+
+// UpdateHolidayHandler changes a holiday's date and name
+func UpdateHolidayHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parsePositiveID(strings.TrimPrefix(r.URL.Path, "/api/holidays/"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid holiday ID"})
+		return
+	}
+
+	var req HolidayRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid date format (use YYYY-MM-DD)"})
+		return
+	}
+
+	err = services.UpdateHoliday(id, req.Date, strings.TrimSpace(req.Name))
+	switch {
+	case errors.Is(err, services.ErrHolidayNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Holiday not found"})
+		return
+	case errors.Is(err, services.ErrHolidayExists):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		slog.Error("Failed to update holiday", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to update holiday"})
+		return
+	}
+
+	holiday, err := services.GetHolidayByID(id)
+	if err != nil {
+		slog.Error("Failed to reload holiday after update", "holiday_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload holiday"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(holiday)
+}
+
+// DeleteHolidayHandler removes a holiday
+func DeleteHolidayHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parsePositiveID(strings.TrimPrefix(r.URL.Path, "/api/holidays/"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid holiday ID"})
+		return
+	}
+
+	err = services.DeleteHoliday(id)
+	if errors.Is(err, services.ErrHolidayNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Holiday not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to delete holiday", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to delete holiday"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"message": "Holiday deleted successfully"})
+}
+
+// ImportHolidaysHandler adds many holidays at once. The whole request is rejected if any date is
+// invalid; dates that already have a holiday are skipped.
+func ImportHolidaysHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req ImportHolidaysRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	holidays := make([]database.Holiday, 0, len(req.Dates)+len(req.Holidays))
+	for _, date := range req.Dates {
+		holidays = append(holidays, database.Holiday{Date: date})
+	}
+	for _, holiday := range req.Holidays {
+		holidays = append(holidays, database.Holiday{Date: holiday.Date, Name: strings.TrimSpace(holiday.Name)})
+	}
+
+	if len(holidays) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "dates or holidays is required"})
+		return
+	}
+
+	for _, holiday := range holidays {
+		if _, err := time.Parse("2006-01-02", holiday.Date); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid date " + holiday.Date + " (use YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	imported, skipped, err := services.ImportHolidays(holidays)
+	if err != nil {
+		slog.Error("Failed to import holidays", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to import holidays"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Holidays imported successfully",
+		"imported": imported,
+		"skipped":  skipped,
+	})
+}
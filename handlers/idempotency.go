@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"google-chat-bot/config"
+	"google-chat-bot/services"
+)
+
+// maxIdempotencyKeyLength bounds the Idempotency-Key header (UUIDs and similar fit easily)
+const maxIdempotencyKeyLength = 255
+
+// Idempotent makes a create handler safe to retry: when a request carries an Idempotency-Key
+// header, its successful (2xx) response is stored under the key and endpoint, and a later
+// request with the same key gets that response back, marked Idempotent-Replayed, instead of
+// creating another row. A repeat that arrives while the first request is still running gets 409,
+// and reusing a key with a different body gets 422. Failed requests don't keep the key, so the
+// client can fix the request and retry with it. Requests without the header pass straight through.
+func Idempotent(endpoint string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get("Idempotency-Key"))
+		if key == "" {
+			next(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+
+		// The body is read up front to fingerprint it, then handed on to the handler
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, config.Config.MaxBodyBytes))
+		if err != nil {
+			writeBodyError(w, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		hash := sha256.Sum256(body)
+
+		stored, err := services.BeginIdempotentRequest(key, endpoint, hex.EncodeToString(hash[:]))
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+				w.WriteHeader(http.StatusConflict)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			case errors.Is(err, services.ErrIdempotencyKeyReused):
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			default:
+				slog.Error("Failed to check idempotency key", "error", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": "Failed to check idempotency key"})
+			}
+			return
+		}
+
+		if stored != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.Body)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			// Free the key when the handler failed (or panicked), so a retry runs again
+			if !completed {
+				if err := services.ReleaseIdempotentRequest(key, endpoint); err != nil {
+					slog.Error("Failed to release idempotency key", "error", err)
+				}
+			}
+		}()
+
+		next(rec, r)
+
+		if rec.status >= 200 && rec.status < 300 {
+			err := services.CompleteIdempotentRequest(key, endpoint, services.IdempotentResponse{
+				StatusCode: rec.status,
+				Body:       rec.body.Bytes(),
+			})
+			if err != nil {
+				slog.Error("Failed to store idempotent response", "error", err)
+				return
+			}
+			completed = true
+		}
+	}
+}
+
+// responseRecorder passes a response through while keeping its status and body
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"encoding/csv"
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"google-chat-bot/database"
 	"google-chat-bot/services"
 )
 
@@ -18,6 +21,8 @@ type CreateLeaveRequest struct {
 	StartDate string `json:"start_date"` // Format: YYYY-MM-DD
 	EndDate   string `json:"end_date"`   // Format: YYYY-MM-DD
 	Reason    string `json:"reason"`
+	// Optional: "full" (default), "morning" or "afternoon"
+	DayPortion string `json:"day_portion"`
 }
 
 // UpdateLeaveRequest represents the request to update a leave
@@ -26,9 +31,22 @@ type UpdateLeaveRequest struct {
 	StartDate string `json:"start_date"` // Format: YYYY-MM-DD
 	EndDate   string `json:"end_date"`   // Format: YYYY-MM-DD
 	Reason    string `json:"reason"`
+	// Optional: "full", "morning" or "afternoon" (omitted = unchanged)
+	DayPortion string `json:"day_portion"`
 }
 
-// GetLeavesHandler retrieves all leaves or filters by status
+// ApproveLeaveRequest represents the request to approve a pending leave
+type ApproveLeaveRequest struct {
+	ApprovedBy string `json:"approved_by"` // Who approved the leave, e.g. the manager's name or email
+}
+
+// LeaveResponse is a created or approved leave plus any non-blocking coverage warnings
+type LeaveResponse struct {
+	*database.Leave
+	Warnings []services.CoverageWarning `json:"warnings,omitempty"`
+}
+
+// GetLeavesHandler retrieves all leaves, or filters by user, status or leaves in effect today
 func GetLeavesHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -40,34 +58,79 @@ func GetLeavesHandler(w http.ResponseWriter, r *http.Request) {
 	// Check for filters
 	activeOnly := r.URL.Query().Get("active") == "true"
 	userIDStr := r.URL.Query().Get("user_id")
+	status := r.URL.Query().Get("status")
+
+	// active=true means "in effect today", which is narrower than status=active (future leaves
+	// are active too), so the two are not combined
+	if status != "" {
+		if !services.ValidLeaveStatus(status) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "status must be pending, active, completed, cancelled or rejected"})
+			return
+		}
+		if activeOnly || userIDStr != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "status cannot be combined with active or user_id"})
+			return
+		}
+	}
+
+	page, err := parsePagination(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
 
 	var leaves interface{}
-	var err error
+	var total int
 
 	if userIDStr != "" {
 		// Get leaves for specific user
-		userID, err := strconv.Atoi(userIDStr)
-		if err != nil {
+		userID, convErr := parsePositiveID(userIDStr)
+		if convErr != nil {
 			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user_id"})
 			return
 		}
 		leaves, err = services.GetLeavesByUserID(userID)
+		// Per-user lists are small, so they are always returned unpaginated
+		page = nil
+	} else if status != "" {
+		// Get leaves in one status, e.g. cancelled ones for auditing
+		if page != nil {
+			leaves, total, err = services.GetLeavesByStatusPaginated(status, page.Limit, page.Offset)
+		} else {
+			leaves, err = services.GetLeavesByStatus(status)
+		}
 	} else if activeOnly {
 		// Get only active leaves
-		leaves, err = services.GetActiveLeaves()
+		if page != nil {
+			leaves, total, err = services.GetActiveLeavesPaginated(page.Limit, page.Offset)
+		} else {
+			leaves, err = services.GetActiveLeaves()
+		}
 	} else {
 		// Get all leaves
-		leaves, err = services.GetAllLeaves()
+		if page != nil {
+			leaves, total, err = services.GetAllLeavesPaginated(page.Limit, page.Offset)
+		} else {
+			leaves, err = services.GetAllLeaves()
+		}
 	}
 
 	if err != nil {
-		log.Printf("Failed to get leaves: %v", err)
+		slog.Error("Failed to get leaves", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get leaves"})
 		return
 	}
 
+	if page != nil {
+		writePaginatedResponse(w, leaves, total, page)
+		return
+	}
+
 	json.NewEncoder(w).Encode(leaves)
 }
 
@@ -80,7 +143,7 @@ func GetLeaveHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Extract leave ID from URL path
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/leaves/")
-	id, err := strconv.Atoi(idStr)
+	id, err := parsePositiveID(idStr)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid leave ID"})
@@ -91,7 +154,7 @@ func GetLeaveHandler(w http.ResponseWriter, r *http.Request) {
 
 	leave, err := services.GetLeaveByID(id)
 	if err != nil {
-		log.Printf("Failed to get leave: %v", err)
+		slog.Error("Failed to get leave", "error", err)
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Leave not found"})
 		return
@@ -108,9 +171,8 @@ func CreateLeaveHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req CreateLeaveRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
@@ -143,18 +205,30 @@ func CreateLeaveHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.DayPortion != "" && !services.ValidDayPortion(req.DayPortion) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidDayPortion.Error()})
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 
-	leave, err := services.CreateLeave(req.UserID, req.LeaveType, startDate, endDate, req.Reason)
+	leave, err := services.CreateLeave(req.UserID, req.LeaveType, startDate, endDate, req.Reason, req.DayPortion)
 	if err != nil {
-		log.Printf("Failed to create leave: %v", err)
+		slog.Error("Failed to create leave", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to create leave"})
 		return
 	}
 
+	// Warn (without blocking) about standups the leave would leave with no eligible facilitator
+	warnings, err := services.StandupsLeftEmptyByLeave(req.UserID, startDate, endDate)
+	if err != nil {
+		slog.Error("Failed to check leave coverage", "error", err)
+	}
+
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(leave)
+	json.NewEncoder(w).Encode(LeaveResponse{Leave: leave, Warnings: warnings})
 }
 
 // UpdateLeaveHandler updates an existing leave
@@ -166,7 +240,7 @@ func UpdateLeaveHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Extract leave ID from URL path
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/leaves/")
-	id, err := strconv.Atoi(idStr)
+	id, err := parsePositiveID(idStr)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid leave ID"})
@@ -174,9 +248,8 @@ func UpdateLeaveHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req UpdateLeaveRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
@@ -195,18 +268,31 @@ func UpdateLeaveHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.DayPortion != "" && !services.ValidDayPortion(req.DayPortion) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidDayPortion.Error()})
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 
-	err = services.UpdateLeave(id, req.LeaveType, startDate, endDate, req.Reason)
+	err = services.UpdateLeave(id, req.LeaveType, startDate, endDate, req.Reason, req.DayPortion)
 	if err != nil {
-		log.Printf("Failed to update leave: %v", err)
+		slog.Error("Failed to update leave", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to update leave"})
 		return
 	}
 
 	// Return updated leave
-	leave, _ := services.GetLeaveByID(id)
+	leave, err := services.GetLeaveByID(id)
+	if err != nil {
+		slog.Error("Failed to reload leave after update", "leave_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload leave"})
+		return
+	}
+
 	json.NewEncoder(w).Encode(leave)
 }
 
@@ -219,7 +305,7 @@ func CancelLeaveHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Extract leave ID from URL path
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/leaves/")
-	id, err := strconv.Atoi(idStr)
+	id, err := parsePositiveID(idStr)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid leave ID"})
@@ -229,12 +315,428 @@ func CancelLeaveHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	err = services.CancelLeave(id)
+	if errors.Is(err, services.ErrLeaveNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Leave not found"})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidLeaveTransition) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
 	if err != nil {
-		log.Printf("Failed to cancel leave: %v", err)
+		slog.Error("Failed to cancel leave", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to cancel leave"})
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"message": "Leave cancelled successfully"})
+	leave, err := services.GetLeaveByID(id)
+	if err != nil {
+		slog.Error("Failed to reload leave after cancellation", "leave_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload leave"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(leave)
+}
+
+// PurgeLeavesHandler permanently deletes completed/cancelled/rejected leaves that ended before a cutoff date
+func PurgeLeavesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	beforeStr := r.URL.Query().Get("before")
+	if beforeStr == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "before is required (use YYYY-MM-DD)"})
+		return
+	}
+
+	before, err := time.Parse("2006-01-02", beforeStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid before format (use YYYY-MM-DD)"})
+		return
+	}
+
+	// Default to all terminal statuses; active leaves can never be purged
+	statuses := services.PurgeableLeaveStatuses
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		statuses = strings.Split(statusStr, ",")
+		for i := range statuses {
+			statuses[i] = strings.TrimSpace(statuses[i])
+			if !services.IsPurgeableLeaveStatus(statuses[i]) {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "status must be completed, cancelled and/or rejected"})
+				return
+			}
+		}
+	}
+
+	removed, err := services.PurgeLeaves(before, statuses)
+	if err != nil {
+		slog.Error("Failed to purge leaves", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to purge leaves"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Leaves purged successfully",
+		"removed": removed,
+	})
+}
+
+// GetLeaveCalendarHandler returns who is on leave on each day of a month (?month=YYYY-MM,
+// default the current month), as a map of date to users
+func GetLeaveCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	month, _ := time.Parse("2006-01-02", services.Today())
+	if monthStr := r.URL.Query().Get("month"); monthStr != "" {
+		var err error
+		month, err = time.Parse("2006-01", monthStr)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid month format (use YYYY-MM)"})
+			return
+		}
+	}
+
+	calendar, err := services.GetLeaveCalendar(month)
+	if err != nil {
+		slog.Error("Failed to get leave calendar", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get leave calendar"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(calendar)
+}
+
+// leaveExportHeader is the header row of a leave CSV export
+var leaveExportHeader = []string{
+	"id", "user_id", "display_name", "leave_type", "start_date", "end_date",
+	"day_portion", "status", "reason", "created_at",
+}
+
+// ExportLeavesHandler streams leaves as a CSV download, filtered by user_id, active and a from/to
+// date range (leaves overlapping the range)
+func ExportLeavesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "csv" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "format must be csv"})
+		return
+	}
+
+	filter := services.LeaveExportFilter{ActiveOnly: query.Get("active") == "true"}
+	var err error
+	if userIDStr := query.Get("user_id"); userIDStr != "" {
+		filter.UserID, err = parsePositiveID(userIDStr)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user_id"})
+			return
+		}
+	}
+	if fromStr := query.Get("from"); fromStr != "" {
+		filter.From, err = time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid from format (use YYYY-MM-DD)"})
+			return
+		}
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		filter.To, err = time.Parse("2006-01-02", toStr)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid to format (use YYYY-MM-DD)"})
+			return
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "to must not be before from"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="leaves-`+services.Today()+`.csv"`)
+
+	// Rows are written as they are read; the csv.Writer flushes to the response whenever its
+	// buffer fills, so once the first rows are out an error can only be logged
+	writer := csv.NewWriter(w)
+	writer.Write(leaveExportHeader)
+	err = services.ExportLeaves(filter, func(leave services.LeaveExportRow) error {
+		return writer.Write([]string{
+			strconv.Itoa(leave.ID),
+			strconv.Itoa(leave.UserID),
+			leave.DisplayName,
+			leave.LeaveType,
+			leave.StartDate.Format("2006-01-02"),
+			leave.EndDate.Format("2006-01-02"),
+			leave.DayPortion,
+			leave.Status,
+			leave.Reason,
+			leave.CreatedAt.Format(time.RFC3339),
+		})
+	})
+	writer.Flush()
+	if err == nil {
+		err = writer.Error()
+	}
+	if err != nil {
+		slog.Error("Failed to export leaves", "error", err)
+	}
+}
+
+// ApproveLeaveHandler approves a pending leave so it takes effect, returning the leave with
+// warnings about standups it leaves without an eligible facilitator
+func ApproveLeaveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract leave ID from URL: /api/leaves/:id/approve
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid leave ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req ApproveLeaveRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	err = services.ApproveLeave(id, req.ApprovedBy)
+	switch {
+	case errors.Is(err, services.ErrApproverRequired):
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrLeaveNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Leave not found"})
+		return
+	case errors.Is(err, services.ErrInvalidLeaveTransition):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		slog.Error("Failed to approve leave", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to approve leave"})
+		return
+	}
+
+	leave, err := services.GetLeaveByID(id)
+	if err != nil {
+		slog.Error("Failed to reload leave after approval", "leave_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload leave"})
+		return
+	}
+
+	// Warn (without blocking) about standups left with no eligible facilitator
+	warnings, err := services.StandupsLeftEmptyByLeave(leave.UserID, leave.StartDate, leave.EndDate)
+	if err != nil {
+		slog.Error("Failed to check leave coverage", "error", err)
+	}
+
+	json.NewEncoder(w).Encode(LeaveResponse{Leave: leave, Warnings: warnings})
+}
+
+// RejectLeaveHandler rejects a pending leave
+func RejectLeaveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract leave ID from URL: /api/leaves/:id/reject
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid leave ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = services.RejectLeave(id)
+	switch {
+	case errors.Is(err, services.ErrLeaveNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Leave not found"})
+		return
+	case errors.Is(err, services.ErrInvalidLeaveTransition):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		slog.Error("Failed to reject leave", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reject leave"})
+		return
+	}
+
+	leave, err := services.GetLeaveByID(id)
+	if err != nil {
+		slog.Error("Failed to reload leave after rejection", "leave_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload leave"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(leave)
+}
+
+// ActivateLeaveHandler moves a leave to active
+func ActivateLeaveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract leave ID from URL: /api/leaves/:id/activate
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid leave ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = services.ActivateLeave(id)
+	switch {
+	case errors.Is(err, services.ErrLeaveNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Leave not found"})
+		return
+	case errors.Is(err, services.ErrLeaveAlreadyActive), errors.Is(err, services.ErrLeaveEnded),
+		errors.Is(err, services.ErrInvalidLeaveTransition):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		slog.Error("Failed to activate leave", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to activate leave"})
+		return
+	}
+
+	leave, err := services.GetLeaveByID(id)
+	if err != nil {
+		slog.Error("Failed to reload leave after activation", "leave_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload leave"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(leave)
+}
+
+// CompleteLeaveHandler marks an active leave as completed, e.g. when someone is back early,
+// so they are eligible again immediately
+func CompleteLeaveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract leave ID from URL: /api/leaves/:id/complete
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid leave ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = services.CompleteLeave(id)
+	switch {
+	case errors.Is(err, services.ErrLeaveNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Leave not found"})
+		return
+	case errors.Is(err, services.ErrInvalidLeaveTransition):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		slog.Error("Failed to complete leave", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to complete leave"})
+		return
+	}
+
+	leave, err := services.GetLeaveByID(id)
+	if err != nil {
+		slog.Error("Failed to reload leave after completion", "leave_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload leave"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(leave)
 }
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"google-chat-bot/database"
+	"google-chat-bot/services"
+)
+
+func TestCancelLeaveReturnsLeave(t *testing.T) {
+	setupTestDB(t)
+	alice := mustCreateUser(t, "alice")
+	start := time.Now().AddDate(0, 0, 7)
+	leave, err := services.CreateLeave(alice.ID, "vacation", start, start.AddDate(0, 0, 2), "Trip", "")
+	if err != nil {
+		t.Fatalf("CreateLeave: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		id         int
+		wantStatus int
+	}{
+		{"pending leave", leave.ID, http.StatusOK},
+		{"already cancelled", leave.ID, http.StatusConflict},
+		{"unknown leave", 999, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, CancelLeaveHandler, http.MethodDelete, "/api/leaves/"+strconv.Itoa(tt.id), nil)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got database.Leave
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.ID != tt.id || got.Status != services.LeaveStatusCancelled || got.Reason != "Trip" {
+				t.Errorf("leave = %+v, want leave %d cancelled", got, tt.id)
+			}
+		})
+	}
+}
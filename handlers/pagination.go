@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// defaultPageLimit is used when only offset is supplied
+	defaultPageLimit = 50
+	// maxPageLimit caps the page size a client may request
+	maxPageLimit = 200
+)
+
+// Pagination holds the limit/offset parsed from a list request
+type Pagination struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// PaginatedResponse is the envelope returned by paginated list endpoints
+type PaginatedResponse struct {
+	Items  interface{} `json:"items"`
+	Total  int         `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// parsePagination reads limit/offset query params.
+// It returns nil when neither param is present so callers can keep returning the plain list.
+func parsePagination(r *http.Request) (*Pagination, error) {
+	query := r.URL.Query()
+	limitStr := query.Get("limit")
+	offsetStr := query.Get("offset")
+
+	if limitStr == "" && offsetStr == "" {
+		return nil, nil
+	}
+
+	page := &Pagination{Limit: defaultPageLimit}
+
+	if limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("limit must be a positive integer")
+		}
+		if limit > maxPageLimit {
+			limit = maxPageLimit
+		}
+		page.Limit = limit
+	}
+
+	if offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("offset must be a non-negative integer")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+// newPaginatedResponse wraps a page of items in the pagination envelope
+func newPaginatedResponse(items interface{}, total int, page *Pagination) PaginatedResponse {
+	return PaginatedResponse{
+		Items:  items,
+		Total:  total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+}
+
+// writePaginatedResponse writes a page in the pagination envelope, with the total row
+// count also in the X-Total-Count header
+func writePaginatedResponse(w http.ResponseWriter, items interface{}, total int, page *Pagination) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	json.NewEncoder(w).Encode(newPaginatedResponse(items, total, page))
+}
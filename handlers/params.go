@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// parsePositiveID parses an ID taken from a path segment or query param,
+// rejecting zero and negative values so they never reach the database
+func parsePositiveID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if id <= 0 {
+		return 0, fmt.Errorf("id must be positive: %d", id)
+	}
+	return id, nil
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestParsePositiveID(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"1", 1, false},
+		{"42", 42, false},
+		{"0", 0, true},
+		{"-1", 0, true},
+		{"-0", 0, true},
+		{"", 0, true},
+		{"abc", 0, true},
+		{"1.5", 0, true},
+		{"99999999999999999999", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePositiveID(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parsePositiveID(%q) = %d, %v; want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestHandlersRejectNonPositiveIDs(t *testing.T) {
+	setupTestDB(t)
+
+	tests := []struct {
+		name      string
+		handler   http.HandlerFunc
+		method    string
+		target    string
+		wantError string
+	}{
+		{"standup zero", GetStandupHandler, http.MethodGet, "/api/standups/0", "Invalid standup ID"},
+		{"standup negative", GetStandupHandler, http.MethodGet, "/api/standups/-3", "Invalid standup ID"},
+		{"standup members negative", SetStandupMembersHandler, http.MethodPut, "/api/standups/-1/members", "Invalid standup ID"},
+		{"member user zero", MoveMemberUpHandler, http.MethodPost, "/api/standups/1/members/0/up", "Invalid user ID"},
+		{"leave zero", GetLeaveHandler, http.MethodGet, "/api/leaves/0", "Invalid leave ID"},
+		{"cancel leave negative", CancelLeaveHandler, http.MethodDelete, "/api/leaves/-7", "Invalid leave ID"},
+		{"user zero", GetUserHandler, http.MethodGet, "/api/roster/0", "Invalid user ID"},
+		{"reactivate negative", ReactivateUserHandler, http.MethodPost, "/api/roster/-2/reactivate", "Invalid user ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, tt.handler, tt.method, tt.target, map[string]interface{}{})
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body)
+			}
+
+			var body map[string]string
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if body["error"] != tt.wantError {
+				t.Errorf("error = %q, want %q", body["error"], tt.wantError)
+			}
+		})
+	}
+}
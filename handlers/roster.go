@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"encoding/csv"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"google-chat-bot/config"
 	"google-chat-bot/services"
 )
 
@@ -35,22 +40,40 @@ func GetRosterHandler(w http.ResponseWriter, r *http.Request) {
 	// Check if we should filter for active users only
 	activeOnly := r.URL.Query().Get("active") == "true"
 
-	var users interface{}
-	var err error
+	page, err := parsePagination(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
 
-	if activeOnly {
+	var users interface{}
+	var total int
+
+	if page != nil {
+		if activeOnly {
+			users, total, err = services.GetActiveUsersPaginated(page.Limit, page.Offset)
+		} else {
+			users, total, err = services.GetAllUsersPaginated(page.Limit, page.Offset)
+		}
+	} else if activeOnly {
 		users, err = services.GetActiveUsers()
 	} else {
 		users, err = services.GetAllUsers()
 	}
 
 	if err != nil {
-		log.Printf("Failed to get roster: %v", err)
+		slog.Error("Failed to get roster", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get roster"})
 		return
 	}
 
+	if page != nil {
+		writePaginatedResponse(w, users, total, page)
+		return
+	}
+
 	json.NewEncoder(w).Encode(users)
 }
 
@@ -63,7 +86,7 @@ func GetUserHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Extract user ID from URL path
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/roster/")
-	id, err := strconv.Atoi(idStr)
+	id, err := parsePositiveID(idStr)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
@@ -74,11 +97,45 @@ func GetUserHandler(w http.ResponseWriter, r *http.Request) {
 
 	user, err := services.GetUserByID(id)
 	if err != nil {
-		log.Printf("Failed to get user: %v", err)
+		slog.Error("Failed to get user", "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not found"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(user)
+}
+
+// GetUserByChatIDHandler retrieves a user by their Google Chat user ID, so inbound chat
+// events can be attributed to roster users
+func GetUserByChatIDHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract the Google Chat user ID from URL: /api/roster/by-chat-id/users/123
+	chatID := strings.TrimPrefix(r.URL.Path, "/api/roster/by-chat-id/")
+	if chatID == "" || chatID == r.URL.Path {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid Google Chat user ID"})
+		return
+	}
+
+	user, err := services.GetUserByGoogleChatID(chatID)
+	if errors.Is(err, services.ErrUserNotFound) {
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]string{"error": "User not found"})
 		return
 	}
+	if err != nil {
+		slog.Error("Failed to get user by chat ID", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get user"})
+		return
+	}
 
 	json.NewEncoder(w).Encode(user)
 }
@@ -91,9 +148,8 @@ func CreateUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req CreateUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
@@ -104,11 +160,39 @@ func CreateUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := services.ValidateEmail(req.Email); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 
 	user, err := services.CreateUser(req.GoogleChatUserID, req.DisplayName, req.Email)
+	if errors.Is(err, services.ErrUserExists) {
+		existing, lookupErr := services.GetUserByGoogleChatID(req.GoogleChatUserID)
+		if lookupErr != nil {
+			slog.Error("Failed to look up existing user", "google_chat_user_id", req.GoogleChatUserID, "error", lookupErr)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Failed to create user"})
+			return
+		}
+
+		// ?upsert=true refreshes the existing user's details instead of failing
+		if r.URL.Query().Get("upsert") == "true" {
+			upsertUser(w, existing.ID, req)
+			return
+		}
+
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":            fmt.Sprintf("User %s already exists with ID %d", req.GoogleChatUserID, existing.ID),
+			"existing_user_id": existing.ID,
+		})
+		return
+	}
 	if err != nil {
-		log.Printf("Failed to create user: %v", err)
+		slog.Error("Failed to create user", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to create user"})
 		return
@@ -118,6 +202,27 @@ func CreateUserHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(user)
 }
 
+// upsertUser updates an existing user's display name and email from a create request and
+// responds with the user (200, since nothing was created)
+func upsertUser(w http.ResponseWriter, id int, req CreateUserRequest) {
+	if err := services.UpdateUser(id, req.DisplayName, req.Email); err != nil {
+		slog.Error("Failed to upsert user", "user_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to update user"})
+		return
+	}
+
+	user, err := services.GetUserByID(id)
+	if err != nil {
+		slog.Error("Failed to reload user after upsert", "user_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload user"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(user)
+}
+
 // UpdateUserHandler updates an existing user
 func UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
@@ -127,7 +232,7 @@ func UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Extract user ID from URL path
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/roster/")
-	id, err := strconv.Atoi(idStr)
+	id, err := parsePositiveID(idStr)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
@@ -135,9 +240,14 @@ func UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req UpdateUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if err := services.ValidateEmail(req.Email); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
 
@@ -145,14 +255,21 @@ func UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 
 	err = services.UpdateUser(id, req.DisplayName, req.Email)
 	if err != nil {
-		log.Printf("Failed to update user: %v", err)
+		slog.Error("Failed to update user", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to update user"})
 		return
 	}
 
 	// Return updated user
-	user, _ := services.GetUserByID(id)
+	user, err := services.GetUserByID(id)
+	if err != nil {
+		slog.Error("Failed to reload user after update", "user_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload user"})
+		return
+	}
+
 	json.NewEncoder(w).Encode(user)
 }
 
@@ -165,7 +282,7 @@ func DeactivateUserHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Extract user ID from URL path
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/roster/")
-	id, err := strconv.Atoi(idStr)
+	id, err := parsePositiveID(idStr)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
@@ -176,7 +293,7 @@ func DeactivateUserHandler(w http.ResponseWriter, r *http.Request) {
 
 	err = services.DeactivateUser(id)
 	if err != nil {
-		log.Printf("Failed to deactivate user: %v", err)
+		slog.Error("Failed to deactivate user", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to deactivate user"})
 		return
@@ -200,7 +317,7 @@ func ReactivateUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := strconv.Atoi(pathParts[2])
+	id, err := parsePositiveID(pathParts[2])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
@@ -210,12 +327,223 @@ func ReactivateUserHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	err = services.ReactivateUser(id)
+	if errors.Is(err, services.ErrUserNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not found"})
+		return
+	}
 	if err != nil {
-		log.Printf("Failed to reactivate user: %v", err)
+		slog.Error("Failed to reactivate user", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reactivate user"})
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"message": "User reactivated successfully"})
+	user, err := services.GetUserByID(id)
+	if err != nil {
+		slog.Error("Failed to reload user after reactivation", "user_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload user"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(user)
+}
+
+// GetUserTodayHandler lists the standups a user is expected at today and whether they facilitate each
+func GetUserTodayHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract user ID from URL: /api/roster/:id/today
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(pathParts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	standups, err := services.GetUserStandupsToday(id)
+	if err != nil {
+		slog.Error("Failed to get today's standups for user", "user_id", id, "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not found"})
+		return
+	}
+
+	for i := range standups {
+		redactStandupWebhooks(&standups[i].Standup)
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id":  id,
+		"date":     services.Today(),
+		"standups": standups,
+	})
+}
+
+// GetUserUpcomingLeavesHandler returns a user's active leaves that have not ended yet, soonest
+// first, each flagged in_progress or not, optionally capped by ?limit=N
+func GetUserUpcomingLeavesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract user ID from URL: /api/roster/:id/leaves/upcoming
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(pathParts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "limit must be a positive integer"})
+			return
+		}
+	}
+
+	leaves, err := services.GetUpcomingLeavesForUser(id, limit)
+	if errors.Is(err, services.ErrUserNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to get upcoming leaves", "user_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get upcoming leaves"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(leaves)
+}
+
+// ImportRosterHandler creates or updates users from a CSV upload with a header row naming the
+// google_chat_user_id, display_name and (optional) email columns. Invalid rows are skipped and
+// reported; the rest are applied.
+func ImportRosterHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "text/csv" {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Content-Type must be text/csv"})
+		return
+	}
+
+	reader := csv.NewReader(http.MaxBytesReader(w, r.Body, config.Config.MaxBodyBytes))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeBodyError(w, err)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid CSV: " + err.Error()})
+		return
+	}
+	if len(records) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "CSV is empty"})
+		return
+	}
+
+	// Map the header row to column positions
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"google_chat_user_id", "display_name"} {
+		if _, ok := columns[required]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "CSV header must include " + required})
+			return
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	_, hasEmail := columns["email"]
+
+	rows := make([]services.RosterImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		row := services.RosterImportRow{
+			Line:             i + 2, // The header is line 1
+			GoogleChatUserID: field(record, "google_chat_user_id"),
+			DisplayName:      field(record, "display_name"),
+		}
+		if hasEmail {
+			email := field(record, "email")
+			row.Email = &email
+		}
+		rows = append(rows, row)
+	}
+
+	results, err := services.ImportRoster(rows)
+	if errors.Is(err, services.ErrTooManyImportRows) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to import roster", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to import roster"})
+		return
+	}
+
+	counts := map[string]int{
+		services.RosterImportCreated: 0,
+		services.RosterImportUpdated: 0,
+		services.RosterImportSkipped: 0,
+	}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"created": counts[services.RosterImportCreated],
+		"updated": counts[services.RosterImportUpdated],
+		"skipped": counts[services.RosterImportSkipped],
+		"results": results,
+	})
 }
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"google-chat-bot/database"
+	"google-chat-bot/services"
+)
+
+func TestReactivateUserReturnsUser(t *testing.T) {
+	setupTestDB(t)
+	alice := mustCreateUser(t, "alice")
+	if err := services.DeactivateUser(alice.ID); err != nil {
+		t.Fatalf("DeactivateUser: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		id         int
+		wantStatus int
+	}{
+		{"inactive user", alice.ID, http.StatusOK},
+		{"already active", alice.ID, http.StatusOK},
+		{"unknown user", 999, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, ReactivateUserHandler, http.MethodPost, "/api/roster/"+strconv.Itoa(tt.id)+"/reactivate", nil)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var user database.User
+			if err := json.NewDecoder(rec.Body).Decode(&user); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if user.ID != tt.id || !user.IsActive || user.LeftAt != nil {
+				t.Errorf("user = %+v, want user %d active with no left_at", user, tt.id)
+			}
+		})
+	}
+}
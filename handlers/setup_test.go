@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+	"google-chat-bot/services"
+)
+
+var testDBCount atomic.Int64
+
+// setupTestDB points the handlers at a fresh in-memory database with every migration applied
+// and a default configuration (UTC, weekends not skipped)
+func setupTestDB(t *testing.T) {
+	t.Helper()
+
+	config.Config = &config.AppConfig{
+		Timezone:               "UTC",
+		DisplayField:           "display_name",
+		SendConcurrency:        2,
+		HistoryPageSize:        20,
+		CardFormat:             "cardsV2",
+		MaxBodyBytes:           1 << 20,
+		IdempotencyKeyTTLHours: 24,
+	}
+
+	// A named shared-cache database lives as long as a connection to it is open, and every
+	// connection in the pool sees the same data
+	dsn := fmt.Sprintf("file:handlers_test_%d?mode=memory&cache=shared", testDBCount.Add(1))
+	if err := database.InitDB(database.DriverSQLite, dsn); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { database.CloseDB() })
+}
+
+// mustCreateUser adds an active user to the roster
+func mustCreateUser(t *testing.T, name string) *database.User {
+	t.Helper()
+
+	user, err := services.CreateUser("users/"+name, name, "")
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", name, err)
+	}
+	return user
+}
+
+// mustCreateStandup adds a standup with the given members, in order
+func mustCreateStandup(t *testing.T, name string, members ...*database.User) *database.Standup {
+	t.Helper()
+
+	standup, err := services.CreateStandup(name, "Standup time!", "09:00", "", "test")
+	if err != nil {
+		t.Fatalf("CreateStandup(%s): %v", name, err)
+	}
+
+	ids := make([]int, len(members))
+	for i, member := range members {
+		ids[i] = member.ID
+	}
+	if err := services.SetStandupMembers(standup.ID, ids); err != nil {
+		t.Fatalf("SetStandupMembers: %v", err)
+	}
+	return standup
+}
+
+// serve runs a handler on a request with an optional JSON body and returns the recorded response
+func serve(t *testing.T, handler http.HandlerFunc, method, target string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reader).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, target, &reader)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
@@ -2,31 +2,136 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"google-chat-bot/config"
 	"google-chat-bot/database"
+	"google-chat-bot/integrations"
 	"google-chat-bot/services"
 )
 
 // CreateStandupRequest represents the request to create a standup
 type CreateStandupRequest struct {
-	Name      string `json:"name"`
-	Message   string `json:"message"`
-	RunAt     string `json:"run_at"` // HH:MM format
-	CreatedBy string `json:"created_by"`
-	Members   []int  `json:"members"` // User IDs
+	Name       string `json:"name"`
+	Message    string `json:"message"`
+	RunAt      string `json:"run_at"` // HH:MM format
+	CreatedBy  string `json:"created_by"`
+	Members    []int  `json:"members"`     // User IDs
+	WebhookURL string `json:"webhook_url"` // Optional, defaults to the global webhook
+	Membership string `json:"membership"`  // Optional: "explicit" (default) or "all_active"
+	// Optional: extra webhooks the reminder is also posted to
+	WebhookURLs []string `json:"webhook_urls"`
+	// Optional: skip the reminder when only one member is eligible (default false)
+	SkipWhenAlone bool `json:"skip_when_alone"`
+	// Optional: list members returning from leave tomorrow (default false)
+	ShowReturning bool `json:"show_returning"`
+	// Optional: line shown when nobody is on leave, e.g. "👍 Full team in today" (default none)
+	FullTeamMessage string `json:"full_team_message"`
+	// Optional: whether manual sends advance the rotation (default true)
+	ManualSendRotates *bool `json:"manual_send_rotates"`
+	// Optional: IANA timezone for run_at, e.g. "Asia/Tokyo" (default: TIMEZONE)
+	Timezone string `json:"timezone"`
+	// Optional: reminder only @-mentions today's facilitator (default false)
+	FacilitatorOnly bool `json:"facilitator_only"`
+	// Optional: days the reminder fires, e.g. "MON,WED,FRI" or "1,3,5" (default every day)
+	DaysOfWeek string `json:"days_of_week"`
+	// Optional: user accountable for the standup, notified when a send fails (default none)
+	OwnerUserID int `json:"owner_user_id"`
+	// Optional: post each day's reminder into its own thread (default false)
+	DailyThread bool `json:"daily_thread"`
+	// Optional: when nobody is eligible at send time, re-check once after this many minutes (default 0, skip the day)
+	EmptyRetryMinutes int `json:"empty_retry_minutes"`
+	// Optional: "text" (default), "card" or "status_card"
+	MessageFormat string `json:"message_format"`
+	// Optional: Go text/template for the reminder (empty = built-in format)
+	Template string `json:"template"`
+	// Optional: @-mention today's facilitator in the reminder (default false)
+	MentionFacilitator bool `json:"mention_facilitator"`
+	// Optional: thread key every reminder is posted into (empty = main timeline)
+	ThreadKey string `json:"thread_key"`
 }
 
 // UpdateStandupRequest represents the request to update a standup
 type UpdateStandupRequest struct {
-	Name    string `json:"name"`
-	Message string `json:"message"`
-	RunAt   string `json:"run_at"` // HH:MM format
-	Members []int  `json:"members"` // User IDs (optional, for updating members)
+	Name       string  `json:"name"`
+	Message    string  `json:"message"`
+	RunAt      string  `json:"run_at"`      // HH:MM format
+	Members    *[]int  `json:"members"`     // User IDs: omitted or null = unchanged, [] = remove all, else replace
+	WebhookURL *string `json:"webhook_url"` // Optional, "" reverts to the global webhook
+	Membership *string `json:"membership"`  // Optional: "explicit" or "all_active"
+	// Optional: extra webhooks the reminder is also posted to ([] removes them)
+	WebhookURLs *[]string `json:"webhook_urls"`
+	// Optional: skip the reminder when only one member is eligible
+	SkipWhenAlone *bool `json:"skip_when_alone"`
+	// Optional: list members returning from leave tomorrow
+	ShowReturning *bool `json:"show_returning"`
+	// Optional: line shown when nobody is on leave ("" removes it)
+	FullTeamMessage *string `json:"full_team_message"`
+	// Optional: whether manual sends advance the rotation
+	ManualSendRotates *bool `json:"manual_send_rotates"`
+	// Optional: IANA timezone for run_at ("" reverts to TIMEZONE)
+	Timezone *string `json:"timezone"`
+	// Optional: reminder only @-mentions today's facilitator
+	FacilitatorOnly *bool `json:"facilitator_only"`
+	// Optional: days the reminder fires ("" = every day)
+	DaysOfWeek *string `json:"days_of_week"`
+	// Optional: user accountable for the standup (0 removes the owner)
+	OwnerUserID *int `json:"owner_user_id"`
+	// Optional: post each day's reminder into its own thread
+	DailyThread *bool `json:"daily_thread"`
+	// Optional: minutes before re-checking an empty send (0 disables the retry)
+	EmptyRetryMinutes *int `json:"empty_retry_minutes"`
+	// Optional: "text", "card" or "status_card"
+	MessageFormat *string `json:"message_format"`
+	// Optional: Go text/template for the reminder ("" = built-in format)
+	Template *string `json:"template"`
+	// Optional: @-mention today's facilitator in the reminder
+	MentionFacilitator *bool `json:"mention_facilitator"`
+	// Optional: thread key every reminder is posted into ("" = main timeline)
+	ThreadKey *string `json:"thread_key"`
+}
+
+// SendStandupReminderRequest represents the optional body of a manual reminder send
+type SendStandupReminderRequest struct {
+	FacilitatorID int   `json:"facilitator_id"` // Force this member as today's facilitator (optional)
+	Rotate        *bool `json:"rotate"`         // Advance the rotation after sending (default: the standup's manual_send_rotates)
+}
+
+// UpdateStandupMemberRequest represents the request to change a member's settings in one standup
+type UpdateStandupMemberRequest struct {
+	Alias         *string `json:"alias"`          // "" clears the alias
+	CanFacilitate *bool   `json:"can_facilitate"` // Whether the member takes facilitation turns
+}
+
+// ImportStandupMembersRequest represents a roster import for one standup
+type ImportStandupMembersRequest struct {
+	Members []services.MemberImportRow `json:"members"`
+}
+
+// StandupMemberResponse is a standup member with their settings in that standup
+type StandupMemberResponse struct {
+	database.User
+	Alias         string `json:"alias,omitempty"`
+	CanFacilitate bool   `json:"can_facilitate"`
+}
+
+// ImportStandupRequest is an exported standup plus who is importing it
+type ImportStandupRequest struct {
+	services.StandupExport
+	CreatedBy string `json:"created_by"`
+}
+
+// ImportStandupResponse is an imported standup plus the webhooks dropped because they were redacted
+type ImportStandupResponse struct {
+	*database.StandupWithMembers
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // GetStandupsHandler retrieves all standups or active standups only
@@ -38,40 +143,1604 @@ func GetStandupsHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
+	// Look up standups by webhook (used when rotating a webhook token)
+	if webhookURL := r.URL.Query().Get("webhook"); webhookURL != "" {
+		standups, err := services.GetStandupsByWebhookURL(webhookURL)
+		if err != nil {
+			slog.Error("Failed to get standups by webhook", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get standups"})
+			return
+		}
+
+		writeStandups(w, standups)
+		return
+	}
+
 	// Check if we should filter for active standups only
 	activeOnly := r.URL.Query().Get("active") == "true"
 
-	var standups interface{}
-	var err error
+	// Look up the standups a user belongs to (the "my standups" view)
+	memberIDStr, memberChatID := r.URL.Query().Get("member_id"), r.URL.Query().Get("member_chat_id")
+	if memberIDStr != "" || memberChatID != "" {
+		if memberIDStr != "" && memberChatID != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Use either member_id or member_chat_id, not both"})
+			return
+		}
+
+		var memberID int
+		if memberIDStr != "" {
+			id, err := parsePositiveID(memberIDStr)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "Invalid member_id"})
+				return
+			}
+			memberID = id
+		} else {
+			user, err := services.GetUserByGoogleChatID(memberChatID)
+			if errors.Is(err, services.ErrUserNotFound) {
+				// Someone not on the roster belongs to no standups
+				writeStandups(w, []database.Standup{})
+				return
+			}
+			if err != nil {
+				slog.Error("Failed to get user by chat id", "error", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get standups"})
+				return
+			}
+			memberID = user.ID
+		}
+
+		standups, err := services.GetStandupsByMember(memberID, activeOnly)
+		if err != nil {
+			slog.Error("Failed to get standups by member", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get standups"})
+			return
+		}
+
+		writeStandups(w, standups)
+		return
+	}
+
+	// Look up standups by schedule window (used to spot reminders bunching up on the webhook)
+	fromTime, toTime := r.URL.Query().Get("from_time"), r.URL.Query().Get("to_time")
+	if fromTime != "" || toTime != "" {
+		standups, err := services.GetStandupsByTimeRange(fromTime, toTime, activeOnly)
+		if errors.Is(err, services.ErrInvalidTimeRange) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to get standups by time range", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get standups"})
+			return
+		}
+
+		writeStandups(w, standups)
+		return
+	}
+
+	page, err := parsePagination(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	var standups []database.Standup
+	var total int
 
-	if activeOnly {
+	if page != nil {
+		if activeOnly {
+			standups, total, err = services.GetActiveStandupsPaginated(page.Limit, page.Offset)
+		} else {
+			standups, total, err = services.GetAllStandupsPaginated(page.Limit, page.Offset)
+		}
+	} else if activeOnly {
 		standups, err = services.GetActiveStandups()
 	} else {
 		standups, err = services.GetAllStandups()
 	}
 
 	if err != nil {
-		log.Printf("Failed to get standups: %v", err)
+		slog.Error("Failed to get standups", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get standups"})
 		return
 	}
 
-	json.NewEncoder(w).Encode(standups)
+	if page != nil {
+		for i := range standups {
+			redactStandupWebhooks(&standups[i])
+		}
+		writePaginatedResponse(w, standups, total, page)
+		return
+	}
+
+	writeStandups(w, standups)
+}
+
+// GetStandupHandler retrieves a single standup by ID with its members
+func GetStandupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL path
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/standups/")
+	// Remove /members suffix if present
+	idStr = strings.TrimSuffix(idStr, "/members")
+	id, err := parsePositiveID(idStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	standup, err := services.GetStandupWithMembers(id)
+	if err != nil {
+		slog.Error("Failed to get standup", "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	redactStandupWebhooks(&standup.Standup)
+	json.NewEncoder(w).Encode(standup)
+}
+
+// CreateStandupHandler creates a new standup
+func CreateStandupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CreateStandupRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	// Validate required fields
+	if req.Name == "" || req.Message == "" || req.RunAt == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "name, message, and run_at are required"})
+		return
+	}
+
+	if !services.ValidRunAt(req.RunAt) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidRunAt.Error()})
+		return
+	}
+
+	if req.Membership != "" && !validMembership(req.Membership) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidMembership.Error()})
+		return
+	}
+
+	if _, err := services.NormalizeDaysOfWeek(req.DaysOfWeek); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	if err := services.ValidateStandupOwner(req.OwnerUserID); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.EmptyRetryMinutes < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidEmptyRetry.Error()})
+		return
+	}
+
+	if req.MessageFormat != "" && !services.ValidMessageFormat(req.MessageFormat) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidMessageFormat.Error()})
+		return
+	}
+
+	if err := services.ValidateReminderTemplate(req.Template); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	standup, err := services.CreateStandupWithSettings(req.Name, req.Message, req.RunAt, req.Timezone, req.CreatedBy, services.StandupSettings{
+		Members:            req.Members,
+		WebhookURL:         req.WebhookURL,
+		WebhookURLs:        req.WebhookURLs,
+		Membership:         req.Membership,
+		SkipWhenAlone:      req.SkipWhenAlone,
+		ShowReturning:      req.ShowReturning,
+		FullTeamMessage:    req.FullTeamMessage,
+		ManualSendRotates:  req.ManualSendRotates,
+		FacilitatorOnly:    req.FacilitatorOnly,
+		DaysOfWeek:         req.DaysOfWeek,
+		OwnerUserID:        req.OwnerUserID,
+		DailyThread:        req.DailyThread,
+		EmptyRetryMinutes:  req.EmptyRetryMinutes,
+		MessageFormat:      req.MessageFormat,
+		Template:           req.Template,
+		MentionFacilitator: req.MentionFacilitator,
+		ThreadKey:          req.ThreadKey,
+	})
+	if errors.Is(err, services.ErrInvalidTimezone) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrStandupLimitReached) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to create standup", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to create standup"})
+		return
+	}
+
+	// Schedule the new standup
+	if err := services.RescheduleStandup(standup.ID); err != nil {
+		slog.Error("Failed to schedule standup", "error", err)
+	}
+
+	// Return standup with members
+	writeStandupWithMembers(w, standup.ID, http.StatusCreated)
+}
+
+// UpdateStandupHandler updates an existing standup
+func UpdateStandupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL path
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/standups/")
+	id, err := parsePositiveID(idStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	var req UpdateStandupRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if !services.ValidRunAt(req.RunAt) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidRunAt.Error()})
+		return
+	}
+
+	if req.Membership != nil && !validMembership(*req.Membership) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidMembership.Error()})
+		return
+	}
+
+	if req.DaysOfWeek != nil {
+		if _, err := services.NormalizeDaysOfWeek(*req.DaysOfWeek); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+	}
+
+	if req.OwnerUserID != nil {
+		if err := services.ValidateStandupOwner(*req.OwnerUserID); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+	}
+
+	if req.Members != nil {
+		if err := services.ValidateStandupMembers(*req.Members); err != nil {
+			writeStandupMembersError(w, err)
+			return
+		}
+	}
+
+	if req.EmptyRetryMinutes != nil && *req.EmptyRetryMinutes < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidEmptyRetry.Error()})
+		return
+	}
+
+	if req.MessageFormat != nil && !services.ValidMessageFormat(*req.MessageFormat) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidMessageFormat.Error()})
+		return
+	}
+
+	if req.Template != nil {
+		if err := services.ValidateReminderTemplate(*req.Template); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = services.UpdateStandup(id, req.Name, req.Message, req.RunAt, req.Timezone)
+	if errors.Is(err, services.ErrInvalidTimezone) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to update standup", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to update standup"})
+		return
+	}
+
+	// Replace members if provided; an empty list removes every member
+	if req.Members != nil {
+		if err := services.SetStandupMembers(id, *req.Members); err != nil {
+			writeStandupMembersError(w, err)
+			return
+		}
+	}
+
+	// Update per-standup webhook if provided
+	if req.WebhookURL != nil {
+		if err := services.SetStandupWebhookURL(id, *req.WebhookURL); err != nil {
+			slog.Error("Failed to update standup webhook", "error", err)
+		}
+	}
+
+	// Update extra webhooks if provided
+	if req.WebhookURLs != nil {
+		if err := services.SetStandupWebhookURLs(id, *req.WebhookURLs); err != nil {
+			slog.Error("Failed to update standup webhooks", "error", err)
+		}
+	}
+
+	// Update membership mode if provided
+	if req.Membership != nil {
+		if err := services.SetStandupMembership(id, *req.Membership); err != nil {
+			slog.Error("Failed to update standup membership", "error", err)
+		}
+	}
+
+	// Update skip_when_alone if provided
+	if req.SkipWhenAlone != nil {
+		if err := services.SetStandupSkipWhenAlone(id, *req.SkipWhenAlone); err != nil {
+			slog.Error("Failed to update standup skip_when_alone", "error", err)
+		}
+	}
+
+	// Update show_returning if provided
+	if req.ShowReturning != nil {
+		if err := services.SetStandupShowReturning(id, *req.ShowReturning); err != nil {
+			slog.Error("Failed to update standup show_returning", "error", err)
+		}
+	}
+
+	// Update full_team_message if provided
+	if req.FullTeamMessage != nil {
+		if err := services.SetStandupFullTeamMessage(id, *req.FullTeamMessage); err != nil {
+			slog.Error("Failed to update standup full_team_message", "error", err)
+		}
+	}
+
+	// Update days_of_week if provided
+	if req.DaysOfWeek != nil {
+		if err := services.SetStandupDaysOfWeek(id, *req.DaysOfWeek); err != nil {
+			slog.Error("Failed to update standup days_of_week", "error", err)
+		}
+	}
+
+	// Update facilitator_only if provided
+	if req.FacilitatorOnly != nil {
+		if err := services.SetStandupFacilitatorOnly(id, *req.FacilitatorOnly); err != nil {
+			slog.Error("Failed to update standup facilitator_only", "error", err)
+		}
+	}
+
+	// Update manual_send_rotates if provided
+	if req.ManualSendRotates != nil {
+		if err := services.SetStandupManualSendRotates(id, *req.ManualSendRotates); err != nil {
+			slog.Error("Failed to update standup manual_send_rotates", "error", err)
+		}
+	}
+
+	// Update owner if provided
+	if req.OwnerUserID != nil {
+		if err := services.SetStandupOwner(id, *req.OwnerUserID); err != nil {
+			slog.Error("Failed to update standup owner", "error", err)
+		}
+	}
+
+	// Update daily_thread if provided
+	if req.DailyThread != nil {
+		if err := services.SetStandupDailyThread(id, *req.DailyThread); err != nil {
+			slog.Error("Failed to update standup daily_thread", "error", err)
+		}
+	}
+
+	// Update empty_retry_minutes if provided
+	if req.EmptyRetryMinutes != nil {
+		if err := services.SetStandupEmptyRetryMinutes(id, *req.EmptyRetryMinutes); err != nil {
+			slog.Error("Failed to update standup empty_retry_minutes", "error", err)
+		}
+	}
+
+	// Update message_format if provided
+	if req.MessageFormat != nil {
+		if err := services.SetStandupMessageFormat(id, *req.MessageFormat); err != nil {
+			slog.Error("Failed to update standup message_format", "error", err)
+		}
+	}
+
+	// Update template if provided
+	if req.Template != nil {
+		if err := services.SetStandupTemplate(id, *req.Template); err != nil {
+			slog.Error("Failed to update standup template", "error", err)
+		}
+	}
+
+	// Update mention_facilitator if provided
+	if req.MentionFacilitator != nil {
+		if err := services.SetStandupMentionFacilitator(id, *req.MentionFacilitator); err != nil {
+			slog.Error("Failed to update standup mention_facilitator", "error", err)
+		}
+	}
+
+	// Update thread_key if provided
+	if req.ThreadKey != nil {
+		if err := services.SetStandupThreadKey(id, *req.ThreadKey); err != nil {
+			slog.Error("Failed to update standup thread_key", "error", err)
+		}
+	}
+
+	// Replace the standup's job with one for the updated schedule
+	if err := services.RescheduleStandup(id); err != nil {
+		slog.Error("Failed to reschedule standup", "error", err)
+	}
+
+	// Return updated standup with members
+	writeStandupWithMembers(w, id, http.StatusOK)
+}
+
+// DeleteStandupHandler deactivates a standup
+func DeleteStandupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL path
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/standups/")
+	id, err := parsePositiveID(idStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = services.DeleteStandup(id)
+	if err != nil {
+		slog.Error("Failed to delete standup", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to delete standup"})
+		return
+	}
+
+	// Remove the standup's job
+	services.UnscheduleStandup(id)
+
+	json.NewEncoder(w).Encode(map[string]string{"message": "Standup deleted successfully"})
+}
+
+// ReactivateStandupHandler brings a deactivated standup back and schedules it again
+func ReactivateStandupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/reactivate
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	err = services.ReactivateStandup(id)
+	if errors.Is(err, services.ErrStandupLimitReached) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to reactivate standup", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reactivate standup"})
+		return
+	}
+
+	if err := services.RescheduleStandup(id); err != nil {
+		slog.Error("Failed to schedule standup", "error", err)
+	}
+
+	writeStandupWithMembers(w, id, http.StatusOK)
+}
+
+// GetStandupMembersHandler retrieves all members of a standup
+func GetStandupMembersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/members
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeStandupMembers(w, id, http.StatusOK)
+}
+
+// writeStandupMembers writes a standup's members, with their standup settings, as JSON with the given status code
+func writeStandupMembers(w http.ResponseWriter, id int, status int) {
+	members, err := services.GetStandupMembers(id)
+	if err != nil {
+		slog.Error("Failed to get standup members", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get standup members"})
+		return
+	}
+
+	settings, err := services.GetStandupMemberSettings(id)
+	if err != nil {
+		slog.Error("Failed to get member settings", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get standup members"})
+		return
+	}
+
+	response := make([]StandupMemberResponse, len(members))
+	for i, member := range members {
+		response[i] = StandupMemberResponse{User: member, CanFacilitate: true}
+		if setting, ok := settings[member.ID]; ok {
+			response[i].Alias = setting.Alias
+			response[i].CanFacilitate = setting.CanFacilitate
+		}
+	}
+
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateStandupMemberHandler sets a member's alias and/or can_facilitate flag in one standup
+func UpdateStandupMemberHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract IDs from URL: /api/standups/:id/members/:user_id
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 5 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	userID, err := parsePositiveID(parts[4])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
+		return
+	}
+
+	var req UpdateStandupMemberRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	if req.Alias == nil && req.CanFacilitate == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "alias or can_facilitate is required"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if req.Alias != nil {
+		err = services.SetStandupMemberAlias(standupID, userID, strings.TrimSpace(*req.Alias))
+	}
+	if err == nil && req.CanFacilitate != nil {
+		err = services.SetStandupMemberCanFacilitate(standupID, userID, *req.CanFacilitate)
+	}
+	if errors.Is(err, services.ErrNotStandupMember) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to update standup member", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to update standup member"})
+		return
+	}
+
+	// Return the members with the updated settings
+	writeStandupMembers(w, standupID, http.StatusOK)
+}
+
+// ImportStandupMembersHandler adds or updates many members at once with their alias,
+// can_facilitate flag and position. Nothing is applied if any row is invalid.
+func ImportStandupMembersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/members/import
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	var req ImportStandupMembersRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	if len(req.Members) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "members is required"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	err = services.ImportStandupMembers(id, req.Members)
+	var importErr *services.MemberImportError
+	if errors.As(err, &importErr) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+			"rows":  importErr.Rows,
+		})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to import standup members", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to import standup members"})
+		return
+	}
+
+	writeStandupMembers(w, id, http.StatusOK)
+}
+
+// SetStandupMembersHandler replaces all members of a standup
+func SetStandupMembersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/members
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	var req struct {
+		Members []int `json:"members"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	if err := services.SetStandupMembers(id, req.Members); err != nil {
+		writeStandupMembersError(w, err)
+		return
+	}
+
+	writeStandupMembers(w, id, http.StatusOK)
+}
+
+// writeStandupMembersError answers a failed roster replacement: 400 when a user does not exist, 500 otherwise
+func writeStandupMembersError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrUserNotFound) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	slog.Error("Failed to set standup members", "error", err)
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(map[string]string{"error": "Failed to set standup members"})
+}
+
+// AddStandupMembersHandler appends one member ({"user_id": 1}) or several ({"user_ids": [1, 2]})
+// to the end of a standup's rotation without touching the existing members
+func AddStandupMembersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/members
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	var req struct {
+		UserID  int   `json:"user_id"`
+		UserIDs []int `json:"user_ids"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	userIDs := req.UserIDs
+	if req.UserID != 0 {
+		userIDs = append([]int{req.UserID}, userIDs...)
+	}
+	if len(userIDs) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "user_id or user_ids is required"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	err = services.AddStandupMembers(id, userIDs)
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrAlreadyStandupMember):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		slog.Error("Failed to add standup members", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to add standup members"})
+		return
+	}
+
+	writeStandupMembers(w, id, http.StatusCreated)
+}
+
+// SendStandupReminderHandler manually triggers a standup reminder
+func SendStandupReminderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/send
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	// Optional body: {"facilitator_id": N, "rotate": false}
+	var req SendStandupReminderRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(w, r, &req); err != nil && err != io.EOF {
+			writeBodyError(w, err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	opts := services.ReminderOptions{
+		FacilitatorID: req.FacilitatorID,
+		Rotate:        req.Rotate,
+	}
+
+	err = services.SendManualStandupReminder(id, opts)
+	if errors.Is(err, services.ErrNotStandupMember) || errors.Is(err, services.ErrUserNotEligible) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeSendError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"message": "Reminder sent successfully!"})
+}
+
+// writeSendError reports why a manual or forced send did not go out: 409 for a skipped send
+// (with its "reason") or an inactive standup, 502 when the webhook failed, 500 otherwise
+func writeSendError(w http.ResponseWriter, err error) {
+	var skipErr *services.SkipError
+	switch {
+	case errors.As(err, &skipErr):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error(), "reason": string(skipErr.Reason)})
+	case errors.Is(err, services.ErrStandupInactive):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrSendFailed):
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+	default:
+		slog.Error("Failed to send reminder", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to send reminder: %v", err)})
+	}
+}
+
+// ForceSendStandupReminderHandler sends a reminder now, bypassing today's weekend/single-member skips
+func ForceSendStandupReminderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/force-send
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	err = services.ForceSendStandupReminder(id)
+	if err != nil {
+		writeSendError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"message": "Reminder force-sent successfully!"})
+}
+
+// SetFacilitatorHandler sets the current facilitator for a standup
+func SetFacilitatorHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/facilitator
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	var req struct {
+		UserID int `json:"user_id"`
+		// Alternative to user_id for integrators that only know the Google Chat user
+		GoogleChatUserID string `json:"google_chat_user_id"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(standupID); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	if req.GoogleChatUserID != "" {
+		user, err := services.GetUserByGoogleChatID(req.GoogleChatUserID)
+		if errors.Is(err, services.ErrUserNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "User not found"})
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to look up user", "google_chat_user_id", req.GoogleChatUserID, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Failed to look up user"})
+			return
+		}
+
+		isMember, err := services.IsStandupMember(standupID, user.ID)
+		if err != nil {
+			slog.Error("Failed to check standup membership", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Failed to check standup membership"})
+			return
+		}
+		if !isMember {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": services.ErrNotStandupMember.Error()})
+			return
+		}
+
+		req.UserID = user.ID
+	}
+
+	err = services.SetLastFacilitator(standupID, req.UserID)
+	if err != nil {
+		slog.Error("Failed to set last facilitator", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to set facilitator: %v", err)})
+		return
+	}
+
+	writeStandupWithMembers(w, standupID, http.StatusOK)
+}
+
+// NominateFacilitatorHandler lets the current facilitator pick who goes next, for one turn
+func NominateFacilitatorHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/facilitator/nominate
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	var req struct {
+		UserID int `json:"user_id"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	if req.UserID <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "user_id is required"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = services.NominateFacilitator(standupID, req.UserID)
+	if errors.Is(err, services.ErrNotStandupMember) || errors.Is(err, services.ErrUserNotEligible) || errors.Is(err, services.ErrCannotFacilitate) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to nominate facilitator", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to nominate facilitator: %v", err)})
+		return
+	}
+
+	// Return updated standup with the nominee as current facilitator
+	writeStandupWithMembers(w, standupID, http.StatusOK)
+}
+
+// RotateFacilitatorHandler rotates to the next facilitator
+func RotateFacilitatorHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/facilitator/rotate
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	// Get eligible users
+	eligibleUsers, err := database.GetEligibleUsersForStandup(standupID, services.StandupToday(standupID))
+	if err != nil || len(eligibleUsers) == 0 {
+		slog.Error("Failed to get eligible users", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "No eligible users for standup"})
+		return
+	}
+
+	// Calculate whose turn it is (the slot, even when a substitute stands in)
+	currentSlot, _, err := services.GetCurrentFacilitatorSlot(standupID, eligibleUsers)
+	if err != nil {
+		slog.Error("Failed to get current facilitator", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to get current facilitator: %v", err)})
+		return
+	}
+
+	// Rotate by setting last_facilitator_id to current facilitator
+	err = services.RotateFacilitator(standupID, currentSlot.ID)
+	if err != nil {
+		slog.Error("Failed to rotate facilitator", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to rotate facilitator: %v", err)})
+		return
+	}
+
+	// Return updated standup with new facilitator
+	writeStandupWithMembers(w, standupID, http.StatusOK)
+}
+
+// SkipFacilitatorHandler passes the current facilitator's turn to the next eligible member
+// without sending a reminder
+func SkipFacilitatorHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/facilitator/skip
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(standupID); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	facilitator, err := services.SkipFacilitator(standupID)
+	if errors.Is(err, services.ErrNoEligibleUsers) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to skip facilitator", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to skip facilitator: %v", err)})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":             "Facilitator skipped successfully",
+		"current_facilitator": facilitator,
+	})
+}
+
+// MoveMemberUpHandler moves a member up in the display order
+func MoveMemberUpHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/members/:user_id/up
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 5 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	userID, err := parsePositiveID(parts[4])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = services.MoveMemberUp(standupID, userID)
+	if errors.Is(err, services.ErrNotStandupMember) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrDynamicMembership) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to move member up", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("%v", err)})
+		return
+	}
+
+	// Return updated standup with reordered members
+	writeStandupWithMembers(w, standupID, http.StatusOK)
+}
+
+// ReorderStandupMembersHandler sets the whole member order at once from an ordered list of user IDs
+func ReorderStandupMembersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/members/order
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 5 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	var req struct {
+		UserIDs []int `json:"user_ids"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(standupID); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	err = services.ReorderStandupMembers(standupID, req.UserIDs)
+	switch {
+	case errors.Is(err, services.ErrMemberOrderMismatch):
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrDynamicMembership):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		slog.Error("Failed to reorder members", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reorder members"})
+		return
+	}
+
+	// Return updated standup with reordered members
+	writeStandupWithMembers(w, standupID, http.StatusOK)
+}
+
+// MoveMemberDownHandler moves a member down in the display order
+func MoveMemberDownHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/members/:user_id/down
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 5 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	userID, err := parsePositiveID(parts[4])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = services.MoveMemberDown(standupID, userID)
+	if errors.Is(err, services.ErrNotStandupMember) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrDynamicMembership) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to move member down", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("%v", err)})
+		return
+	}
+
+	// Return updated standup with reordered members
+	writeStandupWithMembers(w, standupID, http.StatusOK)
+}
+
+// RemoveStandupMemberHandler removes a member from a standup
+func RemoveStandupMemberHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/members/:user_id
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 5 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	userID, err := parsePositiveID(parts[4])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = services.RemoveStandupMember(standupID, userID)
+	if err != nil {
+		slog.Error("Failed to remove member", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("%v", err)})
+		return
+	}
+
+	// Return updated standup with remaining members
+	writeStandupWithMembers(w, standupID, http.StatusOK)
+}
+
+// PreviewStandupHandler renders a standup's reminder without sending it and reports pre-flight diagnostics
+func PreviewStandupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/preview
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	preview, err := services.PreviewStandupReminder(id)
+	if err != nil {
+		slog.Error("Failed to preview standup", "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(preview)
+}
+
+// GetStandupEligibilityHandler lists today's eligible members and why the others were excluded
+func GetStandupEligibilityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/eligible
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	report, err := services.GetStandupEligibility(id)
+	if err != nil {
+		slog.Error("Failed to get standup eligibility", "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetStandupLeavesHandler lists the leaves of a standup's members overlapping a date range
+// (defaults to the next two weeks starting today)
+func GetStandupLeavesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/leaves
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	from, _ := time.Parse("2006-01-02", services.StandupToday(id))
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		from, err = time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid from format (use YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	to := from.AddDate(0, 0, 13)
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		to, err = time.Parse("2006-01-02", toStr)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid to format (use YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	leaves, err := services.GetStandupLeavesInRange(id, from, to)
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to get standup leaves", "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(leaves)
 }
 
-// GetStandupHandler retrieves a single standup by ID with its members
-func GetStandupHandler(w http.ResponseWriter, r *http.Request) {
+// GetStandupFairnessHandler reports how often each member has facilitated a standup
+func GetStandupFairnessHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract standup ID from URL path
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/standups/")
-	// Remove /members suffix if present
-	idStr = strings.TrimSuffix(idStr, "/members")
-	id, err := strconv.Atoi(idStr)
+	// Extract standup ID from URL: /api/standups/:id/fairness
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	id, err := parsePositiveID(parts[2])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
@@ -80,161 +1749,129 @@ func GetStandupHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	standup, err := services.GetStandupWithMembers(id)
+	report, err := services.GetFacilitationFairness(id)
 	if err != nil {
-		log.Printf("Failed to get standup: %v", err)
+		slog.Error("Failed to get facilitation fairness", "error", err)
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
 		return
 	}
 
-	json.NewEncoder(w).Encode(standup)
+	json.NewEncoder(w).Encode(report)
 }
 
-// CreateStandupHandler creates a new standup
-func CreateStandupHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+// GetFacilitatorHistoryHandler lists who facilitated a standup's sent reminders, oldest first,
+// optionally only since a date
+func GetFacilitatorHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	var req CreateStandupRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	// Extract standup ID from URL: /api/standups/:id/facilitator/history
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
 		return
 	}
 
-	// Validate required fields
-	if req.Name == "" || req.Message == "" || req.RunAt == "" {
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "name, message, and run_at are required"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 
-	standup, err := services.CreateStandup(req.Name, req.Message, req.RunAt, req.CreatedBy)
-	if err != nil {
-		log.Printf("Failed to create standup: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to create standup"})
-		return
-	}
-
-	// Add members if provided
-	if len(req.Members) > 0 {
-		err = services.SetStandupMembers(standup.ID, req.Members)
-		if err != nil {
-			log.Printf("Failed to add members to standup: %v", err)
-			// Continue anyway, standup is created
+	since := r.URL.Query().Get("since")
+	if since != "" {
+		if _, err := time.Parse("2006-01-02", since); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid since format (use YYYY-MM-DD)"})
+			return
 		}
 	}
 
-	// Refresh scheduler to include new standup
-	if err := services.RefreshScheduler(); err != nil {
-		log.Printf("Failed to refresh scheduler: %v", err)
+	history, err := services.GetFacilitatorHistory(id, since)
+	if err != nil {
+		slog.Error("Failed to get facilitator history", "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
 	}
 
-	// Return standup with members
-	standupWithMembers, _ := services.GetStandupWithMembers(standup.ID)
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(standupWithMembers)
+	json.NewEncoder(w).Encode(history)
 }
 
-// UpdateStandupHandler updates an existing standup
-func UpdateStandupHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
+// GetFacilitatorStatsHandler reports each member's facilitation count and last turn in a date window
+func GetFacilitatorStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract standup ID from URL path
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/standups/")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
+	// Extract standup ID from URL: /api/standups/:id/facilitator/stats
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
 		return
 	}
 
-	var req UpdateStandupRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	id, err := parsePositiveID(parts[2])
+	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 
-	err = services.UpdateStandup(id, req.Name, req.Message, req.RunAt)
-	if err != nil {
-		log.Printf("Failed to update standup: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to update standup"})
-		return
-	}
-
-	// Update members if provided
-	if len(req.Members) > 0 {
-		err = services.SetStandupMembers(id, req.Members)
-		if err != nil {
-			log.Printf("Failed to update members: %v", err)
+	from := r.URL.Query().Get("from")
+	if from != "" {
+		if _, err := time.Parse("2006-01-02", from); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid from format (use YYYY-MM-DD)"})
+			return
 		}
 	}
 
-	// Refresh scheduler to update schedule
-	if err := services.RefreshScheduler(); err != nil {
-		log.Printf("Failed to refresh scheduler: %v", err)
-	}
-
-	// Return updated standup with members
-	standup, _ := services.GetStandupWithMembers(id)
-	json.NewEncoder(w).Encode(standup)
-}
-
-// DeleteStandupHandler deactivates a standup
-func DeleteStandupHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+	to := r.URL.Query().Get("to")
+	if to != "" {
+		if _, err := time.Parse("2006-01-02", to); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid to format (use YYYY-MM-DD)"})
+			return
+		}
 	}
 
-	// Extract standup ID from URL path
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/standups/")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
+	if from != "" && to != "" && to < from {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "to must not be before from"})
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-
-	err = services.DeleteStandup(id)
+	stats, err := services.GetFacilitatorStats(id, from, to)
 	if err != nil {
-		log.Printf("Failed to delete standup: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to delete standup"})
+		slog.Error("Failed to get facilitator stats", "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
 		return
 	}
 
-	// Refresh scheduler to remove standup
-	if err := services.RefreshScheduler(); err != nil {
-		log.Printf("Failed to refresh scheduler: %v", err)
-	}
-
-	json.NewEncoder(w).Encode(map[string]string{"message": "Standup deleted successfully"})
+	json.NewEncoder(w).Encode(stats)
 }
 
-// GetStandupMembersHandler retrieves all members of a standup
-func GetStandupMembersHandler(w http.ResponseWriter, r *http.Request) {
+// GetNeverFacilitatedHandler lists the members who have not facilitated a standup yet
+func GetNeverFacilitatedHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract standup ID from URL: /api/standups/:id/members
+	// Extract standup ID from URL: /api/standups/:id/never-facilitated
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 	if len(parts) < 3 {
 		w.WriteHeader(http.StatusBadRequest)
@@ -242,7 +1879,7 @@ func GetStandupMembersHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := strconv.Atoi(parts[2])
+	id, err := parsePositiveID(parts[2])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
@@ -251,25 +1888,26 @@ func GetStandupMembersHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	members, err := services.GetStandupMembers(id)
+	users, err := services.GetNeverFacilitated(id)
 	if err != nil {
-		log.Printf("Failed to get standup members: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get standup members"})
+		slog.Error("Failed to get members who never facilitated", "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
 		return
 	}
 
-	json.NewEncoder(w).Encode(members)
+	json.NewEncoder(w).Encode(users)
 }
 
-// SetStandupMembersHandler replaces all members of a standup
-func SetStandupMembersHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
+// SimulateStandupHandler returns the reminders a standup would post over the next days
+// (?days=N, default 7), with projected facilitators and each day's leaves. Read-only.
+func SimulateStandupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract standup ID from URL: /api/standups/:id/members
+	// Extract standup ID from URL: /api/standups/:id/simulate
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 	if len(parts) < 3 {
 		w.WriteHeader(http.StatusBadRequest)
@@ -277,44 +1915,50 @@ func SetStandupMembersHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := strconv.Atoi(parts[2])
+	id, err := parsePositiveID(parts[2])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
 		return
 	}
 
-	var req struct {
-		Members []int `json:"members"`
+	w.Header().Set("Content-Type", "application/json")
+
+	days := 7
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days <= 0 || days > services.MaxSimulationDays {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("days must be between 1 and %d", services.MaxSimulationDays)})
+			return
+		}
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+	if _, err := services.GetStandupByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-
-	err = services.SetStandupMembers(id, req.Members)
+	simulation, err := services.SimulateStandup(id, days)
 	if err != nil {
-		log.Printf("Failed to set standup members: %v", err)
+		slog.Error("Failed to simulate standup", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to set standup members"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to simulate standup"})
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"message": "Standup members updated successfully"})
+	json.NewEncoder(w).Encode(simulation)
 }
 
-// SendStandupReminderHandler manually triggers a standup reminder
-func SendStandupReminderHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+// ExportStandupHandler returns a standup as importable JSON
+func ExportStandupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract standup ID from URL: /api/standups/:id/send
+	// Extract standup ID from URL: /api/standups/:id/export
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 	if len(parts) < 3 {
 		w.WriteHeader(http.StatusBadRequest)
@@ -322,7 +1966,7 @@ func SendStandupReminderHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := strconv.Atoi(parts[2])
+	id, err := parsePositiveID(parts[2])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
@@ -331,25 +1975,28 @@ func SendStandupReminderHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	err = services.SendManualStandupReminder(id)
+	export, err := services.ExportStandup(id)
 	if err != nil {
-		log.Printf("Failed to send manual reminder: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to send reminder: %v", err)})
+		slog.Error("Failed to export standup", "error", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"message": "Reminder sent successfully!"})
+	// The export is shown like any other standup response, so its webhooks are redacted too
+	export.WebhookURL = integrations.RedactWebhookURL(export.WebhookURL)
+	export.WebhookURLs = redactWebhookURLs(export.WebhookURLs)
+	json.NewEncoder(w).Encode(export)
 }
 
-// SetFacilitatorHandler sets the current facilitator for a standup
-func SetFacilitatorHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+// StandupCalendarHandler returns a standup's schedule as an iCalendar (.ics) file
+func StandupCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract standup ID from URL: /api/standups/:id/facilitator
+	// Extract standup ID from URL: /api/standups/:id/ics
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 	if len(parts) < 3 {
 		w.WriteHeader(http.StatusBadRequest)
@@ -357,220 +2004,277 @@ func SetFacilitatorHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	standupID, err := strconv.Atoi(parts[2])
+	id, err := parsePositiveID(parts[2])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
 		return
 	}
 
-	var req struct {
-		UserID int `json:"user_id"`
+	if _, err := services.GetStandupByID(id); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+	calendar, err := services.StandupCalendar(id)
+	if errors.Is(err, services.ErrNoCalendarDays) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-
-	err = services.SetLastFacilitator(standupID, req.UserID)
 	if err != nil {
-		log.Printf("Failed to set last facilitator: %v", err)
+		slog.Error("Failed to build standup calendar", "error", err)
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to set facilitator: %v", err)})
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to build standup calendar"})
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"message": "Facilitator updated successfully!"})
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="standup-%d.ics"`, id))
+	w.Write([]byte(calendar))
 }
 
-// RotateFacilitatorHandler rotates to the next facilitator
-func RotateFacilitatorHandler(w http.ResponseWriter, r *http.Request) {
+// ImportStandupHandler creates a standup from an exported definition
+func ImportStandupHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract standup ID from URL: /api/standups/:id/facilitator/rotate
-	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(parts) < 3 {
+	var req ImportStandupRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if req.Name == "" || req.Message == "" || req.RunAt == "" {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "name, message, and run_at are required"})
 		return
 	}
 
-	standupID, err := strconv.Atoi(parts[2])
-	if err != nil {
+	if !services.ValidRunAt(req.RunAt) {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		json.NewEncoder(w).Encode(map[string]string{"error": services.ErrInvalidRunAt.Error()})
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 
-	// Get eligible users
-	eligibleUsers, err := database.GetEligibleUsersForStandup(standupID)
-	if err != nil || len(eligibleUsers) == 0 {
-		log.Printf("Failed to get eligible users: %v", err)
+	standup, warnings, err := services.ImportStandup(req.StandupExport, req.CreatedBy)
+	var unknownErr *services.UnknownMembersError
+	switch {
+	case errors.As(err, &unknownErr):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":           "Some members do not exist on this instance",
+			"unknown_members": unknownErr.ChatIDs,
+		})
+		return
+	case errors.Is(err, services.ErrInvalidMembership), errors.Is(err, services.ErrInvalidTimezone),
+		errors.Is(err, services.ErrInvalidDaysOfWeek), errors.Is(err, services.ErrInvalidMessageFormat),
+		errors.Is(err, services.ErrInvalidTemplate):
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrStandupLimitReached):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		slog.Error("Failed to import standup", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "No eligible users for standup"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to import standup"})
 		return
 	}
 
-	// Calculate current facilitator
-	currentFac, err := services.GetCurrentFacilitator(standupID, eligibleUsers)
-	if err != nil {
-		log.Printf("Failed to get current facilitator: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to get current facilitator: %v", err)})
-		return
+	// Schedule the imported standup
+	if err := services.RescheduleStandup(standup.ID); err != nil {
+		slog.Error("Failed to schedule standup", "error", err)
 	}
 
-	// Rotate by setting last_facilitator_id to current facilitator
-	err = services.RotateFacilitator(standupID, currentFac.ID)
+	imported, err := services.GetStandupWithMembers(standup.ID)
 	if err != nil {
-		log.Printf("Failed to rotate facilitator: %v", err)
+		slog.Error("Failed to reload standup after write", "standup_id", standup.ID, "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to rotate facilitator: %v", err)})
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload standup"})
 		return
 	}
 
-	// Return updated standup with new facilitator
-	standup, _ := services.GetStandupWithMembers(standupID)
-	json.NewEncoder(w).Encode(standup)
+	redactStandupWebhooks(&imported.Standup)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ImportStandupResponse{StandupWithMembers: imported, Warnings: warnings})
 }
 
-// MoveMemberUpHandler moves a member up in the display order
-func MoveMemberUpHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+// GetStandupHistoryHandler returns a page of a standup's run history, newest first
+func GetStandupHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract standup ID from URL: /api/standups/:id/members/:user_id/up
+	// Extract standup ID from URL: /api/standups/:id/history
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(parts) < 5 {
+	if len(parts) < 3 {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
 		return
 	}
 
-	standupID, err := strconv.Atoi(parts[2])
+	id, err := parsePositiveID(parts[2])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
 		return
 	}
 
-	userID, err := strconv.Atoi(parts[4])
-	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
-		return
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 
-	err = services.MoveMemberUp(standupID, userID)
-	if err != nil {
-		log.Printf("Failed to move member up: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("%v", err)})
-		return
+	limit := config.Config.HistoryPageSize
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "limit must be a positive integer"})
+			return
+		}
 	}
-
-	// Return updated standup with reordered members
-	standup, _ := services.GetStandupWithMembers(standupID)
-	json.NewEncoder(w).Encode(standup)
-}
-
-// MoveMemberDownHandler moves a member down in the display order
-func MoveMemberDownHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+	if limit > maxPageLimit {
+		limit = maxPageLimit
 	}
 
-	// Extract standup ID from URL: /api/standups/:id/members/:user_id/down
-	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(parts) < 5 {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+	if _, err := services.GetStandupByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
 		return
 	}
 
-	standupID, err := strconv.Atoi(parts[2])
-	if err != nil {
+	runs, nextCursor, err := services.GetStandupRuns(id, limit, r.URL.Query().Get("cursor"))
+	if errors.Is(err, services.ErrInvalidCursor) {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
-
-	userID, err := strconv.Atoi(parts[4])
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
+		slog.Error("Failed to get standup history", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get standup history"})
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"items":       runs,
+		"next_cursor": nextCursor,
+	})
+}
 
-	err = services.MoveMemberDown(standupID, userID)
+// validMembership reports whether a requested membership mode is supported
+func validMembership(membership string) bool {
+	return membership == database.MembershipExplicit || membership == database.MembershipAllActive
+}
+
+// writeStandupWithMembers re-reads a standup after a write and encodes it with the given status,
+// responding 500 if the re-read fails rather than encoding a nil standup
+func writeStandupWithMembers(w http.ResponseWriter, id int, status int) {
+	standup, err := services.GetStandupWithMembers(id)
 	if err != nil {
-		log.Printf("Failed to move member down: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("%v", err)})
+		slog.Error("Failed to reload standup after write", "standup_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to reload standup"})
 		return
 	}
 
-	// Return updated standup with reordered members
-	standup, _ := services.GetStandupWithMembers(standupID)
+	redactStandupWebhooks(&standup.Standup)
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(standup)
 }
 
-// RemoveStandupMemberHandler removes a member from a standup
-func RemoveStandupMemberHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
+// writeStandups writes a list of standups with their webhook URLs redacted
+func writeStandups(w http.ResponseWriter, standups []database.Standup) {
+	for i := range standups {
+		redactStandupWebhooks(&standups[i])
+	}
+	json.NewEncoder(w).Encode(standups)
+}
+
+// redactStandupWebhooks hides the key and token of a standup's webhook URLs. Every response
+// carrying standups goes through it, so the API never hands out webhook credentials.
+func redactStandupWebhooks(standup *database.Standup) {
+	standup.WebhookURL = integrations.RedactWebhookURL(standup.WebhookURL)
+	standup.WebhookURLs = redactWebhookURLs(standup.WebhookURLs)
+}
+
+// redactWebhookURLs returns a redacted copy of a list of webhook URLs
+func redactWebhookURLs(webhookURLs []string) []string {
+	if webhookURLs == nil {
+		return nil
+	}
+
+	redacted := make([]string, len(webhookURLs))
+	for i, webhookURL := range webhookURLs {
+		redacted[i] = integrations.RedactWebhookURL(webhookURL)
+	}
+	return redacted
+}
+
+// SnoozeStandupHandler postpones today's reminder by ?minutes=N (default 30)
+func SnoozeStandupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Extract standup ID from URL: /api/standups/:id/members/:user_id
+	// Extract standup ID from URL: /api/standups/:id/snooze
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(parts) < 5 {
+	if len(parts) < 3 {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
 		return
 	}
 
-	standupID, err := strconv.Atoi(parts[2])
+	id, err := parsePositiveID(parts[2])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
 		return
 	}
 
-	userID, err := strconv.Atoi(parts[4])
-	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid user ID"})
-		return
+	minutes := 30
+	if minutesStr := r.URL.Query().Get("minutes"); minutesStr != "" {
+		minutes, err = strconv.Atoi(minutesStr)
+		if err != nil || minutes <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "minutes must be a positive integer"})
+			return
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 
-	err = services.RemoveStandupMember(standupID, userID)
-	if err != nil {
-		log.Printf("Failed to remove member: %v", err)
+	at, err := services.SnoozeStandup(id, minutes)
+	if errors.Is(err, services.ErrAlreadySentToday) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrSnoozeCrossesDay) {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("%v", err)})
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to snooze standup", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to snooze standup: %v", err)})
 		return
 	}
 
-	// Return updated standup with remaining members
-	standup, _ := services.GetStandupWithMembers(standupID)
-	json.NewEncoder(w).Encode(standup)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Standup snoozed successfully",
+		"send_at": at.Format(time.RFC3339),
+	})
 }
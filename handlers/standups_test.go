@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google-chat-bot/database"
+	"google-chat-bot/services"
+)
+
+func TestStandupResponsesRedactWebhooks(t *testing.T) {
+	const secretKey, secretToken = "secret-key-123", "secret-token-456"
+	webhookURL := "https://chat.googleapis.com/v1/spaces/AAA/messages?key=" + secretKey + "&token=" + secretToken
+	extraURL := "https://chat.googleapis.com/v1/spaces/BBB/messages?key=" + secretKey
+
+	setupTestDB(t)
+	alice := mustCreateUser(t, "alice")
+	standup := mustCreateStandup(t, "daily", alice)
+	if err := services.SetStandupWebhookURL(standup.ID, webhookURL); err != nil {
+		t.Fatalf("SetStandupWebhookURL: %v", err)
+	}
+	if err := services.SetStandupWebhookURLs(standup.ID, []string{extraURL}); err != nil {
+		t.Fatalf("SetStandupWebhookURLs: %v", err)
+	}
+
+	id := strconv.Itoa(standup.ID)
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		target  string
+	}{
+		{"list", GetStandupsHandler, "/api/standups"},
+		{"list active", GetStandupsHandler, "/api/standups?active=true"},
+		{"list paginated", GetStandupsHandler, "/api/standups?limit=10"},
+		{"list by member", GetStandupsHandler, "/api/standups?member_id=" + strconv.Itoa(alice.ID)},
+		{"list by webhook", GetStandupsHandler, "/api/standups?webhook=" + strings.ReplaceAll(webhookURL, "&", "%26")},
+		{"get", GetStandupHandler, "/api/standups/" + id},
+		{"export", ExportStandupHandler, "/api/standups/" + id + "/export"},
+		{"today", GetUserTodayHandler, "/api/roster/" + strconv.Itoa(alice.ID) + "/today"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, tt.handler, http.MethodGet, tt.target, nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+			}
+
+			body := rec.Body.String()
+			if strings.Contains(body, secretKey) || strings.Contains(body, secretToken) {
+				t.Errorf("response leaks a webhook secret: %s", body)
+			}
+			if !strings.Contains(body, "spaces/AAA") || !strings.Contains(body, "REDACTED") {
+				t.Errorf("response does not carry the redacted webhook: %s", body)
+			}
+		})
+	}
+}
+
+func TestExportImportRoundTripDropsRedactedWebhooks(t *testing.T) {
+	const (
+		mainURL  = "https://chat.googleapis.com/v1/spaces/AAA/messages?key=k&token=t"
+		extraURL = "https://chat.googleapis.com/v1/spaces/BBB/messages?key=k&token=t"
+		plainURL = "https://hooks.example.com/standup"
+	)
+
+	tests := []struct {
+		name         string
+		webhookURL   string
+		webhookURLs  []string
+		wantURL      string
+		wantURLs     []string
+		wantWarnings int
+	}{
+		{"main and extra redacted", mainURL, []string{extraURL}, "", nil, 2},
+		{"extra redacted", plainURL, []string{extraURL, plainURL + "/extra"}, plainURL, []string{plainURL + "/extra"}, 1},
+		{"nothing redacted", plainURL, nil, plainURL, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			alice := mustCreateUser(t, "alice")
+			standup := mustCreateStandup(t, "daily", alice)
+			if err := services.SetStandupWebhookURL(standup.ID, tt.webhookURL); err != nil {
+				t.Fatalf("SetStandupWebhookURL: %v", err)
+			}
+			if err := services.SetStandupWebhookURLs(standup.ID, tt.webhookURLs); err != nil {
+				t.Fatalf("SetStandupWebhookURLs: %v", err)
+			}
+
+			rec := serve(t, ExportStandupHandler, http.MethodGet, "/api/standups/"+strconv.Itoa(standup.ID)+"/export", nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("export status = %d: %s", rec.Code, rec.Body)
+			}
+
+			var export map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &export); err != nil {
+				t.Fatalf("decode export: %v", err)
+			}
+			export["name"] = "daily copy"
+
+			rec = serve(t, ImportStandupHandler, http.MethodPost, "/api/standups/import", export)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("import status = %d, want 201: %s", rec.Code, rec.Body)
+			}
+
+			var response ImportStandupResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode import: %v", err)
+			}
+			if len(response.Warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %q, want %d", response.Warnings, tt.wantWarnings)
+			}
+
+			imported, err := services.GetStandupByID(response.ID)
+			if err != nil {
+				t.Fatalf("GetStandupByID: %v", err)
+			}
+			if imported.WebhookURL != tt.wantURL || !reflect.DeepEqual(imported.WebhookURLs, tt.wantURLs) {
+				t.Errorf("imported webhooks = %q %q, want %q %q", imported.WebhookURL, imported.WebhookURLs, tt.wantURL, tt.wantURLs)
+			}
+		})
+	}
+}
+
+func TestSetStandupMembersReturnsMembers(t *testing.T) {
+	setupTestDB(t)
+	alice := mustCreateUser(t, "alice")
+	bob := mustCreateUser(t, "bob")
+	standup := mustCreateStandup(t, "Daily", alice)
+
+	tests := []struct {
+		name       string
+		target     string
+		members    []int
+		wantStatus int
+		want       []string
+	}{
+		{"replace", "/api/standups/" + strconv.Itoa(standup.ID) + "/members", []int{bob.ID, alice.ID}, http.StatusOK, []string{"bob", "alice"}},
+		{"remove all", "/api/standups/" + strconv.Itoa(standup.ID) + "/members", []int{}, http.StatusOK, []string{}},
+		{"unknown standup", "/api/standups/999/members", []int{alice.ID}, http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, SetStandupMembersHandler, http.MethodPut, tt.target, map[string][]int{"members": tt.members})
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.want == nil {
+				return
+			}
+
+			var members []StandupMemberResponse
+			if err := json.NewDecoder(rec.Body).Decode(&members); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			names := []string{}
+			for _, member := range members {
+				names = append(names, member.DisplayName)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("members = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestStandupMembersRejectUnknownUser(t *testing.T) {
+	setupTestDB(t)
+	alice := mustCreateUser(t, "alice")
+	standup := mustCreateStandup(t, "Daily", alice)
+	id := strconv.Itoa(standup.ID)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		target  string
+		body    interface{}
+	}{
+		{"replace roster", SetStandupMembersHandler, "/api/standups/" + id + "/members", map[string][]int{"members": {alice.ID, 999}}},
+		{"update standup", UpdateStandupHandler, "/api/standups/" + id, map[string]interface{}{
+			"name": "Renamed", "message": "Hi", "run_at": "09:00", "members": []int{alice.ID, 999},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, tt.handler, http.MethodPut, tt.target, tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body)
+			}
+
+			got, err := services.GetStandupWithMembers(standup.ID)
+			if err != nil {
+				t.Fatalf("GetStandupWithMembers: %v", err)
+			}
+			if got.Name != "Daily" {
+				t.Errorf("name = %q, want it unchanged", got.Name)
+			}
+			if len(got.Members) != 1 || got.Members[0].ID != alice.ID {
+				t.Errorf("members = %+v, want only alice", got.Members)
+			}
+		})
+	}
+}
+
+func TestSetFacilitatorReturnsStandup(t *testing.T) {
+	setupTestDB(t)
+	alice := mustCreateUser(t, "alice")
+	bob := mustCreateUser(t, "bob")
+	standup := mustCreateStandup(t, "Daily", alice, bob)
+	target := "/api/standups/" + strconv.Itoa(standup.ID) + "/facilitator"
+
+	tests := []struct {
+		name       string
+		target     string
+		body       map[string]interface{}
+		wantStatus int
+		wantLast   int
+	}{
+		{"by user ID", target, map[string]interface{}{"user_id": bob.ID}, http.StatusOK, bob.ID},
+		{"by chat ID", target, map[string]interface{}{"google_chat_user_id": "users/alice"}, http.StatusOK, alice.ID},
+		{"unknown standup", "/api/standups/999/facilitator", map[string]interface{}{"user_id": bob.ID}, http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, SetFacilitatorHandler, http.MethodPost, tt.target, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantLast == 0 {
+				return
+			}
+
+			var got struct {
+				ID                int  `json:"id"`
+				LastFacilitatorID *int `json:"last_facilitator_id"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.ID != standup.ID || got.LastFacilitatorID == nil || *got.LastFacilitatorID != tt.wantLast {
+				t.Errorf("standup %d last_facilitator_id = %v, want %d", got.ID, got.LastFacilitatorID, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestMoveMemberRequiresMembership(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		direction  string
+		user       string // "" = a user who exists but is not a member
+		standupID  int    // 0 = the test standup
+		wantStatus int
+		wantOrder  string
+	}{
+		{"up", MoveMemberUpHandler, "up", "bob", 0, http.StatusOK, "bob,alice,carol"},
+		{"down", MoveMemberDownHandler, "down", "bob", 0, http.StatusOK, "alice,carol,bob"},
+		{"up past the top", MoveMemberUpHandler, "up", "alice", 0, http.StatusBadRequest, ""},
+		{"up by a non-member", MoveMemberUpHandler, "up", "", 0, http.StatusNotFound, ""},
+		{"down by a non-member", MoveMemberDownHandler, "down", "", 0, http.StatusNotFound, ""},
+		{"up in an unknown standup", MoveMemberUpHandler, "up", "bob", 999, http.StatusNotFound, ""},
+		{"down in an unknown standup", MoveMemberDownHandler, "down", "bob", 999, http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			users := map[string]*database.User{
+				"alice": mustCreateUser(t, "alice"),
+				"bob":   mustCreateUser(t, "bob"),
+				"carol": mustCreateUser(t, "carol"),
+				"":      mustCreateUser(t, "dave"),
+			}
+			standup := mustCreateStandup(t, "Daily", users["alice"], users["bob"], users["carol"])
+
+			standupID := tt.standupID
+			if standupID == 0 {
+				standupID = standup.ID
+			}
+			target := fmt.Sprintf("/api/standups/%d/members/%d/%s", standupID, users[tt.user].ID, tt.direction)
+			rec := serve(t, tt.handler, http.MethodPost, target, nil)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+
+			members, err := services.GetStandupMembers(standup.ID)
+			if err != nil {
+				t.Fatalf("GetStandupMembers: %v", err)
+			}
+			var names []string
+			for _, member := range members {
+				names = append(names, member.DisplayName)
+			}
+			wantOrder := tt.wantOrder
+			if wantOrder == "" {
+				wantOrder = "alice,bob,carol"
+			}
+			if got := strings.Join(names, ","); got != wantOrder {
+				t.Errorf("members = %s, want %s", got, wantOrder)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"google-chat-bot/services"
+)
+
+// CreateSubstitutionRequest represents the request to cover a member's facilitation turns
+type CreateSubstitutionRequest struct {
+	OriginalUserID   int    `json:"original_user_id"`
+	SubstituteUserID int    `json:"substitute_user_id"`
+	StartDate        string `json:"start_date"` // Format: YYYY-MM-DD
+	EndDate          string `json:"end_date"`   // Format: YYYY-MM-DD
+}
+
+// GetSubstitutionsHandler lists a standup's facilitator substitutions
+func GetSubstitutionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/substitutions
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(standupID); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	subs, err := services.GetSubstitutions(standupID)
+	if err != nil {
+		slog.Error("Failed to get substitutions", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to get substitutions"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(subs)
+}
+
+// CreateSubstitutionHandler hands a member's facilitation turns to another member for a date range
+func CreateSubstitutionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract standup ID from URL: /api/standups/:id/substitutions
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	var req CreateSubstitutionRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	// Validate required fields
+	if req.OriginalUserID == 0 || req.SubstituteUserID == 0 || req.StartDate == "" || req.EndDate == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "original_user_id, substitute_user_id, start_date, and end_date are required"})
+		return
+	}
+
+	if _, err := time.Parse("2006-01-02", req.StartDate); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid start_date format (use YYYY-MM-DD)"})
+		return
+	}
+
+	if _, err := time.Parse("2006-01-02", req.EndDate); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid end_date format (use YYYY-MM-DD)"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := services.GetStandupByID(standupID); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Standup not found"})
+		return
+	}
+
+	sub, err := services.CreateSubstitution(standupID, req.OriginalUserID, req.SubstituteUserID, req.StartDate, req.EndDate)
+	if err != nil {
+		slog.Error("Failed to create substitution", "error", err)
+		if errors.Is(err, services.ErrInvalidSubstitution) || errors.Is(err, services.ErrNotStandupMember) {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("%v", err)})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sub)
+}
+
+// DeleteSubstitutionHandler removes a facilitator substitution
+func DeleteSubstitutionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Extract IDs from URL: /api/standups/:id/substitutions/:sub_id
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 5 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid URL"})
+		return
+	}
+
+	standupID, err := parsePositiveID(parts[2])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid standup ID"})
+		return
+	}
+
+	subID, err := parsePositiveID(parts[4])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid substitution ID"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := services.DeleteSubstitution(standupID, subID); err != nil {
+		slog.Error("Failed to delete substitution", "error", err)
+		if errors.Is(err, services.ErrSubstitutionNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("%v", err)})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"message": "Substitution deleted successfully"})
+}
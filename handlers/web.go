@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"html/template"
-	"log"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"google-chat-bot/config"
+	"google-chat-bot/database"
 	"google-chat-bot/integrations"
 )
 
@@ -16,7 +19,7 @@ func HomeHandler(w http.ResponseWriter, r *http.Request) {
 	tmpl, err := template.ParseFiles("templates/ui.html")
 	if err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		log.Printf("Template parsing error: %v", err)
+		slog.Error("Template parsing error", "error", err)
 		return
 	}
 	tmpl.Execute(w, nil)
@@ -38,9 +41,8 @@ func SendHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req MessageRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
@@ -53,8 +55,8 @@ func SendHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var err error
-	if req.MessageType == "card" {
-		// Send as card message
+	if req.MessageType == "card" && config.Config.CardFormat == "cards" {
+		// Send as a legacy card message (CARD_FORMAT=cards)
 		cardMsg := integrations.CardMessage{
 			Cards: []integrations.Card{
 				{
@@ -76,14 +78,38 @@ func SendHandler(w http.ResponseWriter, r *http.Request) {
 				},
 			},
 		}
-		err = integrations.SendCardMessage(config.Config.WebhookURL, cardMsg)
+		err = integrations.SendCardMessage(r.Context(), config.Config.WebhookURL, cardMsg)
+	} else if req.MessageType == "card" {
+		// Send as card message
+		card := integrations.CardV2{
+			Sections: []integrations.CardV2Section{
+				{
+					Widgets: []integrations.WidgetV2{
+						{
+							TextParagraph: &integrations.TextParagraph{
+								Text: req.Message,
+							},
+						},
+					},
+				},
+			},
+		}
+		if req.CardTitle != "" {
+			card.Header = &integrations.CardHeader{
+				Title:    req.CardTitle,
+				Subtitle: req.CardSubtitle,
+			}
+		}
+		err = integrations.SendCardV2Message(r.Context(), config.Config.WebhookURL, integrations.CardV2Message{
+			CardsV2: []integrations.CardWithID{{CardID: "card-1", Card: card}},
+		})
 	} else {
 		// Send as simple message
-		err = integrations.SendSimpleMessage(config.Config.WebhookURL, req.Message)
+		err = integrations.SendSimpleMessage(r.Context(), config.Config.WebhookURL, req.Message)
 	}
 
 	if err != nil {
-		log.Printf("Failed to send message: %v", err)
+		slog.Error("Failed to send message", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Failed to send message: %v", err)})
 		return
@@ -106,9 +132,39 @@ func SendReminderHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// HealthHandler returns the health status of the service
+// healthCheckTimeout bounds the database check behind the readiness probe
+const healthCheckTimeout = 2 * time.Second
+
+// HealthHandler is the readiness probe: it reports 503 when the database can't be reached,
+// so a load balancer takes the instance out of rotation
 func HealthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
+
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	if err := database.Ping(ctx); err != nil {
+		slog.Error("Health check failed", "error", err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{
+			"status":  "degraded",
+			"service": "standup-bot",
+			"db":      "unreachable",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"status":  "ok",
+		"service": "standup-bot",
+		"db":      "ok",
+	})
+}
+
+// LiveHandler is the liveness probe: it only reports that the process is up and serving
+func LiveHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{
 		"status": "ok",
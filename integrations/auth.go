@@ -0,0 +1,205 @@
+package integrations
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// chatIssuer is the issuer of the bearer tokens Google Chat sends to HTTP apps
+	chatIssuer = "chat@system.gserviceaccount.com"
+	// chatCertsURL serves the x509 certificates Google Chat signs its tokens with, by key ID
+	chatCertsURL = "https://www.googleapis.com/service_accounts/v1/metadata/x509/" + chatIssuer
+	// chatTokenLeeway tolerates clock skew when checking token expiry
+	chatTokenLeeway = time.Minute
+	// defaultCertsMaxAge is how long certs are cached when the response has no max-age
+	defaultCertsMaxAge = time.Hour
+)
+
+// ErrUnauthenticatedChatRequest is returned when an inbound request does not carry a valid Google Chat token
+var ErrUnauthenticatedChatRequest = errors.New("request is not authenticated by Google Chat")
+
+// chatAudience is the audience Google Chat tokens must be issued for (the project number)
+var chatAudience string
+
+// SetChatAudience sets the audience inbound Google Chat tokens must carry. Call it at startup;
+// while it is empty every request fails verification.
+func SetChatAudience(audience string) {
+	chatAudience = audience
+}
+
+// chatCerts caches Google Chat's signing keys until the certs endpoint's max-age runs out
+var chatCerts struct {
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	expires time.Time
+}
+
+// VerifyChatRequest checks the bearer JWT Google Chat sends in the Authorization header: an RS256
+// signature by one of Google Chat's published certs, the chat issuer, the configured audience and
+// an unexpired lifetime. Failures wrap ErrUnauthenticatedChatRequest.
+func VerifyChatRequest(r *http.Request) error {
+	if chatAudience == "" {
+		return fmt.Errorf("%w: no audience configured", ErrUnauthenticatedChatRequest)
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return fmt.Errorf("%w: missing bearer token", ErrUnauthenticatedChatRequest)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("%w: malformed token", ErrUnauthenticatedChatRequest)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeTokenPart(parts[0], &header); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnauthenticatedChatRequest, err)
+	}
+	if header.Alg != "RS256" {
+		return fmt.Errorf("%w: unexpected signing algorithm %q", ErrUnauthenticatedChatRequest, header.Alg)
+	}
+
+	key, err := chatSigningKey(r.Context(), header.Kid)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnauthenticatedChatRequest, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrUnauthenticatedChatRequest)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return fmt.Errorf("%w: invalid signature", ErrUnauthenticatedChatRequest)
+	}
+
+	var claims struct {
+		Iss string  `json:"iss"`
+		Aud string  `json:"aud"`
+		Exp float64 `json:"exp"`
+		Iat float64 `json:"iat"`
+	}
+	if err := decodeTokenPart(parts[1], &claims); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnauthenticatedChatRequest, err)
+	}
+
+	now := time.Now()
+	switch {
+	case claims.Iss != chatIssuer:
+		return fmt.Errorf("%w: unexpected issuer %q", ErrUnauthenticatedChatRequest, claims.Iss)
+	case claims.Aud != chatAudience:
+		return fmt.Errorf("%w: unexpected audience %q", ErrUnauthenticatedChatRequest, claims.Aud)
+	case now.After(time.Unix(int64(claims.Exp), 0).Add(chatTokenLeeway)):
+		return fmt.Errorf("%w: token expired", ErrUnauthenticatedChatRequest)
+	case time.Unix(int64(claims.Iat), 0).After(now.Add(chatTokenLeeway)):
+		return fmt.Errorf("%w: token issued in the future", ErrUnauthenticatedChatRequest)
+	}
+
+	return nil
+}
+
+// decodeTokenPart decodes one base64url segment of a JWT into v
+func decodeTokenPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return fmt.Errorf("malformed token: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("malformed token: %w", err)
+	}
+	return nil
+}
+
+// chatSigningKey returns Google Chat's public key with the given ID, refreshing the cached
+// certs when they have expired or the key is unknown (Google rotates keys regularly)
+func chatSigningKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	chatCerts.mu.Lock()
+	defer chatCerts.mu.Unlock()
+
+	if key, ok := chatCerts.keys[kid]; ok && time.Now().Before(chatCerts.expires) {
+		return key, nil
+	}
+
+	keys, maxAge, err := fetchChatCerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chatCerts.keys = keys
+	chatCerts.expires = time.Now().Add(maxAge)
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// fetchChatCerts downloads Google Chat's signing certs and how long they may be cached
+func fetchChatCerts(ctx context.Context) (map[string]*rsa.PublicKey, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, chatCertsURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch chat certs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("failed to fetch chat certs: unexpected status code: %d", resp.StatusCode)
+	}
+
+	var pems map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&pems); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode chat certs: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(pems))
+	for kid, certPEM := range pems {
+		block, _ := pem.Decode([]byte(certPEM))
+		if block == nil {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			keys[kid] = key
+		}
+	}
+
+	return keys, certsMaxAge(resp.Header.Get("Cache-Control")), nil
+}
+
+// certsMaxAge reads max-age from a Cache-Control header, falling back to defaultCertsMaxAge
+func certsMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age="); ok {
+			if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return defaultCertsMaxAge
+}
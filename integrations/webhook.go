@@ -2,22 +2,52 @@ package integrations
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"time"
+
+	"google-chat-bot/metrics"
 )
 
+// DefaultHTTPTimeout bounds a webhook POST when no timeout has been configured
+const DefaultHTTPTimeout = 10 * time.Second
+
+// httpClient is shared by all webhook POSTs so connections are reused
+var httpClient = &http.Client{Timeout: DefaultHTTPTimeout}
+
+// SetHTTPTimeout sets how long a webhook POST may take before it is abandoned.
+// Call it at startup, before any message is sent.
+func SetHTTPTimeout(timeout time.Duration) {
+	httpClient.Timeout = timeout
+}
+
 // Message represents a Google Chat message
 type Message struct {
-	Text string `json:"text"`
+	Text   string  `json:"text"`
+	Thread *Thread `json:"thread,omitempty"`
+}
+
+// Thread identifies the thread a message is posted into. Google Chat groups every message
+// sent to a space with the same threadKey into one thread.
+type Thread struct {
+	ThreadKey string `json:"threadKey"`
 }
 
 // CardMessage represents a more complex Google Chat card message
+//
+// Deprecated: the legacy cards format is being retired by Google Chat; use CardV2Message.
 type CardMessage struct {
 	Cards []Card `json:"cards,omitempty"`
 	Text  string `json:"text,omitempty"`
 }
 
+// Card is a legacy card.
+//
+// Deprecated: use CardV2.
 type Card struct {
 	Header   CardHeader    `json:"header,omitempty"`
 	Sections []CardSection `json:"sections,omitempty"`
@@ -29,10 +59,16 @@ type CardHeader struct {
 	ImageURL string `json:"imageUrl,omitempty"`
 }
 
+// CardSection is a legacy card section.
+//
+// Deprecated: use CardV2Section.
 type CardSection struct {
 	Widgets []Widget `json:"widgets"`
 }
 
+// Widget is a legacy card widget.
+//
+// Deprecated: use WidgetV2.
 type Widget struct {
 	TextParagraph *TextParagraph `json:"textParagraph,omitempty"`
 	KeyValue      *KeyValue      `json:"keyValue,omitempty"`
@@ -42,23 +78,147 @@ type TextParagraph struct {
 	Text string `json:"text"`
 }
 
+// KeyValue is a legacy label/content widget.
+//
+// Deprecated: use DecoratedText.
 type KeyValue struct {
 	TopLabel string `json:"topLabel,omitempty"`
 	Content  string `json:"content"`
 }
 
+// CardV2Message is a Google Chat message using the cardsV2 format, which replaces the deprecated cards format
+type CardV2Message struct {
+	CardsV2 []CardWithID `json:"cardsV2,omitempty"`
+	Text    string       `json:"text,omitempty"`
+	Thread  *Thread      `json:"thread,omitempty"`
+}
+
+// CardWithID wraps a cardsV2 card with the identifier Google Chat requires
+type CardWithID struct {
+	CardID string `json:"cardId"`
+	Card   CardV2 `json:"card"`
+}
+
+type CardV2 struct {
+	Header   *CardHeader     `json:"header,omitempty"`
+	Sections []CardV2Section `json:"sections,omitempty"`
+}
+
+type CardV2Section struct {
+	Header  string     `json:"header,omitempty"`
+	Widgets []WidgetV2 `json:"widgets"`
+}
+
+// WidgetV2 is a cardsV2 widget; set exactly one field
+type WidgetV2 struct {
+	TextParagraph *TextParagraph `json:"textParagraph,omitempty"`
+	DecoratedText *DecoratedText `json:"decoratedText,omitempty"`
+	ButtonList    *ButtonList    `json:"buttonList,omitempty"`
+}
+
+// DecoratedText is the cardsV2 replacement for KeyValue
+type DecoratedText struct {
+	TopLabel    string  `json:"topLabel,omitempty"`
+	Text        string  `json:"text"`
+	BottomLabel string  `json:"bottomLabel,omitempty"`
+	Button      *Button `json:"button,omitempty"` // Shown at the end of the line
+}
+
+// ButtonList is a row of buttons
+type ButtonList struct {
+	Buttons []Button `json:"buttons"`
+}
+
+// Button is a cardsV2 text button
+type Button struct {
+	Text    string  `json:"text"`
+	OnClick OnClick `json:"onClick"`
+}
+
+// OnClick is what happens when a button is clicked; set exactly one field
+type OnClick struct {
+	OpenLink *OpenLink `json:"openLink,omitempty"`
+	Action   *Action   `json:"action,omitempty"`
+}
+
+// Action calls back into a Chat app. For HTTP apps, Function is the URL the click event is posted to.
+type Action struct {
+	Function   string            `json:"function"`
+	Parameters []ActionParameter `json:"parameters,omitempty"`
+}
+
+// ActionParameter is a key/value pair passed back in the click event
+type ActionParameter struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// OpenLink opens a URL in a new tab
+type OpenLink struct {
+	URL string `json:"url"`
+}
+
+// ToV2 converts a legacy cards message to the cardsV2 format
+func (m CardMessage) ToV2() CardV2Message {
+	msg := CardV2Message{Text: m.Text}
+
+	for i, card := range m.Cards {
+		v2 := CardV2{}
+		if card.Header.Title != "" {
+			header := card.Header
+			v2.Header = &header
+		}
+
+		for _, section := range card.Sections {
+			v2Section := CardV2Section{Widgets: []WidgetV2{}}
+			for _, widget := range section.Widgets {
+				switch {
+				case widget.TextParagraph != nil:
+					v2Section.Widgets = append(v2Section.Widgets, WidgetV2{TextParagraph: widget.TextParagraph})
+				case widget.KeyValue != nil:
+					v2Section.Widgets = append(v2Section.Widgets, WidgetV2{DecoratedText: &DecoratedText{
+						TopLabel: widget.KeyValue.TopLabel,
+						Text:     widget.KeyValue.Content,
+					}})
+				}
+			}
+			v2.Sections = append(v2.Sections, v2Section)
+		}
+
+		msg.CardsV2 = append(msg.CardsV2, CardWithID{CardID: fmt.Sprintf("card-%d", i+1), Card: v2})
+	}
+
+	return msg
+}
+
 // SendSimpleMessage sends a simple text message to Google Chat webhook
-func SendSimpleMessage(webhookURL, message string) error {
+func SendSimpleMessage(ctx context.Context, webhookURL, message string) error {
+	return SendThreadedMessage(ctx, webhookURL, message, "")
+}
+
+// SendThreadedMessage sends a text message into the thread identified by threadKey, starting
+// the thread if it doesn't exist yet. An empty threadKey posts to the main timeline.
+func SendThreadedMessage(ctx context.Context, webhookURL, message, threadKey string) error {
 	msg := Message{
 		Text: message,
 	}
 
+	if threadKey != "" {
+		msg.Thread = &Thread{ThreadKey: threadKey}
+
+		threadedURL, err := withReplyOption(webhookURL)
+		if err != nil {
+			return fmt.Errorf("invalid webhook URL: %w", err)
+		}
+		webhookURL = threadedURL
+	}
+
 	jsonData, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := postJSON(ctx, webhookURL, jsonData)
 	if err != nil {
 		return fmt.Errorf("failed to send message: %w", err)
 	}
@@ -72,13 +232,41 @@ func SendSimpleMessage(webhookURL, message string) error {
 }
 
 // SendCardMessage sends a card message to Google Chat webhook
-func SendCardMessage(webhookURL string, cardMsg CardMessage) error {
-	jsonData, err := json.Marshal(cardMsg)
+//
+// Deprecated: the legacy cards format is being retired by Google Chat; use SendCardV2Message.
+func SendCardMessage(ctx context.Context, webhookURL string, cardMsg CardMessage) error {
+	return postCardPayload(ctx, webhookURL, cardMsg)
+}
+
+// SendCardV2Message sends a cardsV2 message to Google Chat webhook
+func SendCardV2Message(ctx context.Context, webhookURL string, cardMsg CardV2Message) error {
+	return SendThreadedCardV2Message(ctx, webhookURL, cardMsg, "")
+}
+
+// SendThreadedCardV2Message sends a cardsV2 message into the thread identified by threadKey,
+// like SendThreadedMessage. An empty threadKey posts to the main timeline.
+func SendThreadedCardV2Message(ctx context.Context, webhookURL string, cardMsg CardV2Message, threadKey string) error {
+	if threadKey != "" {
+		cardMsg.Thread = &Thread{ThreadKey: threadKey}
+
+		threadedURL, err := withReplyOption(webhookURL)
+		if err != nil {
+			return fmt.Errorf("invalid webhook URL: %w", err)
+		}
+		webhookURL = threadedURL
+	}
+
+	return postCardPayload(ctx, webhookURL, cardMsg)
+}
+
+// postCardPayload marshals and posts a card message in either format
+func postCardPayload(ctx context.Context, webhookURL string, payload interface{}) error {
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal card message: %w", err)
 	}
 
-	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := postJSON(ctx, webhookURL, jsonData)
 	if err != nil {
 		return fmt.Errorf("failed to send card message: %w", err)
 	}
@@ -90,3 +278,77 @@ func SendCardMessage(webhookURL string, cardMsg CardMessage) error {
 
 	return nil
 }
+
+// withReplyOption adds the query parameter that makes Google Chat reply into the message's
+// thread, falling back to starting a new thread when none has the key yet
+func withReplyOption(webhookURL string) (string, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := parsed.Query()
+	query.Set("messageReplyOption", "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String(), nil
+}
+
+// postJSON posts a JSON body to a webhook with the shared client, honouring ctx cancellation.
+// Transport errors quote the URL, so its key and token are redacted before returning them.
+func postJSON(ctx context.Context, webhookURL string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	metrics.WebhookDuration.Observe(time.Since(start).Seconds())
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = RedactWebhookURL(urlErr.URL)
+	}
+	return resp, err
+}
+
+// redactedValue stands in for the secrets hidden by RedactWebhookURL
+const redactedValue = "REDACTED"
+
+// webhookSecretParams are the query parameters of a webhook URL that carry credentials
+var webhookSecretParams = []string{"key", "token"}
+
+// RedactWebhookURL hides the key and token query parameters of a webhook URL so it can be shown safely
+func RedactWebhookURL(webhookURL string) string {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return redactedValue
+	}
+
+	query := parsed.Query()
+	for _, param := range webhookSecretParams {
+		if query.Has(param) {
+			query.Set(param, redactedValue)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// IsRedactedWebhookURL reports whether a webhook URL had its secrets hidden by RedactWebhookURL
+func IsRedactedWebhookURL(webhookURL string) bool {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return webhookURL == redactedValue
+	}
+
+	query := parsed.Query()
+	for _, param := range webhookSecretParams {
+		if query.Get(param) == redactedValue {
+			return true
+		}
+	}
+	return false
+}
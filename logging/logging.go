@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Setup configures the default slog logger from LOG_LEVEL (debug, info, warn or error) and
+// LOG_FORMAT ("json" for one JSON object per line, anything else for the plain text format).
+// In JSON mode the standard log package is routed through the same handler at info level,
+// so code that still uses log.Printf ends up in the same stream.
+func Setup(level, format string) {
+	lvl, ok := parseLevel(level)
+	if !ok {
+		log.Printf("Warning: invalid LOG_LEVEL=%q, using info", level)
+	}
+
+	if format == "json" {
+		handler := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: lvl})
+		slog.SetDefault(slog.New(handler))
+		return
+	}
+
+	// The default handler writes through the log package, keeping the usual text layout
+	slog.SetLogLoggerLevel(lvl)
+}
+
+// parseLevel maps a LOG_LEVEL value to a slog level, reporting whether it was recognised
+func parseLevel(level string) (slog.Level, bool) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info", "":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	}
+	return slog.LevelInfo, false
+}
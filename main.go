@@ -1,19 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"google-chat-bot/config"
 	"google-chat-bot/database"
 	"google-chat-bot/handlers"
+	"google-chat-bot/integrations"
+	"google-chat-bot/logging"
+	"google-chat-bot/metrics"
 	"google-chat-bot/services"
 )
 
+// shutdownTimeout bounds how long shutdown waits for in-flight requests and reminder sends
+const shutdownTimeout = 30 * time.Second
+
 // handleRosterRoutes routes roster API requests
 func handleRosterRoutes(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path == "/api/roster" || r.URL.Path == "/api/roster/" {
@@ -26,9 +35,21 @@ func handleRosterRoutes(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
+	} else if r.URL.Path == "/api/roster/import" {
+		// CSV import route: POST /api/roster/import
+		handlers.ImportRosterHandler(w, r)
+	} else if strings.HasPrefix(r.URL.Path, "/api/roster/by-chat-id/") {
+		// Lookup by Google Chat user ID route: GET /api/roster/by-chat-id/users/123
+		handlers.GetUserByChatIDHandler(w, r)
 	} else if strings.HasSuffix(r.URL.Path, "/reactivate") && r.Method == http.MethodPost {
 		// Reactivate route
 		handlers.ReactivateUserHandler(w, r)
+	} else if strings.HasSuffix(r.URL.Path, "/leaves/upcoming") {
+		// Upcoming leaves route: GET /api/roster/:id/leaves/upcoming
+		handlers.GetUserUpcomingLeavesHandler(w, r)
+	} else if strings.HasSuffix(r.URL.Path, "/today") && r.Method == http.MethodGet {
+		// Today's standups route: GET /api/roster/:id/today
+		handlers.GetUserTodayHandler(w, r)
 	} else {
 		// Single resource routes
 		switch r.Method {
@@ -52,10 +73,47 @@ func handleLeavesRoutes(w http.ResponseWriter, r *http.Request) {
 		case http.MethodGet:
 			handlers.GetLeavesHandler(w, r)
 		case http.MethodPost:
-			handlers.CreateLeaveHandler(w, r)
+			handlers.Idempotent("create_leave", handlers.CreateLeaveHandler)(w, r)
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
+	} else if strings.HasSuffix(r.URL.Path, "/activate") {
+		// Activate route: POST /api/leaves/:id/activate
+		if r.Method == http.MethodPost {
+			handlers.ActivateLeaveHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/approve") {
+		// Approve route: POST /api/leaves/:id/approve
+		if r.Method == http.MethodPost {
+			handlers.ApproveLeaveHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/reject") {
+		// Reject route: POST /api/leaves/:id/reject
+		if r.Method == http.MethodPost {
+			handlers.RejectLeaveHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/complete") {
+		// Complete route: POST /api/leaves/:id/complete
+		if r.Method == http.MethodPost {
+			handlers.CompleteLeaveHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if r.URL.Path == "/api/leaves/purge" {
+		// Purge route: DELETE /api/leaves/purge?before=YYYY-MM-DD
+		handlers.PurgeLeavesHandler(w, r)
+	} else if r.URL.Path == "/api/leaves/calendar" {
+		// Calendar route: GET /api/leaves/calendar?month=YYYY-MM
+		handlers.GetLeaveCalendarHandler(w, r)
+	} else if r.URL.Path == "/api/leaves/export" {
+		// Export route: GET /api/leaves/export?format=csv
+		handlers.ExportLeavesHandler(w, r)
 	} else {
 		// Single resource routes
 		switch r.Method {
@@ -71,6 +129,36 @@ func handleLeavesRoutes(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleHolidaysRoutes routes holidays API requests
+func handleHolidaysRoutes(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/holidays" || r.URL.Path == "/api/holidays/" {
+		// Collection routes
+		switch r.Method {
+		case http.MethodGet:
+			handlers.GetHolidaysHandler(w, r)
+		case http.MethodPost:
+			handlers.CreateHolidayHandler(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if r.URL.Path == "/api/holidays/import" {
+		// Bulk import route: POST /api/holidays/import
+		handlers.ImportHolidaysHandler(w, r)
+	} else {
+		// Single resource routes
+		switch r.Method {
+		case http.MethodGet:
+			handlers.GetHolidayHandler(w, r)
+		case http.MethodPut:
+			handlers.UpdateHolidayHandler(w, r)
+		case http.MethodDelete:
+			handlers.DeleteHolidayHandler(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
 // handleStandupsRoutes routes standups API requests
 func handleStandupsRoutes(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path == "/api/standups" || r.URL.Path == "/api/standups/" {
@@ -79,10 +167,27 @@ func handleStandupsRoutes(w http.ResponseWriter, r *http.Request) {
 		case http.MethodGet:
 			handlers.GetStandupsHandler(w, r)
 		case http.MethodPost:
-			handlers.CreateStandupHandler(w, r)
+			handlers.Idempotent("create_standup", handlers.CreateStandupHandler)(w, r)
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
+	} else if r.URL.Path == "/api/standups/import" {
+		// Import route: POST /api/standups/import
+		if r.Method == http.MethodPost {
+			handlers.ImportStandupHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/export") {
+		// Export route: GET /api/standups/:id/export
+		if r.Method == http.MethodGet {
+			handlers.ExportStandupHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/ics") {
+		// Calendar route: GET /api/standups/:id/ics
+		handlers.StandupCalendarHandler(w, r)
 	} else if strings.HasSuffix(r.URL.Path, "/up") {
 		// Move member up route: /api/standups/:id/members/:user_id/up
 		if r.Method == http.MethodPost {
@@ -97,19 +202,83 @@ func handleStandupsRoutes(w http.ResponseWriter, r *http.Request) {
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
+	} else if strings.Contains(r.URL.Path, "/substitutions/") {
+		// Remove substitution route: DELETE /api/standups/:id/substitutions/:sub_id
+		if r.Method == http.MethodDelete {
+			handlers.DeleteSubstitutionHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/substitutions") {
+		// Facilitator substitution routes: /api/standups/:id/substitutions
+		switch r.Method {
+		case http.MethodGet:
+			handlers.GetSubstitutionsHandler(w, r)
+		case http.MethodPost:
+			handlers.CreateSubstitutionHandler(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/members/import") {
+		// Roster import route: POST /api/standups/:id/members/import
+		if r.Method == http.MethodPost {
+			handlers.ImportStandupMembersHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/members/order") {
+		// Batch reorder route: PUT /api/standups/:id/members/order
+		if r.Method == http.MethodPut {
+			handlers.ReorderStandupMembersHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
 	} else if strings.Contains(r.URL.Path, "/members/") && r.Method == http.MethodDelete {
 		// Remove member route: DELETE /api/standups/:id/members/:user_id
 		handlers.RemoveStandupMemberHandler(w, r)
+	} else if strings.Contains(r.URL.Path, "/members/") && r.Method == http.MethodPut {
+		// Member settings route: PUT /api/standups/:id/members/:user_id
+		handlers.UpdateStandupMemberHandler(w, r)
 	} else if strings.HasSuffix(r.URL.Path, "/members") {
 		// Member management routes: /api/standups/:id/members
 		switch r.Method {
 		case http.MethodGet:
 			handlers.GetStandupMembersHandler(w, r)
+		case http.MethodPost:
+			handlers.AddStandupMembersHandler(w, r)
 		case http.MethodPut:
 			handlers.SetStandupMembersHandler(w, r)
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
+	} else if strings.HasSuffix(r.URL.Path, "/facilitator/nominate") {
+		// Nominate next facilitator route: /api/standups/:id/facilitator/nominate
+		if r.Method == http.MethodPost {
+			handlers.NominateFacilitatorHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/facilitator/history") {
+		// Facilitator history route: /api/standups/:id/facilitator/history?since=YYYY-MM-DD
+		if r.Method == http.MethodGet {
+			handlers.GetFacilitatorHistoryHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/facilitator/stats") {
+		// Facilitator stats route: /api/standups/:id/facilitator/stats?from=YYYY-MM-DD&to=YYYY-MM-DD
+		if r.Method == http.MethodGet {
+			handlers.GetFacilitatorStatsHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/facilitator/skip") {
+		// Skip facilitator route: /api/standups/:id/facilitator/skip
+		if r.Method == http.MethodPost {
+			handlers.SkipFacilitatorHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
 	} else if strings.HasSuffix(r.URL.Path, "/facilitator/rotate") {
 		// Rotate facilitator route: /api/standups/:id/facilitator/rotate
 		if r.Method == http.MethodPost {
@@ -124,6 +293,76 @@ func handleStandupsRoutes(w http.ResponseWriter, r *http.Request) {
 		} else {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
+	} else if strings.HasSuffix(r.URL.Path, "/leaves") {
+		// Leave calendar route: /api/standups/:id/leaves?from=YYYY-MM-DD&to=YYYY-MM-DD
+		if r.Method == http.MethodGet {
+			handlers.GetStandupLeavesHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/history") {
+		// Run history route: /api/standups/:id/history?limit=20&cursor=...
+		if r.Method == http.MethodGet {
+			handlers.GetStandupHistoryHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/fairness") {
+		// Facilitation fairness route: /api/standups/:id/fairness
+		if r.Method == http.MethodGet {
+			handlers.GetStandupFairnessHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/never-facilitated") {
+		// Members yet to facilitate route: /api/standups/:id/never-facilitated
+		if r.Method == http.MethodGet {
+			handlers.GetNeverFacilitatedHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/simulate") {
+		// Week preview route: /api/standups/:id/simulate?days=7
+		if r.Method == http.MethodGet {
+			handlers.SimulateStandupHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/eligible") {
+		// Eligibility audit route: /api/standups/:id/eligible
+		if r.Method == http.MethodGet {
+			handlers.GetStandupEligibilityHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/preview") {
+		// Dry-run preview route: /api/standups/:id/preview
+		if r.Method == http.MethodGet {
+			handlers.PreviewStandupHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/reactivate") {
+		// Reactivate route: POST /api/standups/:id/reactivate
+		if r.Method == http.MethodPost {
+			handlers.ReactivateStandupHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/snooze") {
+		// Snooze route: /api/standups/:id/snooze?minutes=30
+		if r.Method == http.MethodPost {
+			handlers.SnoozeStandupHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	} else if strings.HasSuffix(r.URL.Path, "/force-send") {
+		// Forced reminder route: /api/standups/:id/force-send
+		if r.Method == http.MethodPost {
+			handlers.ForceSendStandupReminderHandler(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
 	} else if strings.HasSuffix(r.URL.Path, "/send") {
 		// Manual reminder route: /api/standups/:id/send
 		if r.Method == http.MethodPost {
@@ -152,55 +391,96 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Leveled (and optionally JSON) logging from LOG_LEVEL / LOG_FORMAT
+	logging.Setup(config.Config.LogLevel, config.Config.LogFormat)
+
+	// Bound webhook POSTs so a hung Google Chat endpoint can't block senders
+	integrations.SetHTTPTimeout(time.Duration(config.Config.WebhookTimeoutSeconds) * time.Second)
+	integrations.SetChatAudience(config.Config.ChatAudience)
+
+	// Configure database query timing (slow-query warnings, debug timings)
+	database.SlowQueryThreshold = time.Duration(config.Config.SlowQueryMs) * time.Millisecond
+	database.LogQueryTimings = config.Config.LogLevel == "debug"
+
 	// Initialize database
-	if err := database.InitDB(config.Config.DatabasePath); err != nil {
+	if err := database.InitDB(config.Config.DatabaseDriver, config.Config.DatabaseDSN()); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer database.CloseDB()
 
+	// Prometheus metrics, served on /metrics
+	metrics.Register(services.CountActiveStandups)
+
 	// Start scheduler
 	if err := services.StartScheduler(); err != nil {
 		log.Fatalf("Failed to start scheduler: %v", err)
 	}
-	defer services.StopScheduler()
 
-	// Set up HTTP routes
+	// Set up HTTP routes. /api/* routes require X-API-Key when API_KEY is set, except
+	// /api/chat/action, which Google Chat calls and which verifies its own token. All /api/*
+	// routes get CORS headers for the origins in CORS_ALLOWED_ORIGINS; the web UI at / is same-origin.
 	http.HandleFunc("/", handlers.HomeHandler)
-	http.HandleFunc("/send", handlers.SendHandler)
+	http.HandleFunc("/send", handlers.RequireAPIKey(handlers.SendHandler))
 	http.HandleFunc("/health", handlers.HealthHandler)
-	http.HandleFunc("/api/send-reminder", handlers.SendReminderHandler)
+	http.HandleFunc("/health/live", handlers.LiveHandler)
+	http.Handle("/metrics", metrics.Handler())
+	http.HandleFunc("/api/send-reminder", handlers.CORS(handlers.RequireAPIKey(handlers.SendReminderHandler)))
+	http.HandleFunc("/api/admin/schema", handlers.CORS(handlers.RequireAPIKey(handlers.GetSchemaHandler)))
+	http.HandleFunc("/api/admin/migrate", handlers.CORS(handlers.RequireAPIKey(handlers.MigrateHandler)))
+	http.HandleFunc("/api/admin/query-stats", handlers.CORS(handlers.RequireAPIKey(handlers.GetQueryStatsHandler)))
+	http.HandleFunc("/api/chat/action", handlers.CORS(handlers.ChatActionHandler))
 
 	// Roster API routes
-	http.HandleFunc("/api/roster", handleRosterRoutes)
-	http.HandleFunc("/api/roster/", handleRosterRoutes)
+	http.HandleFunc("/api/roster", handlers.CORS(handlers.RequireAPIKey(handleRosterRoutes)))
+	http.HandleFunc("/api/roster/", handlers.CORS(handlers.RequireAPIKey(handleRosterRoutes)))
 
 	// Leaves API routes
-	http.HandleFunc("/api/leaves", handleLeavesRoutes)
-	http.HandleFunc("/api/leaves/", handleLeavesRoutes)
+	http.HandleFunc("/api/leaves", handlers.CORS(handlers.RequireAPIKey(handleLeavesRoutes)))
+	http.HandleFunc("/api/leaves/", handlers.CORS(handlers.RequireAPIKey(handleLeavesRoutes)))
+
+	// Holidays API routes
+	http.HandleFunc("/api/holidays", handlers.CORS(handlers.RequireAPIKey(handleHolidaysRoutes)))
+	http.HandleFunc("/api/holidays/", handlers.CORS(handlers.RequireAPIKey(handleHolidaysRoutes)))
 
 	// Standups API routes
-	http.HandleFunc("/api/standups", handleStandupsRoutes)
-	http.HandleFunc("/api/standups/", handleStandupsRoutes)
+	http.HandleFunc("/api/standups", handlers.CORS(handlers.RequireAPIKey(handleStandupsRoutes)))
+	http.HandleFunc("/api/standups/", handlers.CORS(handlers.RequireAPIKey(handleStandupsRoutes)))
 
-	// Graceful shutdown
+	addr := ":" + config.Config.Port
+	srv := &http.Server{Addr: addr}
+
+	// Graceful shutdown: stop the scheduler, let in-flight requests and reminder sends
+	// finish (within shutdownTimeout), then return so the database is closed last
+	shutdownDone := make(chan struct{})
 	go func() {
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 		<-sigChan
 		log.Println("Shutting down gracefully...")
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
 		services.StopScheduler()
-		database.CloseDB()
-		os.Exit(0)
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Printf("Timed out after %v waiting for in-flight requests: %v", shutdownTimeout, err)
+		}
+		deadline, _ := ctx.Deadline()
+		if !services.WaitForSends(time.Until(deadline)) {
+			log.Printf("Timed out after %v waiting for reminder sends; their history stays 'attempting'", shutdownTimeout)
+		}
+		close(shutdownDone)
 	}()
 
 	// Start the server
-	addr := ":" + config.Config.Port
 	log.Printf("🚀 Standup Bot starting on http://localhost%s", addr)
 	log.Printf("📝 Web UI: http://localhost%s", addr)
 	log.Printf("🔧 Health check: http://localhost%s/health", addr)
 	log.Printf("⏰ Scheduler: Running with configured standups")
 
-	if err := http.ListenAndServe(addr, nil); err != nil {
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Failed to start server: %v", err)
 	}
+	<-shutdownDone
+	log.Println("Shutdown complete")
 }
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// RemindersSent counts standup reminders posted successfully
+	RemindersSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "standup_reminders_sent_total",
+		Help: "Standup reminders posted to Google Chat successfully.",
+	})
+
+	// RemindersFailed counts standup reminders whose webhook post failed, per standup
+	RemindersFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "standup_reminders_failed_total",
+		Help: "Standup reminders whose webhook post failed.",
+	}, []string{"standup_id"})
+
+	// WebhookDuration observes how long each webhook POST to Google Chat takes, failures included
+	WebhookDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "webhook_request_duration_seconds",
+		Help:    "Duration of webhook POST requests to Google Chat.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+// Register adds the bot's metrics to the default Prometheus registry. Call it once at startup.
+// countActiveStandups is called on every scrape to report active_standups.
+func Register(countActiveStandups func() (int, error)) {
+	activeStandups := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "active_standups",
+		Help: "Standups that are currently active.",
+	}, func() float64 {
+		count, err := countActiveStandups()
+		if err != nil {
+			slog.Warn("Failed to count active standups for metrics", "error", err)
+			return 0
+		}
+		return float64(count)
+	})
+
+	prometheus.MustRegister(RemindersSent, RemindersFailed, WebhookDuration, activeStandups)
+}
+
+// Handler serves the default registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
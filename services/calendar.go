@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google-chat-bot/config"
+)
+
+// ErrNoCalendarDays is returned when a standup never fires, so it has no calendar event
+var ErrNoCalendarDays = errors.New("standup has no days it runs on (its days_of_week are all weekend days, which SKIP_WEEKENDS skips)")
+
+// calendarEventDuration is how long the standup's calendar event lasts; standups have no length of their own
+const calendarEventDuration = 15 * time.Minute
+
+// icalDays maps the cron day names of days_of_week to iCalendar BYDAY codes
+var icalDays = map[string]string{
+	"SUN": "SU", "MON": "MO", "TUE": "TU", "WED": "WE", "THU": "TH", "FRI": "FR", "SAT": "SA",
+}
+
+// StandupCalendar renders a standup's schedule as an iCalendar file holding one weekly recurring
+// event at run_at in the standup's timezone. Weekends are left out of the recurrence when
+// SKIP_WEEKENDS is on and holidays become EXDATEs, matching the days the scheduler skips.
+// An inactive standup's event is marked cancelled.
+func StandupCalendar(standupID int) (string, error) {
+	standup, err := GetStandupByID(standupID)
+	if err != nil {
+		return "", err
+	}
+
+	runAt, err := time.Parse("15:04", standup.RunAt)
+	if err != nil {
+		return "", fmt.Errorf("invalid run_at time format: %w", err)
+	}
+
+	days := weekdayNames
+	if standup.DaysOfWeek != "" {
+		days = strings.Split(standup.DaysOfWeek, ",")
+	}
+	runsOn := make(map[time.Weekday]bool)
+	var byDay []string
+	for i, name := range weekdayNames {
+		weekday := time.Weekday(i)
+		if !containsString(days, name) {
+			continue
+		}
+		if config.Config.SkipWeekends && (weekday == time.Saturday || weekday == time.Sunday) {
+			continue
+		}
+		runsOn[weekday] = true
+		byDay = append(byDay, icalDays[name])
+	}
+	if len(byDay) == 0 {
+		return "", ErrNoCalendarDays
+	}
+
+	// The series starts on the first day the standup runs on after it was created
+	loc := standupLocation(standup)
+	created := standup.CreatedAt.In(loc)
+	start := time.Date(created.Year(), created.Month(), created.Day(), runAt.Hour(), runAt.Minute(), 0, 0, loc)
+	for !runsOn[start.Weekday()] {
+		start = start.AddDate(0, 0, 1)
+	}
+
+	holidays, err := GetHolidays()
+	if err != nil {
+		return "", err
+	}
+
+	var cal icalWriter
+	cal.line("BEGIN:VCALENDAR")
+	cal.line("VERSION:2.0")
+	cal.line("PRODID:-//google-chat-bot//Standup Bot//EN")
+	cal.line("CALSCALE:GREGORIAN")
+	cal.line("BEGIN:VEVENT")
+	cal.line(fmt.Sprintf("UID:standup-%d@google-chat-bot", standup.ID))
+	cal.line("DTSTAMP:" + now().UTC().Format("20060102T150405Z"))
+	cal.line(icalTime("DTSTART", start, loc))
+	cal.line(fmt.Sprintf("DURATION:PT%dM", int(calendarEventDuration.Minutes())))
+	cal.line("RRULE:FREQ=WEEKLY;BYDAY=" + strings.Join(byDay, ","))
+	for _, holiday := range holidays {
+		day, err := time.ParseInLocation("2006-01-02", holiday.Date, loc)
+		if err != nil || !runsOn[day.Weekday()] {
+			continue
+		}
+		day = time.Date(day.Year(), day.Month(), day.Day(), runAt.Hour(), runAt.Minute(), 0, 0, loc)
+		if day.Before(start) {
+			continue
+		}
+		cal.line(icalTime("EXDATE", day, loc))
+	}
+	cal.line("SUMMARY:" + icalText(standup.Name))
+	cal.line("DESCRIPTION:" + icalText(standup.Message))
+	if !standup.IsActive {
+		cal.line("STATUS:CANCELLED")
+	}
+	cal.line("END:VEVENT")
+	cal.line("END:VCALENDAR")
+
+	return cal.String(), nil
+}
+
+// icalTime formats a date-time property, as UTC or as local time with a TZID parameter
+func icalTime(name string, t time.Time, loc *time.Location) string {
+	if loc == time.UTC {
+		return name + ":" + t.UTC().Format("20060102T150405Z")
+	}
+	return name + ";TZID=" + loc.String() + ":" + t.Format("20060102T150405")
+}
+
+// icalText escapes a TEXT property value
+func icalText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// icalWriter builds an iCalendar file, ending lines with CRLF and folding them at 75 octets
+type icalWriter struct {
+	strings.Builder
+}
+
+// line writes one content line, continuing it on indented lines when it is too long
+func (w *icalWriter) line(content string) {
+	limit := 75
+	for len(content) > limit {
+		// Fold between UTF-8 characters, never inside one
+		cut := limit
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+		limit = 74 // The leading space of a continuation line counts too
+	}
+	w.WriteString(content + "\r\n")
+}
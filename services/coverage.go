@@ -0,0 +1,75 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+)
+
+// maxCoverageDays bounds how many days of a leave are checked for coverage
+const maxCoverageDays = 90
+
+// CoverageWarning flags a standup left without any eligible facilitator on some days
+type CoverageWarning struct {
+	StandupID   int      `json:"standup_id"`
+	StandupName string   `json:"standup_name"`
+	Dates       []string `json:"dates"`
+	Message     string   `json:"message"`
+}
+
+// StandupsLeftEmptyByLeave reports, for each standup the user belongs to, the days within
+// [startDate, endDate] on which no member would be eligible to facilitate if the user were away.
+// The user counts as absent whether or not the leave is stored or approved yet, so a pending
+// leave is warned about the same way as an active one.
+func StandupsLeftEmptyByLeave(userID int, startDate, endDate time.Time) ([]CoverageWarning, error) {
+	standups, err := GetStandupsForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	days := coverageDays(startDate, endDate)
+
+	var warnings []CoverageWarning
+	for _, standup := range standups {
+		var emptyDays []string
+		for _, day := range days {
+			users, err := database.GetEligibleUsersForStandup(standup.ID, day)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check coverage for standup %d: %w", standup.ID, err)
+			}
+			others := len(users)
+			if findUser(users, userID) != nil {
+				others--
+			}
+			if others == 0 {
+				emptyDays = append(emptyDays, day)
+			}
+		}
+
+		if len(emptyDays) > 0 {
+			warnings = append(warnings, CoverageWarning{
+				StandupID:   standup.ID,
+				StandupName: standup.Name,
+				Dates:       emptyDays,
+				Message:     fmt.Sprintf("Standup '%s' has no eligible facilitator on %d day(s)", standup.Name, len(emptyDays)),
+			})
+		}
+	}
+
+	return warnings, nil
+}
+
+// coverageDays lists the standup days (YYYY-MM-DD) in a range, skipping weekends when
+// SKIP_WEEKENDS is on and capping the range at maxCoverageDays
+func coverageDays(startDate, endDate time.Time) []string {
+	var days []string
+	for day, i := startDate, 0; !day.After(endDate) && i < maxCoverageDays; day, i = day.AddDate(0, 0, 1), i+1 {
+		if config.Config.SkipWeekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+		days = append(days, day.Format("2006-01-02"))
+	}
+	return days
+}
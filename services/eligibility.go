@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+
+	"google-chat-bot/database"
+)
+
+// ExclusionReason explains why a standup member was not considered for facilitation
+type ExclusionReason string
+
+const (
+	ExclusionReasonInactive         ExclusionReason = "inactive"
+	ExclusionReasonOnLeave          ExclusionReason = "on_leave"
+	ExclusionReasonCannotFacilitate ExclusionReason = "cannot_facilitate"
+)
+
+// ExcludedMember is a standup member left out of today's eligible set
+type ExcludedMember struct {
+	User   database.User   `json:"user"`
+	Reason ExclusionReason `json:"reason"`
+	Detail string          `json:"detail,omitempty"`
+}
+
+// EligibilityReport lists who a standup would consider for facilitation on a given day, and why the rest were excluded
+type EligibilityReport struct {
+	StandupID  int              `json:"standup_id"`
+	Date       string           `json:"date"`
+	SkipReason SkipReason       `json:"skip_reason,omitempty"` // Why today's scheduled reminder won't run, if it won't
+	SkipDetail string           `json:"skip_detail,omitempty"`
+	Eligible   []database.User  `json:"eligible"`
+	Excluded   []ExcludedMember `json:"excluded"`
+}
+
+// GetStandupEligibility diffs a standup's full member list against today's eligible users, on the
+// standup's own date. Members with can_facilitate off are excluded too, unless nobody eligible
+// can facilitate (the rotation then falls back to everyone eligible).
+func GetStandupEligibility(standupID int) (*EligibilityReport, error) {
+	standup, err := GetStandupByID(standupID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := standupToday(standup)
+
+	members, err := GetStandupMembers(standupID)
+	if err != nil {
+		return nil, err
+	}
+
+	eligible, err := database.GetEligibleUsersForStandup(standupID, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eligible users: %w", err)
+	}
+
+	leaves, err := database.GetActiveLeavesForStandup(standupID, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active leaves: %w", err)
+	}
+
+	settings, err := GetStandupMemberSettings(standupID)
+	if err != nil {
+		return nil, err
+	}
+
+	leaveByUser := make(map[int]database.Leave)
+	for _, leave := range leaves {
+		leaveByUser[leave.UserID] = leave.Leave
+	}
+
+	report := &EligibilityReport{
+		StandupID: standupID,
+		Date:      today,
+		Eligible:  []database.User{},
+		Excluded:  []ExcludedMember{},
+	}
+	report.SkipReason, report.SkipDetail = scheduledRunSkipReason(standup, now())
+
+	var cannotFacilitate []database.User
+	for _, user := range eligible {
+		if member, ok := settings[user.ID]; ok && !member.CanFacilitate {
+			cannotFacilitate = append(cannotFacilitate, user)
+			continue
+		}
+		report.Eligible = append(report.Eligible, user)
+	}
+	if len(report.Eligible) == 0 {
+		report.Eligible = append(report.Eligible, eligible...)
+		cannotFacilitate = nil
+	}
+
+	for _, member := range members {
+		if findUser(report.Eligible, member.ID) != nil {
+			continue
+		}
+
+		excluded := ExcludedMember{User: member}
+		if !member.IsActive {
+			excluded.Reason = ExclusionReasonInactive
+		} else if leave, ok := leaveByUser[member.ID]; ok {
+			excluded.Reason = ExclusionReasonOnLeave
+			excluded.Detail = fmt.Sprintf("%s until %s", leave.LeaveType, leave.EndDate.Format("2006-01-02"))
+		} else if findUser(cannotFacilitate, member.ID) != nil {
+			excluded.Reason = ExclusionReasonCannotFacilitate
+		} else {
+			// Should not happen: excluded without a known reason
+			continue
+		}
+
+		report.Excluded = append(report.Excluded, excluded)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"google-chat-bot/config"
+)
+
+func TestGetStandupEligibility(t *testing.T) {
+	// Tuesday 2026-03-10, 08:00 UTC
+	tuesday := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		at             time.Time
+		timezone       string
+		daysOfWeek     string
+		holiday        string
+		skipWeekends   bool
+		wantDate       string
+		wantSkipReason SkipReason
+	}{
+		{name: "regular day", at: tuesday, wantDate: "2026-03-10"},
+		{name: "weekend", at: tuesday.AddDate(0, 0, 4), skipWeekends: true, wantDate: "2026-03-14", wantSkipReason: SkipReasonWeekend},
+		{name: "holiday", at: tuesday, holiday: "2026-03-10", wantDate: "2026-03-10", wantSkipReason: SkipReasonHoliday},
+		{name: "not in days_of_week", at: tuesday, daysOfWeek: "MON,WED,FRI", wantDate: "2026-03-10", wantSkipReason: SkipReasonNotScheduled},
+		{name: "standup's own date", at: tuesday.Add(15 * time.Hour), timezone: "Pacific/Kiritimati", wantDate: "2026-03-11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			config.Config.SkipWeekends = tt.skipWeekends
+			setNow(tt.at)
+
+			alice := mustCreateUser(t, "alice")
+			bob := mustCreateUser(t, "bob")
+			carol := mustCreateUser(t, "carol")
+			dave := mustCreateUser(t, "dave")
+			standup := mustCreateStandup(t, "daily", alice, bob, carol, dave)
+
+			if err := SetStandupMemberCanFacilitate(standup.ID, bob.ID, false); err != nil {
+				t.Fatalf("SetStandupMemberCanFacilitate: %v", err)
+			}
+			mustCreateApprovedLeave(t, carol, "vacation", "2026-03-01", "2026-03-31")
+			if err := DeactivateUser(dave.ID); err != nil {
+				t.Fatalf("DeactivateUser: %v", err)
+			}
+			if tt.timezone != "" {
+				if err := setStandupField(standup.ID, "timezone", tt.timezone); err != nil {
+					t.Fatalf("set timezone: %v", err)
+				}
+			}
+			if tt.daysOfWeek != "" {
+				if err := SetStandupDaysOfWeek(standup.ID, tt.daysOfWeek); err != nil {
+					t.Fatalf("SetStandupDaysOfWeek: %v", err)
+				}
+			}
+			if tt.holiday != "" {
+				if _, err := CreateHoliday(tt.holiday, "Founders' Day"); err != nil {
+					t.Fatalf("CreateHoliday: %v", err)
+				}
+			}
+
+			report, err := GetStandupEligibility(standup.ID)
+			if err != nil {
+				t.Fatalf("GetStandupEligibility: %v", err)
+			}
+
+			if report.Date != tt.wantDate {
+				t.Errorf("date = %s, want %s", report.Date, tt.wantDate)
+			}
+			if report.SkipReason != tt.wantSkipReason {
+				t.Errorf("skip reason = %q, want %q", report.SkipReason, tt.wantSkipReason)
+			}
+			if len(report.Eligible) != 1 || report.Eligible[0].ID != alice.ID {
+				t.Errorf("eligible = %v, want only alice", report.Eligible)
+			}
+
+			reasons := map[int]ExclusionReason{}
+			for _, excluded := range report.Excluded {
+				reasons[excluded.User.ID] = excluded.Reason
+			}
+			want := map[int]ExclusionReason{
+				bob.ID:   ExclusionReasonCannotFacilitate,
+				carol.ID: ExclusionReasonOnLeave,
+				dave.ID:  ExclusionReasonInactive,
+			}
+			for userID, reason := range want {
+				if reasons[userID] != reason {
+					t.Errorf("user %d excluded as %q, want %q", userID, reasons[userID], reason)
+				}
+			}
+		})
+	}
+}
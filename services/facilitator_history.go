@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google-chat-bot/database"
+)
+
+// recordFacilitation stores that userID facilitated today's sent reminder and logs (rather than
+// returns) any failure, so history problems never block the rotation
+func recordFacilitation(standupID, userID int) {
+	defer database.TimeQuery("recordFacilitation", time.Now())
+
+	_, err := database.DB.Exec(
+		"INSERT INTO facilitator_history (standup_id, user_id, facilitated_on) VALUES (?, ?, ?)",
+		standupID, userID, StandupToday(standupID),
+	)
+	if err != nil {
+		slog.Warn("Failed to record facilitator history", "standup_id", standupID, "error", err)
+	}
+}
+
+// GetFacilitatorHistory lists who facilitated a standup's sent reminders, oldest first, with
+// user details. since (YYYY-MM-DD, inclusive) limits the list when non-empty.
+func GetFacilitatorHistory(standupID int, since string) ([]database.FacilitatorHistoryEntry, error) {
+	defer database.TimeQuery("GetFacilitatorHistory", time.Now())
+
+	if _, err := GetStandupByID(standupID); err != nil {
+		return nil, err
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT id, standup_id, user_id, facilitated_on, created_at
+		FROM facilitator_history
+		WHERE standup_id = ? AND (? = '' OR facilitated_on >= ?)
+		ORDER BY facilitated_on, id
+	`, standupID, since, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get facilitator history: %w", err)
+	}
+
+	history := []database.FacilitatorHistoryEntry{}
+	for rows.Next() {
+		var entry database.FacilitatorHistoryEntry
+		if err := rows.Scan(&entry.ID, &entry.StandupID, &entry.UserID, &entry.FacilitatedOn, &entry.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan facilitator history: %w", err)
+		}
+		history = append(history, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read facilitator history: %w", err)
+	}
+
+	// The same few members facilitate over and over, so look each one up once
+	users := make(map[int]*database.User)
+	for i := range history {
+		user, ok := users[history[i].UserID]
+		if !ok {
+			user, _ = GetUserByID(history[i].UserID)
+			users[history[i].UserID] = user
+		}
+		history[i].User = user
+	}
+
+	return history, nil
+}
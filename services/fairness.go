@@ -0,0 +1,195 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"google-chat-bot/database"
+)
+
+// fairnessImbalanceThreshold is the spread between the most and least frequent
+// current facilitators at which a rotation is flagged as imbalanced. A fair rotation
+// interrupted by leaves stays within one facilitation of even.
+const fairnessImbalanceThreshold = 2
+
+// FacilitationCount is how many sent reminders a user facilitated for a standup
+type FacilitationCount struct {
+	User     database.User `json:"user"`
+	Count    int           `json:"count"`
+	IsMember bool          `json:"is_member"` // false for people who have since left the standup
+}
+
+// FairnessReport summarises facilitation counts from a standup's run history
+type FairnessReport struct {
+	StandupID  int                 `json:"standup_id"`
+	TotalRuns  int                 `json:"total_runs"`
+	Counts     []FacilitationCount `json:"counts"`
+	Spread     int                 `json:"spread"` // most minus least facilitations among current members
+	Imbalanced bool                `json:"imbalanced"`
+}
+
+// GetFacilitationFairness counts the sent runs each person facilitated, including current
+// members who have never facilitated, sorted by count descending
+func GetFacilitationFairness(standupID int) (*FairnessReport, error) {
+	defer database.TimeQuery("GetFacilitationFairness", time.Now())
+
+	if _, err := GetStandupByID(standupID); err != nil {
+		return nil, err
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT facilitator_id, COUNT(*)
+		FROM standup_runs
+		WHERE standup_id = ? AND status = ? AND facilitator_id IS NOT NULL
+		GROUP BY facilitator_id
+	`, standupID, RunStatusSent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count facilitations: %w", err)
+	}
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var userID, count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan facilitation count: %w", err)
+		}
+		counts[userID] = count
+	}
+	rows.Close()
+
+	members, err := GetStandupMembers(standupID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FairnessReport{
+		StandupID: standupID,
+		Counts:    []FacilitationCount{},
+	}
+
+	minCount, maxCount := -1, 0
+	for _, member := range members {
+		count := counts[member.ID]
+		delete(counts, member.ID)
+
+		report.Counts = append(report.Counts, FacilitationCount{User: member, Count: count, IsMember: true})
+		report.TotalRuns += count
+
+		if minCount == -1 || count < minCount {
+			minCount = count
+		}
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	// Former members still show up so their share of the history is visible
+	for userID, count := range counts {
+		user, err := GetUserByID(userID)
+		if err != nil {
+			continue
+		}
+		report.Counts = append(report.Counts, FacilitationCount{User: *user, Count: count})
+		report.TotalRuns += count
+	}
+
+	sort.SliceStable(report.Counts, func(i, j int) bool {
+		if report.Counts[i].Count != report.Counts[j].Count {
+			return report.Counts[i].Count > report.Counts[j].Count
+		}
+		return report.Counts[i].User.DisplayName < report.Counts[j].User.DisplayName
+	})
+
+	if minCount >= 0 {
+		report.Spread = maxCount - minCount
+	}
+	report.Imbalanced = report.Spread >= fairnessImbalanceThreshold
+
+	return report, nil
+}
+
+// GetNeverFacilitated returns the current members who have not facilitated a sent reminder
+// for the standup yet, in rotation order
+func GetNeverFacilitated(standupID int) ([]database.User, error) {
+	defer database.TimeQuery("GetNeverFacilitated", time.Now())
+
+	if _, err := GetStandupByID(standupID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT u.id, u.google_chat_user_id, u.display_name, u.email, u.is_active,
+		       u.joined_at, u.left_at, u.created_at, u.updated_at
+		FROM users u
+		LEFT JOIN standup_runs r
+			ON r.facilitator_id = u.id AND r.standup_id = ? AND r.status = ?
+		WHERE ` + database.StandupMemberFilter + ` AND r.id IS NULL
+		ORDER BY ` + database.StandupMemberOrder + `
+	`
+
+	users, err := queryUsers(query, standupID, RunStatusSent, standupID, standupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members who never facilitated: %w", err)
+	}
+
+	return users, nil
+}
+
+// FacilitatorStat is how many times a member facilitated a standup within a window, and when
+// they last did
+type FacilitatorStat struct {
+	User              database.User `json:"user"`
+	Count             int           `json:"count"`
+	LastFacilitatedOn *string       `json:"last_facilitated_on"` // YYYY-MM-DD; null when they had no turn in the window
+}
+
+// GetFacilitatorStats counts each current member's turns in the facilitator history between
+// from and to (YYYY-MM-DD, inclusive; empty leaves that side open), in rotation order. Members
+// with no turns are included with a count of 0.
+func GetFacilitatorStats(standupID int, from, to string) ([]FacilitatorStat, error) {
+	defer database.TimeQuery("GetFacilitatorStats", time.Now())
+
+	if _, err := GetStandupByID(standupID); err != nil {
+		return nil, err
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT user_id, COUNT(*), MAX(facilitated_on)
+		FROM facilitator_history
+		WHERE standup_id = ?
+		AND (? = '' OR facilitated_on >= ?)
+		AND (? = '' OR facilitated_on <= ?)
+		GROUP BY user_id
+	`, standupID, from, from, to, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count facilitator turns: %w", err)
+	}
+
+	turns := make(map[int]FacilitatorStat)
+	for rows.Next() {
+		var userID int
+		var stat FacilitatorStat
+		if err := rows.Scan(&userID, &stat.Count, &stat.LastFacilitatedOn); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan facilitator turns: %w", err)
+		}
+		turns[userID] = stat
+	}
+	rows.Close()
+
+	members, err := GetStandupMembers(standupID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]FacilitatorStat, 0, len(members))
+	for _, member := range members {
+		stat := turns[member.ID]
+		stat.User = member
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"google-chat-bot/database"
+)
+
+// ErrInvalidCursor is returned when a history cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// dbTimestampLayout matches how CURRENT_TIMESTAMP values are stored in sent_at and created_at (UTC)
+const dbTimestampLayout = "2006-01-02 15:04:05"
+
+// Run statuses recorded in standup_runs
+const (
+	RunStatusSent    = "sent"
+	RunStatusSkipped = "skipped"
+	RunStatusFailed  = "failed"
+	RunStatusSnoozed = "snoozed"
+	// RunStatusAttempting is written before the webhook call and replaced once it returns,
+	// so a process killed mid-send still leaves a trace
+	RunStatusAttempting = "attempting"
+)
+
+// Run triggers recorded in standup_runs
+const (
+	RunTriggerScheduled = "scheduled"
+	RunTriggerManual    = "manual"
+	RunTriggerSnooze    = "snooze"
+	RunTriggerForced    = "forced"
+	RunTriggerRetry     = "retry" // Re-check after a scheduled send found nobody eligible
+)
+
+// RecordStandupRun stores the outcome of a reminder attempt
+func RecordStandupRun(run database.StandupRun) error {
+	defer database.TimeQuery("RecordStandupRun", time.Now())
+
+	query := `
+		INSERT INTO standup_runs (standup_id, run_date, status, skip_reason, trigger, facilitator_id, detail)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := database.DB.Exec(query,
+		run.StandupID,
+		run.RunDate,
+		run.Status,
+		run.SkipReason,
+		run.Trigger,
+		run.FacilitatorID,
+		run.Detail,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record standup run: %w", err)
+	}
+
+	return nil
+}
+
+// recordRun records a run for today and logs (rather than returns) any failure,
+// so history problems never block sending
+func recordRun(standupID int, status string, reason SkipReason, trigger string, facilitator *database.User, detail string) {
+	run := database.StandupRun{
+		StandupID:  standupID,
+		RunDate:    StandupToday(standupID),
+		Status:     status,
+		SkipReason: string(reason),
+		Trigger:    trigger,
+		Detail:     detail,
+	}
+	if facilitator != nil {
+		run.FacilitatorID = &facilitator.ID
+	}
+
+	if err := RecordStandupRun(run); err != nil {
+		slog.Warn("Failed to record standup run", "standup_id", standupID, "error", err)
+	}
+}
+
+// startRun records an in-progress send for today and returns its ID, or 0 if it could not be
+// stored (logged, like recordRun, so history problems never block sending)
+func startRun(standupID int, trigger string, facilitator *database.User) int64 {
+	var facilitatorID *int
+	if facilitator != nil {
+		facilitatorID = &facilitator.ID
+	}
+
+	var id int64
+	err := database.DB.QueryRow(`
+		INSERT INTO standup_runs (standup_id, run_date, status, trigger, facilitator_id)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`, standupID, StandupToday(standupID), RunStatusAttempting, trigger, facilitatorID).Scan(&id)
+	if err != nil {
+		slog.Warn("Failed to record standup run", "standup_id", standupID, "error", err)
+		return 0
+	}
+	return id
+}
+
+// finishRun sets the final status of a run started with startRun. When the attempting row
+// could not be stored, the outcome is recorded as a new run instead.
+func finishRun(runID int64, standupID int, status, trigger string, facilitator *database.User, detail string) {
+	if runID == 0 {
+		recordRun(standupID, status, "", trigger, facilitator, detail)
+		return
+	}
+
+	_, err := database.DB.Exec(
+		"UPDATE standup_runs SET status = ?, detail = ? WHERE id = ?",
+		status, detail, runID,
+	)
+	if err != nil {
+		slog.Warn("Failed to finalize standup run", "run_id", runID, "error", err)
+	}
+}
+
+// HasRunWithStatus reports whether a standup has a run with the given status on a day (YYYY-MM-DD)
+func HasRunWithStatus(standupID int, day, status string) (bool, error) {
+	var count int
+	err := database.DB.QueryRow(
+		"SELECT COUNT(*) FROM standup_runs WHERE standup_id = ? AND run_date = ? AND status = ?",
+		standupID, day, status,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check standup runs: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// HasRunWithTrigger reports whether a standup has a run started by the given trigger on the given day (YYYY-MM-DD)
+func HasRunWithTrigger(standupID int, day, trigger string) (bool, error) {
+	var count int
+	err := database.DB.QueryRow(
+		"SELECT COUNT(*) FROM standup_runs WHERE standup_id = ? AND run_date = ? AND trigger = ?",
+		standupID, day, trigger,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check standup runs: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetStandupRuns returns up to limit runs for a standup, newest first, starting after the
+// given cursor ("" for the first page). Paging is keyset-based on (sent_at, id) so it stays
+// cheap for long histories. The returned cursor is "" when there are no more runs.
+func GetStandupRuns(standupID, limit int, cursor string) ([]database.StandupRun, string, error) {
+	defer database.TimeQuery("GetStandupRuns", time.Now())
+
+	query := `
+		SELECT id, standup_id, run_date, status, skip_reason, trigger, facilitator_id, detail, sent_at
+		FROM standup_runs
+		WHERE standup_id = ?
+	`
+	args := []interface{}{standupID}
+
+	if cursor != "" {
+		sentAt, id, err := decodeRunCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += ` AND (sent_at < ? OR (sent_at = ? AND id < ?))`
+		args = append(args, sentAt, sentAt, id)
+	}
+
+	// Fetch one extra row to know whether another page exists
+	query += ` ORDER BY sent_at DESC, id DESC LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query standup runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []database.StandupRun{}
+	for rows.Next() {
+		var run database.StandupRun
+		var facilitatorID sql.NullInt64
+		err := rows.Scan(
+			&run.ID,
+			&run.StandupID,
+			&run.RunDate,
+			&run.Status,
+			&run.SkipReason,
+			&run.Trigger,
+			&facilitatorID,
+			&run.Detail,
+			&run.SentAt,
+		)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan standup run: %w", err)
+		}
+
+		if facilitatorID.Valid {
+			id := int(facilitatorID.Int64)
+			run.FacilitatorID = &id
+		}
+
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to read standup runs: %w", err)
+	}
+
+	nextCursor := ""
+	if len(runs) > limit {
+		runs = runs[:limit]
+		last := runs[len(runs)-1]
+		nextCursor = encodeRunCursor(last.SentAt, last.ID)
+	}
+
+	return runs, nextCursor, nil
+}
+
+// encodeRunCursor builds an opaque cursor pointing just past the given run
+func encodeRunCursor(sentAt time.Time, id int) string {
+	raw := sentAt.UTC().Format(dbTimestampLayout) + "|" + strconv.Itoa(id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeRunCursor returns the sent_at text and id encoded in a cursor
+func decodeRunCursor(cursor string) (string, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, ErrInvalidCursor
+	}
+
+	sentAt, idStr, found := strings.Cut(string(raw), "|")
+	if !found {
+		return "", 0, ErrInvalidCursor
+	}
+	if _, err := time.Parse(dbTimestampLayout, sentAt); err != nil {
+		return "", 0, ErrInvalidCursor
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return "", 0, ErrInvalidCursor
+	}
+
+	return sentAt, id, nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"google-chat-bot/database"
+)
+
+var (
+	// ErrHolidayNotFound is returned when no holiday has the given ID
+	ErrHolidayNotFound = errors.New("holiday not found")
+	// ErrHolidayExists is returned when a holiday is already set on the date
+	ErrHolidayExists = errors.New("a holiday already exists on that date")
+)
+
+// holidayColumns is the column list shared by holiday queries, in scanHoliday order
+const holidayColumns = `id, date, name, created_at`
+
+// scanHoliday scans a single holiday row selected with holidayColumns
+func scanHoliday(row interface{ Scan(...interface{}) error }) (database.Holiday, error) {
+	var holiday database.Holiday
+	err := row.Scan(
+		&holiday.ID,
+		&holiday.Date,
+		&holiday.Name,
+		&holiday.CreatedAt,
+	)
+	return holiday, err
+}
+
+// CreateHoliday adds a holiday on date (YYYY-MM-DD)
+func CreateHoliday(date, name string) (*database.Holiday, error) {
+	existing, err := HolidayOn(date)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrHolidayExists
+	}
+
+	var id int
+	err = database.DB.QueryRow("INSERT INTO holidays (date, name) VALUES (?, ?) RETURNING id", date, name).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create holiday: %w", err)
+	}
+
+	return GetHolidayByID(id)
+}
+
+// GetHolidayByID retrieves a holiday by ID
+func GetHolidayByID(id int) (*database.Holiday, error) {
+	holiday, err := scanHoliday(database.DB.QueryRow(`SELECT `+holidayColumns+` FROM holidays WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrHolidayNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday: %w", err)
+	}
+
+	return &holiday, nil
+}
+
+// GetHolidays lists all holidays, earliest first
+func GetHolidays() ([]database.Holiday, error) {
+	rows, err := database.DB.Query(`SELECT ` + holidayColumns + ` FROM holidays ORDER BY date`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := []database.Holiday{}
+	for rows.Next() {
+		holiday, err := scanHoliday(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan holiday: %w", err)
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	return holidays, rows.Err()
+}
+
+// UpdateHoliday changes a holiday's date and name
+func UpdateHoliday(id int, date, name string) error {
+	existing, err := HolidayOn(date)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return ErrHolidayExists
+	}
+
+	result, err := database.DB.Exec("UPDATE holidays SET date = ?, name = ? WHERE id = ?", date, name, id)
+	if err != nil {
+		return fmt.Errorf("failed to update holiday: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrHolidayNotFound
+	}
+
+	return nil
+}
+
+// DeleteHoliday removes a holiday
+func DeleteHoliday(id int) error {
+	result, err := database.DB.Exec("DELETE FROM holidays WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete holiday: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrHolidayNotFound
+	}
+
+	return nil
+}
+
+// ImportHolidays adds many holidays in one transaction. Dates that already have a holiday are
+// left as they are; the counts of added and already present holidays are returned.
+func ImportHolidays(holidays []database.Holiday) (imported, skipped int, err error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, holiday := range holidays {
+		result, err := tx.Exec("INSERT INTO holidays (date, name) VALUES (?, ?) ON CONFLICT (date) DO NOTHING", holiday.Date, holiday.Name)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to import holiday %s: %w", holiday.Date, err)
+		}
+
+		if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
+			imported++
+		} else {
+			skipped++
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return imported, skipped, nil
+}
+
+// HolidayOn returns the holiday on date (YYYY-MM-DD), or nil when the date is a regular day
+func HolidayOn(date string) (*database.Holiday, error) {
+	defer database.TimeQuery("HolidayOn", time.Now())
+
+	holiday, err := scanHoliday(database.DB.QueryRow(`SELECT `+holidayColumns+` FROM holidays WHERE date = ?`, date))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check holiday: %w", err)
+	}
+
+	return &holiday, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+)
+
+var (
+	// ErrIdempotencyKeyInProgress is returned while the first request with a key is still running
+	ErrIdempotencyKeyInProgress = errors.New("a request with this Idempotency-Key is still being processed")
+	// ErrIdempotencyKeyReused is returned when a key comes back with a different request body
+	ErrIdempotencyKeyReused = errors.New("this Idempotency-Key was already used with a different request body")
+)
+
+// IdempotentResponse is the stored response to a completed request with an Idempotency-Key
+type IdempotentResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+// idempotencyCutoff is the created_at (UTC, as CURRENT_TIMESTAMP stores it) at or before which a key has expired
+func idempotencyCutoff() string {
+	ttl := time.Duration(config.Config.IdempotencyKeyTTLHours) * time.Hour
+	return time.Now().UTC().Add(-ttl).Format(dbTimestampLayout)
+}
+
+// BeginIdempotentRequest claims key for endpoint. It returns the stored response when a request
+// with the key already completed, and nil when the caller now holds the key and must finish with
+// CompleteIdempotentRequest or ReleaseIdempotentRequest. Keys older than IDEMPOTENCY_KEY_TTL_HOURS
+// are treated as unused.
+func BeginIdempotentRequest(key, endpoint, requestHash string) (*IdempotentResponse, error) {
+	defer database.TimeQuery("BeginIdempotentRequest", time.Now())
+
+	_, err := database.DB.Exec(
+		"DELETE FROM idempotency WHERE key = ? AND endpoint = ? AND created_at <= ?",
+		key, endpoint, idempotencyCutoff(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear expired idempotency key: %w", err)
+	}
+
+	_, err = database.DB.Exec(
+		"INSERT INTO idempotency (key, endpoint, request_hash) VALUES (?, ?, ?)",
+		key, endpoint, requestHash,
+	)
+	if err == nil {
+		return nil, nil
+	}
+	if !database.IsUniqueViolation(err) {
+		return nil, fmt.Errorf("failed to store idempotency key: %w", err)
+	}
+
+	var storedHash, body string
+	var status int
+	err = database.DB.QueryRow(
+		"SELECT request_hash, status_code, response_body FROM idempotency WHERE key = ? AND endpoint = ?",
+		key, endpoint,
+	).Scan(&storedHash, &status, &body)
+	if err == sql.ErrNoRows {
+		// The first request failed and released the key just now; the client can retry
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	if storedHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if status == 0 {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return &IdempotentResponse{StatusCode: status, Body: []byte(body)}, nil
+}
+
+// CompleteIdempotentRequest stores the response to a request claimed with BeginIdempotentRequest
+func CompleteIdempotentRequest(key, endpoint string, response IdempotentResponse) error {
+	defer database.TimeQuery("CompleteIdempotentRequest", time.Now())
+
+	_, err := database.DB.Exec(
+		"UPDATE idempotency SET status_code = ?, response_body = ? WHERE key = ? AND endpoint = ?",
+		response.StatusCode, string(response.Body), key, endpoint,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotentRequest frees a claimed key without storing a response, so a retry runs again
+func ReleaseIdempotentRequest(key, endpoint string) error {
+	defer database.TimeQuery("ReleaseIdempotentRequest", time.Now())
+
+	_, err := database.DB.Exec("DELETE FROM idempotency WHERE key = ? AND endpoint = ?", key, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// PurgeIdempotencyKeys deletes keys older than IDEMPOTENCY_KEY_TTL_HOURS and returns how many were removed
+func PurgeIdempotencyKeys() (int64, error) {
+	defer database.TimeQuery("PurgeIdempotencyKeys", time.Now())
+
+	result, err := database.DB.Exec(
+		"DELETE FROM idempotency WHERE created_at <= ?",
+		idempotencyCutoff(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+
+	removed, _ := result.RowsAffected()
+	return removed, nil
+}
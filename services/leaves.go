@@ -1,43 +1,164 @@
 package services
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"google-chat-bot/config"
 	"google-chat-bot/database"
 )
 
-// CreateLeave adds a new leave record
-func CreateLeave(userID int, leaveType string, startDate, endDate time.Time, reason string) (*database.Leave, error) {
+var (
+	// ErrLeaveNotFound is returned when no leave has the given ID
+	ErrLeaveNotFound = errors.New("leave not found")
+	// ErrLeaveAlreadyActive is returned when activating a leave that is already active
+	ErrLeaveAlreadyActive = errors.New("leave is already active")
+	// ErrLeaveEnded is returned when activating a leave whose end date has passed
+	ErrLeaveEnded = errors.New("leave has already ended")
+	// ErrInvalidLeaveTransition is returned when a leave cannot move from its current status to the requested one
+	ErrInvalidLeaveTransition = errors.New("invalid leave status transition")
+	// ErrInvalidDateRange is returned when a range ends before it starts or spans too many days
+	ErrInvalidDateRange = fmt.Errorf("to must not be before from, and the range may span at most %d days", maxLeaveRangeDays)
+	// ErrApproverRequired is returned when approving a leave without saying who approved it
+	ErrApproverRequired = errors.New("approved_by is required")
+	// ErrInvalidDayPortion is returned for a day portion other than full, morning or afternoon
+	ErrInvalidDayPortion = errors.New("day_portion must be 'full', 'morning' or 'afternoon'")
+)
+
+// ValidDayPortion reports whether a leave day portion is supported
+func ValidDayPortion(portion string) bool {
+	return portion == database.DayPortionFull || portion == database.DayPortionMorning ||
+		portion == database.DayPortionAfternoon
+}
+
+// maxLeaveRangeDays caps the window accepted by range queries over leaves
+const maxLeaveRangeDays = 92
+
+// Leave statuses
+const (
+	LeaveStatusPending   = "pending"
+	LeaveStatusActive    = "active"
+	LeaveStatusCompleted = "completed"
+	LeaveStatusCancelled = "cancelled"
+	LeaveStatusRejected  = "rejected"
+)
+
+// leaveTransitions lists the statuses each leave status may move to.
+// A completed or cancelled leave can only be reactivated, never moved between terminal states;
+// ActivateLeave sends one that was never approved back to pending instead of active.
+// A pending leave only becomes active through ApproveLeave; a rejected one stays rejected.
+var leaveTransitions = map[string][]string{
+	LeaveStatusPending:   {LeaveStatusRejected, LeaveStatusCancelled},
+	LeaveStatusActive:    {LeaveStatusCompleted, LeaveStatusCancelled},
+	LeaveStatusCompleted: {LeaveStatusActive, LeaveStatusPending},
+	LeaveStatusCancelled: {LeaveStatusActive, LeaveStatusPending},
+}
+
+// canTransitionLeave reports whether a leave may move from one status to another
+func canTransitionLeave(from, to string) bool {
+	for _, allowed := range leaveTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionLeave moves a leave to a new status after checking the transition is allowed.
+// The update is conditional on the status read, so a concurrent change is reported rather than overwritten.
+func transitionLeave(id int, to string) error {
+	leave, err := GetLeaveByID(id)
+	if err != nil {
+		return ErrLeaveNotFound
+	}
+
+	if !canTransitionLeave(leave.Status, to) {
+		return fmt.Errorf("%w: cannot move a %s leave to %s", ErrInvalidLeaveTransition, leave.Status, to)
+	}
+
 	query := `
-		INSERT INTO leaves (user_id, leave_type, start_date, end_date, reason, status)
-		VALUES (?, ?, ?, ?, ?, 'active')
+		UPDATE leaves
+		SET status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?
 	`
 
-	result, err := database.DB.Exec(query, userID, leaveType, startDate, endDate, reason)
+	result, err := database.DB.Exec(query, to, id, leave.Status)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create leave: %w", err)
+		return fmt.Errorf("failed to set leave status to %s: %w", to, err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: leave status changed concurrently", ErrInvalidLeaveTransition)
+	}
+
+	return nil
+}
+
+// now is the clock used for "today" calculations; replaceable for testing
+var now = time.Now
+
+// Today returns the current date (YYYY-MM-DD) in the configured timezone
+func Today() string {
+	return now().In(config.Config.Location()).Format("2006-01-02")
+}
+
+// CreateLeave adds a new leave record, pending until a manager approves it.
+// dayPortion is "full", "morning" or "afternoon" ("" = full).
+func CreateLeave(userID int, leaveType string, startDate, endDate time.Time, reason, dayPortion string) (*database.Leave, error) {
+	if dayPortion == "" {
+		dayPortion = database.DayPortionFull
 	}
+	if !ValidDayPortion(dayPortion) {
+		return nil, ErrInvalidDayPortion
+	}
+
+	query := `
+		INSERT INTO leaves (user_id, leave_type, start_date, end_date, reason, day_portion, status)
+		VALUES (?, ?, ?, ?, ?, ?, 'pending')
+		RETURNING id
+	`
 
-	id, err := result.LastInsertId()
+	var id int
+	err := database.DB.QueryRow(query, userID, leaveType, startDate, endDate, reason, dayPortion).Scan(&id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+		return nil, fmt.Errorf("failed to create leave: %w", err)
 	}
 
-	return GetLeaveByID(int(id))
+	return GetLeaveByID(id)
 }
 
 // GetLeaveByID retrieves a leave by ID
 func GetLeaveByID(id int) (*database.Leave, error) {
 	query := `
-		SELECT id, user_id, leave_type, start_date, end_date, reason, status,
-		       created_at, updated_at
+		SELECT ` + leaveColumns + `
 		FROM leaves
 		WHERE id = ?
 	`
 
-	var leave database.Leave
-	err := database.DB.QueryRow(query, id).Scan(
+	leave, err := scanLeave(database.DB.QueryRow(query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leave: %w", err)
+	}
+
+	return &leave, nil
+}
+
+// leaveColumns is the column list shared by all leave queries, in scanLeave order
+const leaveColumns = `id, user_id, leave_type, start_date, end_date, reason, status,
+	       day_portion, created_at, updated_at, approved_by, approved_at`
+
+// scanLeave scans a single leave row selected with leaveColumns
+func scanLeave(row interface{ Scan(...interface{}) error }) (database.Leave, error) {
+	var (
+		leave      database.Leave
+		approvedBy sql.NullString
+		approvedAt sql.NullTime
+	)
+	err := row.Scan(
 		&leave.ID,
 		&leave.UserID,
 		&leave.LeaveType,
@@ -45,27 +166,22 @@ func GetLeaveByID(id int) (*database.Leave, error) {
 		&leave.EndDate,
 		&leave.Reason,
 		&leave.Status,
+		&leave.DayPortion,
 		&leave.CreatedAt,
 		&leave.UpdatedAt,
+		&approvedBy,
+		&approvedAt,
 	)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get leave: %w", err)
+	leave.ApprovedBy = approvedBy.String
+	if approvedAt.Valid {
+		leave.ApprovedAt = &approvedAt.Time
 	}
-
-	return &leave, nil
+	return leave, err
 }
 
-// GetAllLeaves retrieves all leave records
-func GetAllLeaves() ([]database.Leave, error) {
-	query := `
-		SELECT id, user_id, leave_type, start_date, end_date, reason, status,
-		       created_at, updated_at
-		FROM leaves
-		ORDER BY start_date DESC
-	`
-
-	rows, err := database.DB.Query(query)
+// queryLeaves runs a leave query and scans every returned row
+func queryLeaves(query string, args ...interface{}) ([]database.Leave, error) {
+	rows, err := database.DB.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query leaves: %w", err)
 	}
@@ -73,18 +189,7 @@ func GetAllLeaves() ([]database.Leave, error) {
 
 	var leaves []database.Leave
 	for rows.Next() {
-		var leave database.Leave
-		err := rows.Scan(
-			&leave.ID,
-			&leave.UserID,
-			&leave.LeaveType,
-			&leave.StartDate,
-			&leave.EndDate,
-			&leave.Reason,
-			&leave.Status,
-			&leave.CreatedAt,
-			&leave.UpdatedAt,
-		)
+		leave, err := scanLeave(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan leave: %w", err)
 		}
@@ -94,96 +199,206 @@ func GetAllLeaves() ([]database.Leave, error) {
 	return leaves, nil
 }
 
+// GetAllLeaves retrieves all leave records
+func GetAllLeaves() ([]database.Leave, error) {
+	defer database.TimeQuery("GetAllLeaves", time.Now())
+
+	query := `
+		SELECT ` + leaveColumns + `
+		FROM leaves
+		ORDER BY start_date DESC
+	`
+
+	return queryLeaves(query)
+}
+
+// GetAllLeavesPaginated retrieves a page of leave records along with the total count
+func GetAllLeavesPaginated(limit, offset int) ([]database.Leave, int, error) {
+	var total int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM leaves").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count leaves: %w", err)
+	}
+
+	query := `
+		SELECT ` + leaveColumns + `
+		FROM leaves
+		ORDER BY start_date DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	leaves, err := queryLeaves(query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return leaves, total, nil
+}
+
+// activeLeavesCondition matches leaves in effect on a day; bind the day twice
+const activeLeavesCondition = `status = 'active'
+		AND date(start_date) <= ?
+		AND date(end_date) >= ?`
+
 // GetActiveLeaves retrieves all currently active leaves
 func GetActiveLeaves() ([]database.Leave, error) {
+	defer database.TimeQuery("GetActiveLeaves", time.Now())
+
 	query := `
-		SELECT id, user_id, leave_type, start_date, end_date, reason, status,
-		       created_at, updated_at
+		SELECT ` + leaveColumns + `
 		FROM leaves
-		WHERE status = 'active'
-		AND start_date <= date('now')
-		AND end_date >= date('now')
+		WHERE ` + activeLeavesCondition + `
 		ORDER BY start_date DESC
 	`
 
-	rows, err := database.DB.Query(query)
+	today := Today()
+	return queryLeaves(query, today, today)
+}
+
+// GetActiveLeavesPaginated retrieves a page of currently active leaves along with the total count
+func GetActiveLeavesPaginated(limit, offset int) ([]database.Leave, int, error) {
+	today := Today()
+
+	var total int
+	err := database.DB.QueryRow("SELECT COUNT(*) FROM leaves WHERE "+activeLeavesCondition, today, today).Scan(&total)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query active leaves: %w", err)
+		return nil, 0, fmt.Errorf("failed to count active leaves: %w", err)
 	}
-	defer rows.Close()
 
-	var leaves []database.Leave
-	for rows.Next() {
-		var leave database.Leave
-		err := rows.Scan(
-			&leave.ID,
-			&leave.UserID,
-			&leave.LeaveType,
-			&leave.StartDate,
-			&leave.EndDate,
-			&leave.Reason,
-			&leave.Status,
-			&leave.CreatedAt,
-			&leave.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan leave: %w", err)
-		}
-		leaves = append(leaves, leave)
+	query := `
+		SELECT ` + leaveColumns + `
+		FROM leaves
+		WHERE ` + activeLeavesCondition + `
+		ORDER BY start_date DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	leaves, err := queryLeaves(query, today, today, limit, offset)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return leaves, nil
+	return leaves, total, nil
+}
+
+// ValidLeaveStatus reports whether status is one of the leave statuses
+func ValidLeaveStatus(status string) bool {
+	switch status {
+	case LeaveStatusPending, LeaveStatusActive, LeaveStatusCompleted, LeaveStatusCancelled, LeaveStatusRejected:
+		return true
+	}
+	return false
+}
+
+// GetLeavesByStatus retrieves all leaves in the given status, whatever their dates
+func GetLeavesByStatus(status string) ([]database.Leave, error) {
+	defer database.TimeQuery("GetLeavesByStatus", time.Now())
+
+	query := `
+		SELECT ` + leaveColumns + `
+		FROM leaves
+		WHERE status = ?
+		ORDER BY start_date DESC
+	`
+
+	return queryLeaves(query, status)
+}
+
+// GetLeavesByStatusPaginated retrieves a page of leaves in the given status along with the total count
+func GetLeavesByStatusPaginated(status string, limit, offset int) ([]database.Leave, int, error) {
+	var total int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM leaves WHERE status = ?", status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count leaves: %w", err)
+	}
+
+	query := `
+		SELECT ` + leaveColumns + `
+		FROM leaves
+		WHERE status = ?
+		ORDER BY start_date DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	leaves, err := queryLeaves(query, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return leaves, total, nil
 }
 
 // GetLeavesByUserID retrieves all leaves for a specific user
 func GetLeavesByUserID(userID int) ([]database.Leave, error) {
 	query := `
-		SELECT id, user_id, leave_type, start_date, end_date, reason, status,
-		       created_at, updated_at
+		SELECT ` + leaveColumns + `
 		FROM leaves
 		WHERE user_id = ?
 		ORDER BY start_date DESC
 	`
 
-	rows, err := database.DB.Query(query, userID)
+	return queryLeaves(query, userID)
+}
+
+// UpcomingLeave is an active leave that has not ended yet
+type UpcomingLeave struct {
+	database.Leave
+	InProgress bool `json:"in_progress"` // Started on or before today; false for leaves that start later
+}
+
+// GetUpcomingLeavesForUser retrieves a user's active leaves that have not ended, soonest first:
+// the one in progress today (if any), then future ones. A limit of 0 returns them all.
+func GetUpcomingLeavesForUser(userID, limit int) ([]UpcomingLeave, error) {
+	defer database.TimeQuery("GetUpcomingLeavesForUser", time.Now())
+
+	if _, err := GetUserByID(userID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+
+	query := `
+		SELECT ` + leaveColumns + `
+		FROM leaves
+		WHERE user_id = ?
+		AND status = 'active'
+		AND date(end_date) >= ?
+		ORDER BY start_date, id
+		LIMIT ?
+	`
+
+	today := Today()
+	leaves, err := queryLeaves(query, userID, today, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query user leaves: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	var leaves []database.Leave
-	for rows.Next() {
-		var leave database.Leave
-		err := rows.Scan(
-			&leave.ID,
-			&leave.UserID,
-			&leave.LeaveType,
-			&leave.StartDate,
-			&leave.EndDate,
-			&leave.Reason,
-			&leave.Status,
-			&leave.CreatedAt,
-			&leave.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan leave: %w", err)
-		}
-		leaves = append(leaves, leave)
+	upcoming := make([]UpcomingLeave, 0, len(leaves))
+	for _, leave := range leaves {
+		upcoming = append(upcoming, UpcomingLeave{
+			Leave:      leave,
+			InProgress: leave.StartDate.Format("2006-01-02") <= today,
+		})
 	}
 
-	return leaves, nil
+	return upcoming, nil
 }
 
-// UpdateLeave updates a leave record
-func UpdateLeave(id int, leaveType string, startDate, endDate time.Time, reason string) error {
+// UpdateLeave updates a leave record. An empty dayPortion keeps the current one.
+func UpdateLeave(id int, leaveType string, startDate, endDate time.Time, reason, dayPortion string) error {
+	if dayPortion != "" && !ValidDayPortion(dayPortion) {
+		return ErrInvalidDayPortion
+	}
+
 	query := `
 		UPDATE leaves
 		SET leave_type = ?, start_date = ?, end_date = ?, reason = ?,
+		    day_portion = COALESCE(NULLIF(?, ''), day_portion),
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
 
-	result, err := database.DB.Exec(query, leaveType, startDate, endDate, reason, id)
+	result, err := database.DB.Exec(query, leaveType, startDate, endDate, reason, dayPortion, id)
 	if err != nil {
 		return fmt.Errorf("failed to update leave: %w", err)
 	}
@@ -196,44 +411,294 @@ func UpdateLeave(id int, leaveType string, startDate, endDate time.Time, reason
 	return nil
 }
 
-// CancelLeave marks a leave as cancelled
+// CancelLeave marks an active or pending leave as cancelled
 func CancelLeave(id int) error {
+	return transitionLeave(id, LeaveStatusCancelled)
+}
+
+// GetStandupLeavesInRange returns the active leaves of a standup's members overlapping [from, to]
+func GetStandupLeavesInRange(standupID int, from, to time.Time) ([]database.LeaveWithUser, error) {
+	if to.Before(from) || to.Sub(from) >= maxLeaveRangeDays*24*time.Hour {
+		return nil, ErrInvalidDateRange
+	}
+
+	if _, err := GetStandupByID(standupID); err != nil {
+		return nil, err
+	}
+
+	leaves, err := database.GetLeavesForStandupInRange(standupID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	if leaves == nil {
+		leaves = []database.LeaveWithUser{}
+	}
+	return leaves, nil
+}
+
+// ApproveLeave makes a pending leave active, recording who approved it and when.
+// Only active leaves make their user ineligible for standups.
+func ApproveLeave(id int, approvedBy string) error {
+	approvedBy = strings.TrimSpace(approvedBy)
+	if approvedBy == "" {
+		return ErrApproverRequired
+	}
+
+	leave, err := GetLeaveByID(id)
+	if err != nil {
+		return ErrLeaveNotFound
+	}
+
+	if leave.Status != LeaveStatusPending {
+		return fmt.Errorf("%w: only pending leaves can be approved, this one is %s", ErrInvalidLeaveTransition, leave.Status)
+	}
+
 	query := `
 		UPDATE leaves
-		SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
+		SET status = 'active', approved_by = ?, approved_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = 'pending'
 	`
 
-	result, err := database.DB.Exec(query, id)
+	result, err := database.DB.Exec(query, approvedBy, id)
 	if err != nil {
-		return fmt.Errorf("failed to cancel leave: %w", err)
+		return fmt.Errorf("failed to approve leave: %w", err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("leave not found")
+		return fmt.Errorf("%w: leave status changed concurrently", ErrInvalidLeaveTransition)
 	}
 
 	return nil
 }
 
-// CompleteLeave marks a leave as completed
+// RejectLeave marks a pending leave as rejected
+func RejectLeave(id int) error {
+	return transitionLeave(id, LeaveStatusRejected)
+}
+
+// ActivateLeave moves a leave to 'active', provided it has not already ended. A leave that was
+// never approved (e.g. one cancelled while still pending) goes back to 'pending' instead, so it
+// cannot skip approval.
+func ActivateLeave(id int) error {
+	leave, err := GetLeaveByID(id)
+	if err != nil {
+		return ErrLeaveNotFound
+	}
+
+	if leave.Status == LeaveStatusActive {
+		return ErrLeaveAlreadyActive
+	}
+
+	if leave.EndDate.Format("2006-01-02") < Today() {
+		return ErrLeaveEnded
+	}
+
+	if leave.ApprovedBy == "" {
+		return transitionLeave(id, LeaveStatusPending)
+	}
+	return transitionLeave(id, LeaveStatusActive)
+}
+
+// CompleteLeave marks an active leave as completed
 func CompleteLeave(id int) error {
+	return transitionLeave(id, LeaveStatusCompleted)
+}
+
+// PurgeableLeaveStatuses are the terminal statuses that may be purged
+var PurgeableLeaveStatuses = []string{LeaveStatusCompleted, LeaveStatusCancelled, LeaveStatusRejected}
+
+// PurgeLeaves permanently deletes leaves in one of the given terminal statuses
+// whose end_date is before the cutoff date. Active leaves are never deleted.
+func PurgeLeaves(before time.Time, statuses []string) (int64, error) {
+	defer database.TimeQuery("PurgeLeaves", time.Now())
+
+	if len(statuses) == 0 {
+		statuses = PurgeableLeaveStatuses
+	}
+
+	placeholders := make([]string, 0, len(statuses))
+	args := []interface{}{before.Format("2006-01-02")}
+	for _, status := range statuses {
+		if !IsPurgeableLeaveStatus(status) {
+			return 0, fmt.Errorf("status %q cannot be purged", status)
+		}
+		placeholders = append(placeholders, "?")
+		args = append(args, status)
+	}
+
 	query := `
-		UPDATE leaves
-		SET status = 'completed', updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
+		DELETE FROM leaves
+		WHERE date(end_date) < ?
+		AND status IN (` + strings.Join(placeholders, ", ") + `)
 	`
 
-	result, err := database.DB.Exec(query, id)
+	result, err := database.DB.Exec(query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to complete leave: %w", err)
+		return 0, fmt.Errorf("failed to purge leaves: %w", err)
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("leave not found")
+	removed, _ := result.RowsAffected()
+	return removed, nil
+}
+
+// IsPurgeableLeaveStatus reports whether leaves in the given status may be purged
+func IsPurgeableLeaveStatus(status string) bool {
+	for _, s := range PurgeableLeaveStatuses {
+		if s == status {
+			return true
+		}
 	}
+	return false
+}
 
-	return nil
+// LeaveExportFilter narrows a leave export. Zero values mean no filter.
+type LeaveExportFilter struct {
+	UserID     int
+	ActiveOnly bool      // Only leaves in effect today
+	From       time.Time // Only leaves ending on or after this day
+	To         time.Time // Only leaves starting on or before this day
+}
+
+// LeaveExportRow is one leave in an export, with the user's display name joined in
+type LeaveExportRow struct {
+	database.Leave
+	DisplayName string
+}
+
+// ExportLeaves calls fn for every leave matching the filter, oldest start date first, reading
+// rows one at a time so large exports are never held in memory. It stops at the first error fn returns.
+func ExportLeaves(filter LeaveExportFilter, fn func(LeaveExportRow) error) error {
+	defer database.TimeQuery("ExportLeaves", time.Now())
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.UserID != 0 {
+		conditions = append(conditions, "l.user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.ActiveOnly {
+		today := Today()
+		conditions = append(conditions, "l.status = 'active' AND date(l.start_date) <= ? AND date(l.end_date) >= ?")
+		args = append(args, today, today)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "date(l.end_date) >= ?")
+		args = append(args, filter.From.Format("2006-01-02"))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "date(l.start_date) <= ?")
+		args = append(args, filter.To.Format("2006-01-02"))
+	}
+
+	query := `
+		SELECT l.id, l.user_id, l.leave_type, l.start_date, l.end_date, l.reason, l.status,
+		       l.day_portion, l.created_at, l.updated_at, u.display_name
+		FROM leaves l
+		INNER JOIN users u ON l.user_id = u.id
+	`
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+	query += "ORDER BY l.start_date, l.id"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query leaves: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row LeaveExportRow
+		err := rows.Scan(
+			&row.ID,
+			&row.UserID,
+			&row.LeaveType,
+			&row.StartDate,
+			&row.EndDate,
+			&row.Reason,
+			&row.Status,
+			&row.DayPortion,
+			&row.CreatedAt,
+			&row.UpdatedAt,
+			&row.DisplayName,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan leave: %w", err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// LeaveCalendarEntry is one user away on one day of the leave calendar
+type LeaveCalendarEntry struct {
+	LeaveID     int    `json:"leave_id"`
+	UserID      int    `json:"user_id"`
+	DisplayName string `json:"display_name"`
+	LeaveType   string `json:"leave_type"`
+	DayPortion  string `json:"day_portion"` // 'full', 'morning' or 'afternoon'
+}
+
+// GetLeaveCalendar returns, for every date (YYYY-MM-DD) of the month containing month, who is on
+// an active leave that day. Leaves spanning several days appear on each of their days within the
+// month, including those that start before or end after it. Days nobody is away have an empty list.
+func GetLeaveCalendar(month time.Time) (map[string][]LeaveCalendarEntry, error) {
+	defer database.TimeQuery("GetLeaveCalendar", time.Now())
+
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+
+	calendar := make(map[string][]LeaveCalendarEntry)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		calendar[day.Format("2006-01-02")] = []LeaveCalendarEntry{}
+	}
+
+	query := `
+		SELECT l.id, l.user_id, u.display_name, l.leave_type, l.day_portion, l.start_date, l.end_date
+		FROM leaves l
+		INNER JOIN users u ON l.user_id = u.id
+		WHERE l.status = 'active'
+		AND date(l.start_date) <= ?
+		AND date(l.end_date) >= ?
+		ORDER BY u.display_name, l.start_date
+	`
+
+	rows, err := database.DB.Query(query, last.Format("2006-01-02"), first.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leaves: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry      LeaveCalendarEntry
+			start, end time.Time
+		)
+		if err := rows.Scan(&entry.LeaveID, &entry.UserID, &entry.DisplayName, &entry.LeaveType, &entry.DayPortion, &start, &end); err != nil {
+			return nil, fmt.Errorf("failed to scan leave: %w", err)
+		}
+
+		// Expand the leave into its days, clipped to the month
+		from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		if from.Before(first) {
+			from = first
+		}
+		to := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+		if to.After(last) {
+			to = last
+		}
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			date := day.Format("2006-01-02")
+			calendar[date] = append(calendar[date], entry)
+		}
+	}
+
+	return calendar, rows.Err()
 }
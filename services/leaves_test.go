@@ -0,0 +1,217 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+)
+
+func TestLeaveTodayFollowsTimezone(t *testing.T) {
+	// 20:00 UTC on Sunday 9 March is already Monday the 10th in Tokyo, still the 9th in New York
+	clock := time.Date(2025, 3, 9, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		timezone       string
+		wantToday      string
+		wantActive     []string // reasons of the leaves in effect today
+		wantExpired    []string // reasons of the leaves ExpireLeaves completes
+		wantInProgress bool     // the leave starting on the 10th, per GetUpcomingLeavesForUser
+	}{
+		{"UTC", "2025-03-09", []string{"ends 9th"}, []string{"ended 8th"}, false},
+		{"America/New_York", "2025-03-09", []string{"ends 9th"}, []string{"ended 8th"}, false},
+		{"Asia/Tokyo", "2025-03-10", []string{"starts 10th"}, []string{"ended 8th", "ends 9th"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			setupTestDB(t)
+			config.Config.Timezone = tt.timezone
+			setNow(clock)
+
+			alice := mustCreateUser(t, "alice")
+			bob := mustCreateUser(t, "bob")
+			carol := mustCreateUser(t, "carol")
+			for _, leave := range []struct {
+				user       int
+				start, end string
+				reason     string
+			}{
+				{alice.ID, "2025-03-05", "2025-03-08", "ended 8th"},
+				{bob.ID, "2025-03-07", "2025-03-09", "ends 9th"},
+				{carol.ID, "2025-03-10", "2025-03-12", "starts 10th"},
+			} {
+				start, _ := time.Parse("2006-01-02", leave.start)
+				end, _ := time.Parse("2006-01-02", leave.end)
+				created, err := CreateLeave(leave.user, "vacation", start, end, leave.reason, "")
+				if err != nil {
+					t.Fatalf("CreateLeave: %v", err)
+				}
+				if err := ApproveLeave(created.ID, "test"); err != nil {
+					t.Fatalf("ApproveLeave: %v", err)
+				}
+			}
+
+			if got := Today(); got != tt.wantToday {
+				t.Errorf("Today() = %s, want %s", got, tt.wantToday)
+			}
+
+			active, err := GetActiveLeaves()
+			if err != nil {
+				t.Fatalf("GetActiveLeaves: %v", err)
+			}
+			var reasons []string
+			for _, leave := range active {
+				reasons = append(reasons, leave.Reason)
+			}
+			if !reflect.DeepEqual(reasons, tt.wantActive) {
+				t.Errorf("GetActiveLeaves = %v, want %v", reasons, tt.wantActive)
+			}
+
+			page, total, err := GetActiveLeavesPaginated(10, 0)
+			if err != nil {
+				t.Fatalf("GetActiveLeavesPaginated: %v", err)
+			}
+			if total != len(tt.wantActive) || len(page) != len(tt.wantActive) {
+				t.Errorf("GetActiveLeavesPaginated = %d leaves of %d, want %d", len(page), total, len(tt.wantActive))
+			}
+
+			upcoming, err := GetUpcomingLeavesForUser(carol.ID, 0)
+			if err != nil {
+				t.Fatalf("GetUpcomingLeavesForUser: %v", err)
+			}
+			if len(upcoming) != 1 || upcoming[0].InProgress != tt.wantInProgress {
+				t.Errorf("carol's upcoming leaves = %+v, want one with in_progress %v", upcoming, tt.wantInProgress)
+			}
+
+			ExpireLeaves()
+			completed, err := GetLeavesByStatus(LeaveStatusCompleted)
+			if err != nil {
+				t.Fatalf("GetLeavesByStatus: %v", err)
+			}
+			reasons = nil
+			for _, leave := range completed {
+				reasons = append(reasons, leave.Reason)
+			}
+			sort.Strings(reasons)
+			if !reflect.DeepEqual(reasons, tt.wantExpired) {
+				t.Errorf("expired leaves = %v, want %v", reasons, tt.wantExpired)
+			}
+		})
+	}
+}
+
+func TestLeaveStatusTransitions(t *testing.T) {
+	approve := func(id int) error { return ApproveLeave(id, "manager") }
+
+	tests := []struct {
+		name       string
+		from       string
+		approved   bool // approved_by is set
+		ended      bool // end_date is before today
+		action     func(id int) error
+		wantErr    error
+		wantStatus string
+	}{
+		{"approve pending", LeaveStatusPending, false, false, approve, nil, LeaveStatusActive},
+		{"reject pending", LeaveStatusPending, false, false, RejectLeave, nil, LeaveStatusRejected},
+		{"cancel pending", LeaveStatusPending, false, false, CancelLeave, nil, LeaveStatusCancelled},
+		{"complete pending", LeaveStatusPending, false, false, CompleteLeave, ErrInvalidLeaveTransition, LeaveStatusPending},
+		{"cancel active", LeaveStatusActive, true, false, CancelLeave, nil, LeaveStatusCancelled},
+		{"complete active", LeaveStatusActive, true, false, CompleteLeave, nil, LeaveStatusCompleted},
+		{"approve active", LeaveStatusActive, true, false, approve, ErrInvalidLeaveTransition, LeaveStatusActive},
+		{"reject active", LeaveStatusActive, true, false, RejectLeave, ErrInvalidLeaveTransition, LeaveStatusActive},
+		{"activate active", LeaveStatusActive, true, false, ActivateLeave, ErrLeaveAlreadyActive, LeaveStatusActive},
+		{"cancel completed", LeaveStatusCompleted, true, false, CancelLeave, ErrInvalidLeaveTransition, LeaveStatusCompleted},
+		{"reactivate completed", LeaveStatusCompleted, true, false, ActivateLeave, nil, LeaveStatusActive},
+		{"reactivate ended", LeaveStatusCompleted, true, true, ActivateLeave, ErrLeaveEnded, LeaveStatusCompleted},
+		{"reactivate cancelled", LeaveStatusCancelled, true, false, ActivateLeave, nil, LeaveStatusActive},
+		{"reactivate cancelled while pending", LeaveStatusCancelled, false, false, ActivateLeave, nil, LeaveStatusPending},
+		{"complete cancelled", LeaveStatusCancelled, true, false, CompleteLeave, ErrInvalidLeaveTransition, LeaveStatusCancelled},
+		{"cancel rejected", LeaveStatusRejected, false, false, CancelLeave, ErrInvalidLeaveTransition, LeaveStatusRejected},
+		{"reactivate rejected", LeaveStatusRejected, false, false, ActivateLeave, ErrInvalidLeaveTransition, LeaveStatusRejected},
+		{"unknown leave", "", false, false, CancelLeave, ErrLeaveNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			setNow(time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC))
+			alice := mustCreateUser(t, "alice")
+
+			id := 999
+			if tt.from != "" {
+				start, end := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+				if tt.ended {
+					start, end = start.AddDate(0, 0, -5), end.AddDate(0, 0, -5)
+				}
+				leave, err := CreateLeave(alice.ID, "vacation", start, end, "", "")
+				if err != nil {
+					t.Fatalf("CreateLeave: %v", err)
+				}
+				approvedBy := ""
+				if tt.approved {
+					approvedBy = "manager"
+				}
+				if _, err := database.DB.Exec("UPDATE leaves SET status = ?, approved_by = ? WHERE id = ?", tt.from, approvedBy, leave.ID); err != nil {
+					t.Fatalf("set status: %v", err)
+				}
+				id = leave.ID
+			}
+
+			err := tt.action(id)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.from == "" {
+				return
+			}
+
+			leave, err := GetLeaveByID(id)
+			if err != nil {
+				t.Fatalf("GetLeaveByID: %v", err)
+			}
+			if leave.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", leave.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestPurgeExpiredLeavesFollowsTimezone(t *testing.T) {
+	// 20:00 UTC on Sunday 9 March is already the 10th in Tokyo, so a day's retention reaches the 8th
+	clock := time.Date(2025, 3, 9, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		timezone   string
+		wantPurged bool
+	}{
+		{"UTC", false},
+		{"Asia/Tokyo", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			setupTestDB(t)
+			config.Config.Timezone = tt.timezone
+			config.Config.LeaveRetentionDays = 1
+			setNow(clock)
+
+			leave := mustCreateApprovedLeave(t, mustCreateUser(t, "alice"), "vacation", "2025-03-05", "2025-03-08")
+			if err := CancelLeave(leave.ID); err != nil {
+				t.Fatalf("CancelLeave: %v", err)
+			}
+
+			PurgeExpiredLeaves()
+
+			_, err := GetLeaveByID(leave.ID)
+			if purged := err != nil; purged != tt.wantPurged {
+				t.Errorf("purged = %v (err %v), want %v", purged, err, tt.wantPurged)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// messageLocale holds the names and layout used to render dates in reminder messages
+type messageLocale struct {
+	weekdays [7]string  // Sunday first, matching time.Weekday
+	months   [12]string // January first
+	// layout uses the placeholders {weekday}, {day}, {month} and {year}
+	layout string
+}
+
+// messageLocales are the supported MESSAGE_LOCALE values
+var messageLocales = map[string]messageLocale{
+	"en": {
+		weekdays: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months:   [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		layout:   "{weekday}, {day} {month} {year}",
+	},
+	"de": {
+		weekdays: [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		months:   [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		layout:   "{weekday}, {day}. {month} {year}",
+	},
+	"fr": {
+		weekdays: [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		months:   [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		layout:   "{weekday} {day} {month} {year}",
+	},
+	"es": {
+		weekdays: [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		months:   [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		layout:   "{weekday}, {day} de {month} de {year}",
+	},
+	"pt": {
+		weekdays: [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		months:   [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		layout:   "{weekday}, {day} de {month} de {year}",
+	},
+	"ja": {
+		weekdays: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		months:   [12]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+		layout:   "{year}年{month}月{day}日({weekday})",
+	},
+	"zh": {
+		weekdays: [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+		months:   [12]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+		layout:   "{year}年{month}月{day}日 {weekday}",
+	},
+}
+
+// IsSupportedLocale reports whether a MESSAGE_LOCALE value has translations
+func IsSupportedLocale(code string) bool {
+	_, ok := messageLocales[code]
+	return ok
+}
+
+// formatLocalDate renders a date with the given locale's weekday and month names,
+// falling back to English for unknown locales
+func formatLocalDate(t time.Time, code string) string {
+	locale, ok := messageLocales[code]
+	if !ok {
+		locale = messageLocales["en"]
+	}
+
+	return strings.NewReplacer(
+		"{weekday}", locale.weekdays[t.Weekday()],
+		"{day}", strconv.Itoa(t.Day()),
+		"{month}", locale.months[t.Month()-1],
+		"{year}", strconv.Itoa(t.Year()),
+	).Replace(locale.layout)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google-chat-bot/database"
+)
+
+// MemberImportRow is one member in a roster import, identified by google_chat_user_id.
+// Omitted fields keep the member's current value (or the default for new members).
+type MemberImportRow struct {
+	GoogleChatUserID string  `json:"google_chat_user_id"`
+	Alias            *string `json:"alias"`
+	CanFacilitate    *bool   `json:"can_facilitate"`
+	DisplayOrder     *int    `json:"display_order"` // Position in the rotation, 0-based
+}
+
+// MemberImportRowError describes why one row of a roster import was rejected
+type MemberImportRowError struct {
+	Row              int    `json:"row"` // 0-based index into the request
+	GoogleChatUserID string `json:"google_chat_user_id"`
+	Error            string `json:"error"`
+}
+
+// MemberImportError is returned by ImportStandupMembers when any row is invalid
+type MemberImportError struct {
+	Rows []MemberImportRowError
+}
+
+func (e *MemberImportError) Error() string {
+	return fmt.Sprintf("%d invalid member row(s)", len(e.Rows))
+}
+
+// ImportStandupMembers adds or updates standup members with their alias, can_facilitate flag
+// and position in one transaction. Members not in rows stay on the roster; rows with a
+// display_order are moved to that position and the order is renumbered from 0.
+// Every row is validated first and nothing is applied if any row is invalid.
+func ImportStandupMembers(standupID int, rows []MemberImportRow) error {
+	if _, err := GetStandupByID(standupID); err != nil {
+		return err
+	}
+
+	userIDs := make([]int, len(rows))
+	var rowErrors []MemberImportRowError
+	seen := make(map[string]int)
+	for i, row := range rows {
+		chatID := strings.TrimSpace(row.GoogleChatUserID)
+		reject := func(msg string) {
+			rowErrors = append(rowErrors, MemberImportRowError{Row: i, GoogleChatUserID: chatID, Error: msg})
+		}
+
+		if chatID == "" {
+			reject("google_chat_user_id is required")
+			continue
+		}
+		if first, ok := seen[chatID]; ok {
+			reject(fmt.Sprintf("duplicate of row %d", first))
+			continue
+		}
+		seen[chatID] = i
+
+		if row.DisplayOrder != nil && *row.DisplayOrder < 0 {
+			reject("display_order must not be negative")
+			continue
+		}
+
+		err := database.DB.QueryRow("SELECT id FROM users WHERE google_chat_user_id = ?", chatID).Scan(&userIDs[i])
+		if err == sql.ErrNoRows {
+			reject("unknown user")
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to look up user %s: %w", chatID, err)
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return &MemberImportError{Rows: rowErrors}
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Current roster in rotation order
+	existing, err := tx.Query(
+		"SELECT user_id FROM standup_members WHERE standup_id = ? ORDER BY display_order, user_id",
+		standupID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get standup members: %w", err)
+	}
+	var current []int
+	for existing.Next() {
+		var userID int
+		if err := existing.Scan(&userID); err != nil {
+			existing.Close()
+			return fmt.Errorf("failed to scan standup member: %w", err)
+		}
+		current = append(current, userID)
+	}
+	existing.Close()
+	if err := existing.Err(); err != nil {
+		return fmt.Errorf("failed to get standup members: %w", err)
+	}
+
+	// Work out the new order: rows without display_order keep their place (new members go
+	// last), then rows with one are inserted at that position, lowest first
+	placed := make(map[int]bool)
+	var positioned []int
+	for i, row := range rows {
+		if row.DisplayOrder != nil {
+			placed[userIDs[i]] = true
+			positioned = append(positioned, i)
+		}
+	}
+
+	order := []int{}
+	onRoster := make(map[int]bool)
+	for _, userID := range current {
+		onRoster[userID] = true
+		if !placed[userID] {
+			order = append(order, userID)
+		}
+	}
+	for _, userID := range userIDs {
+		if !onRoster[userID] && !placed[userID] {
+			order = append(order, userID)
+		}
+		onRoster[userID] = true
+	}
+
+	sort.SliceStable(positioned, func(a, b int) bool {
+		return *rows[positioned[a]].DisplayOrder < *rows[positioned[b]].DisplayOrder
+	})
+	for _, i := range positioned {
+		pos := *rows[i].DisplayOrder
+		if pos > len(order) {
+			pos = len(order)
+		}
+		order = append(order[:pos], append([]int{userIDs[i]}, order[pos:]...)...)
+	}
+
+	for pos, userID := range order {
+		_, err := tx.Exec(`
+			INSERT INTO standup_members (standup_id, user_id, display_order) VALUES (?, ?, ?)
+			ON CONFLICT(standup_id, user_id) DO UPDATE SET display_order = excluded.display_order
+		`, standupID, userID, pos)
+		if err != nil {
+			return fmt.Errorf("failed to set member %d: %w", userID, err)
+		}
+	}
+
+	for i, row := range rows {
+		if row.Alias != nil {
+			_, err := tx.Exec("UPDATE standup_members SET alias = ? WHERE standup_id = ? AND user_id = ?",
+				strings.TrimSpace(*row.Alias), standupID, userIDs[i])
+			if err != nil {
+				return fmt.Errorf("failed to set member alias: %w", err)
+			}
+		}
+
+		if row.CanFacilitate != nil {
+			_, err := tx.Exec("UPDATE standup_members SET can_facilitate = ? WHERE standup_id = ? AND user_id = ?",
+				*row.CanFacilitate, standupID, userIDs[i])
+			if err != nil {
+				return fmt.Errorf("failed to set member can_facilitate: %w", err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+	"google-chat-bot/integrations"
+)
+
+// failureAlertWebhookURL returns where a standup's send failures are reported: ADMIN_WEBHOOK_URL
+// when configured, otherwise the global webhook for standups with an owner. Empty means no alert.
+func failureAlertWebhookURL(standup *database.Standup) string {
+	if config.Config.AdminWebhookURL != "" {
+		return config.Config.AdminWebhookURL
+	}
+	if standup.OwnerUserID != nil {
+		return config.Config.WebhookURL
+	}
+	return ""
+}
+
+// notifySendFailure reports a failed reminder send, @-mentioning the standup's owner if it has
+// one. Alerts are opt-in: they need ADMIN_WEBHOOK_URL or a standup owner, and are skipped when
+// the alert would go to one of the standup's own webhooks.
+func notifySendFailure(standup *database.Standup, sendErr error) {
+	alertURL := failureAlertWebhookURL(standup)
+	if alertURL == "" {
+		return
+	}
+
+	if containsString(standupWebhookURLs(standup), alertURL) {
+		slog.Warn("Not alerting: the alert destination is one of the standup's own webhooks", "standup_id", standup.ID)
+		return
+	}
+
+	message := fmt.Sprintf("⚠️ Standup '%s' failed to send: %v", standup.Name, sendErr)
+
+	if standup.OwnerUserID != nil {
+		owner, err := GetUserByID(*standup.OwnerUserID)
+		if err != nil {
+			slog.Warn("Failed to get standup owner", "standup_id", standup.ID, "error", err)
+		} else {
+			message += fmt.Sprintf("\n👤 Owner: %s", mention(owner, displayName(owner)))
+		}
+	}
+
+	if err := integrations.SendSimpleMessage(context.Background(), alertURL, message); err != nil {
+		slog.Error("Failed to send failure alert", "standup_id", standup.ID, "error", err)
+		return
+	}
+
+	slog.Info("📣 [FAILURE ALERTED] Reported failed send", "standup_id", standup.ID, "destination", integrations.RedactWebhookURL(alertURL))
+}
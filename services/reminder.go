@@ -0,0 +1,428 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+)
+
+// SkipReason explains why a reminder was (or would be) not sent
+type SkipReason string
+
+const (
+	SkipReasonWeekend         SkipReason = "weekend"
+	SkipReasonHoliday         SkipReason = "holiday"
+	SkipReasonInactive        SkipReason = "inactive"
+	SkipReasonNoEligibleUsers SkipReason = "no_eligible_users"
+	SkipReasonNoWebhook       SkipReason = "no_webhook"
+	SkipReasonSnoozed         SkipReason = "snoozed"
+	SkipReasonSingleEligible  SkipReason = "single_eligible"
+	SkipReasonNotScheduled    SkipReason = "not_scheduled"
+	SkipReasonAlreadyRan      SkipReason = "already_ran"
+	SkipReasonTemplateError   SkipReason = "template_error"
+)
+
+// ErrSendFailed is returned when the reminder webhook post fails
+var ErrSendFailed = errors.New("failed to send reminder")
+
+// SkipError is returned by a send that was skipped instead of attempted
+type SkipError struct {
+	Reason SkipReason
+	Detail string
+}
+
+func (e *SkipError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("reminder skipped (%s): %s", e.Reason, e.Detail)
+	}
+	return fmt.Sprintf("reminder skipped (%s)", e.Reason)
+}
+
+// StandupReminder holds everything that goes into a single reminder message
+type StandupReminder struct {
+	Standup            *database.Standup
+	Day                time.Time // The day the reminder is for, in the standup's timezone
+	EligibleUsers      []database.User
+	CurrentFacilitator *database.User
+	RotationSlot       *database.User // Whose turn it is; differs from CurrentFacilitator when a substitute stands in
+	Nominated          bool           // CurrentFacilitator was nominated for this turn; RotationSlot is nil and the rotation stays put
+	NextFacilitator    *database.User
+	ActiveLeaves       []database.LeaveWithUser
+	ReturningLeaves    []database.LeaveWithUser // Leaves ending today, only when show_returning is on
+	Aliases            map[int]string           // Per-standup member aliases, by user ID
+	Message            string
+	TemplateErr        error // Why the standup's template failed; Message then uses the built-in format
+}
+
+// ReminderDiagnostic describes a condition that would stop a reminder from being posted
+type ReminderDiagnostic struct {
+	Reason SkipReason `json:"reason"`
+	Detail string     `json:"detail"`
+}
+
+// ReminderPreview is the dry-run result of building a reminder without sending it
+type ReminderPreview struct {
+	StandupID          int                  `json:"standup_id"`
+	Message            string               `json:"message"`
+	CurrentFacilitator *database.User       `json:"current_facilitator,omitempty"`
+	NextFacilitator    *database.User       `json:"next_facilitator,omitempty"`
+	EligibleUsers      int                  `json:"eligible_users"`
+	OnLeave            int                  `json:"on_leave"`
+	WouldSend          bool                 `json:"would_send"`
+	Diagnostics        []ReminderDiagnostic `json:"diagnostics"`
+}
+
+// scheduleSkipReason reports whether a standup's scheduled send would be skipped at the given time
+func scheduleSkipReason(standup *database.Standup, now time.Time) (SkipReason, string) {
+	if !standup.IsActive {
+		return SkipReasonInactive, "standup is not active"
+	}
+
+	local := now.In(standupLocation(standup))
+	if config.Config.SkipWeekends {
+		today := local.Weekday()
+		if today == time.Saturday || today == time.Sunday {
+			return SkipReasonWeekend, fmt.Sprintf("would skip: weekend (%s)", today.String())
+		}
+	}
+
+	// Holidays are dates in the standup's own timezone, like the weekend check
+	holiday, err := HolidayOn(local.Format("2006-01-02"))
+	if err != nil {
+		slog.Warn("Could not check holidays", "standup_id", standup.ID, "error", err)
+	} else if holiday != nil {
+		return SkipReasonHoliday, fmt.Sprintf("would skip: holiday (%s)", holidayName(holiday))
+	}
+
+	return "", ""
+}
+
+// scheduledRunSkipReason reports why the scheduled reminder does not run on now's day in the
+// standup's timezone: a reason any send would be skipped, or the day missing from days_of_week
+func scheduledRunSkipReason(standup *database.Standup, now time.Time) (SkipReason, string) {
+	if reason, detail := scheduleSkipReason(standup, now); reason != "" {
+		return reason, detail
+	}
+
+	weekday := now.In(standupLocation(standup)).Weekday()
+	if !runsOnWeekday(standup, weekday) {
+		return SkipReasonNotScheduled, fmt.Sprintf("would skip: not scheduled on %s", weekday.String())
+	}
+
+	return "", ""
+}
+
+// runsOnWeekday reports whether days_of_week includes the weekday (an empty list means every day)
+func runsOnWeekday(standup *database.Standup, weekday time.Weekday) bool {
+	if standup.DaysOfWeek == "" {
+		return true
+	}
+	day := strings.ToUpper(weekday.String()[:3])
+	return strings.Contains(","+standup.DaysOfWeek+",", ","+day+",")
+}
+
+// holidayName returns a holiday's name for messages, or its date when it has none
+func holidayName(holiday *database.Holiday) string {
+	if holiday.Name != "" {
+		return holiday.Name
+	}
+	return holiday.Date
+}
+
+// BuildStandupMessage gathers facilitators and leaves for a standup and renders the reminder text
+// without sending it. A standup with no eligible users still renders, just without facilitators.
+func BuildStandupMessage(standupID int, opts ReminderOptions) (*StandupReminder, error) {
+	standup, err := GetStandupByID(standupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Leaves and eligibility are for the standup's own day, which may differ from the server's
+	today := standupToday(standup)
+
+	// Get eligible users (active and not on leave)
+	users, err := database.GetEligibleUsersForStandup(standupID, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eligible users: %w", err)
+	}
+
+	reminder := &StandupReminder{
+		Standup:       standup,
+		Day:           now().In(standupLocation(standup)),
+		EligibleUsers: users,
+	}
+
+	// Members may go by a standup-specific alias (best effort, display_name otherwise)
+	reminder.Aliases, _ = GetStandupMemberAliases(standupID)
+
+	if len(users) > 0 {
+		// Calculate current facilitator from eligible users, unless one was forced for this send
+		if opts.FacilitatorID != 0 {
+			reminder.CurrentFacilitator = findUser(users, opts.FacilitatorID)
+			if reminder.CurrentFacilitator == nil {
+				return nil, ErrUserNotEligible
+			}
+			reminder.RotationSlot = reminder.CurrentFacilitator
+		} else if nominee := nominatedFacilitator(standup, users); nominee != nil {
+			// The nominee runs this turn outside the rotation, which stays where it is
+			reminder.CurrentFacilitator = nominee
+			reminder.Nominated = true
+		} else {
+			reminder.RotationSlot, reminder.CurrentFacilitator, err = GetCurrentFacilitatorSlot(standupID, users)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get current facilitator: %w", err)
+			}
+		}
+
+		// Calculate tomorrow's facilitator (best effort); after a nominee, it is whoever the
+		// rotation has up now
+		if reminder.Nominated {
+			tomorrow := reminder.Day.AddDate(0, 0, 1).Format("2006-01-02")
+			_, reminder.NextFacilitator, _ = facilitatorSlotOn(standupID, users, tomorrow)
+		} else {
+			reminder.NextFacilitator, _ = GetNextFacilitator(standupID, users, reminder.RotationSlot.ID)
+		}
+	}
+
+	// Get active leaves for today (best effort)
+	reminder.ActiveLeaves, _ = database.GetActiveLeavesForStandup(standupID, today)
+
+	// end_date is the last day away, so leaves ending today are back tomorrow (best effort)
+	if standup.ShowReturning {
+		reminder.ReturningLeaves, _ = database.GetLeavesEndingSoon(standupID, today)
+	}
+
+	reminder.Message, reminder.TemplateErr = renderStandupMessage(reminder)
+	if reminder.TemplateErr != nil {
+		slog.Warn("Standup template failed, using the built-in format", "standup_id", standupID, "error", reminder.TemplateErr)
+	}
+	return reminder, nil
+}
+
+// renderStandupMessage builds the reminder text from the gathered reminder data, using the
+// standup's template when it has one. A template that fails falls back to the built-in format
+// and its error is returned alongside.
+func renderStandupMessage(reminder *StandupReminder) (string, error) {
+	if reminder.Standup.FacilitatorOnly && reminder.CurrentFacilitator != nil {
+		return renderFacilitatorCallout(reminder), nil
+	}
+
+	if reminder.Standup.Template != "" {
+		message, err := renderReminderTemplate(reminder)
+		if err != nil {
+			return renderDefaultMessage(reminder), err
+		}
+		return message, nil
+	}
+
+	return renderDefaultMessage(reminder), nil
+}
+
+// renderDefaultMessage builds the built-in reminder format
+func renderDefaultMessage(reminder *StandupReminder) string {
+	message := fmt.Sprintf("🌅 *%s*\n", reminder.Standup.Name)
+
+	// Add today's date in the configured locale (opt-in via MESSAGE_LOCALE)
+	if config.Config.MessageLocale != "" {
+		message += fmt.Sprintf("📆 %s\n", formatLocalDate(reminder.Day, config.Config.MessageLocale))
+	}
+	message += "\n"
+
+	// Add current facilitator if available
+	if reminder.CurrentFacilitator != nil {
+		message += fmt.Sprintf("👤 *Today's Facilitator:* %s\n", reminder.facilitatorName(reminder.CurrentFacilitator))
+	}
+
+	// Add tomorrow's facilitator if available
+	if reminder.NextFacilitator != nil {
+		message += fmt.Sprintf("📅 *Tomorrow's Facilitator:* %s\n", reminder.memberName(reminder.NextFacilitator))
+	}
+
+	message += fmt.Sprintf("\n%s\n", reminder.Standup.Message)
+
+	// Add leave information if there are active leaves
+	if len(reminder.ActiveLeaves) > 0 {
+		message += "\n🏖️ *On Leave Today:*\n"
+		for _, leave := range reminder.ActiveLeaves {
+			message += fmt.Sprintf("• %s (%s)\n", reminder.memberName(&leave.User), leaveLabel(leave.Leave))
+		}
+	} else if reminder.Standup.FullTeamMessage != "" {
+		// Positive confirmation when nobody is away (opt-in per standup)
+		message += fmt.Sprintf("\n%s\n", reminder.Standup.FullTeamMessage)
+	}
+
+	// Add members returning from leave tomorrow (opt-in per standup)
+	if len(reminder.ReturningLeaves) > 0 {
+		message += "\n🔙 *Returning Tomorrow:*\n"
+		for _, leave := range reminder.ReturningLeaves {
+			message += fmt.Sprintf("• %s\n", reminder.memberName(&leave.User))
+		}
+	}
+
+	message += "\n_Have a great day!_ ☀️"
+	return message
+}
+
+// renderFacilitatorCallout builds the minimal facilitator_only reminder: just the standup name
+// and an @-mention of today's facilitator
+func renderFacilitatorCallout(reminder *StandupReminder) string {
+	return fmt.Sprintf("🌅 *%s*\n\n👤 %s, you're facilitating today.",
+		reminder.Standup.Name, mention(reminder.CurrentFacilitator, reminder.memberName(reminder.CurrentFacilitator)))
+}
+
+// leaveLabel describes a leave in reminders: its type, plus the half of the day for half-day leaves
+func leaveLabel(leave database.Leave) string {
+	if leave.DayPortion == database.DayPortionMorning || leave.DayPortion == database.DayPortionAfternoon {
+		return fmt.Sprintf("%s, %s", leave.LeaveType, leave.DayPortion)
+	}
+	return leave.LeaveType
+}
+
+// mention returns a Google Chat @-mention for a user whose google_chat_user_id is a
+// "users/..." resource name, or the given fallback name otherwise
+func mention(user *database.User, fallback string) string {
+	if strings.HasPrefix(user.GoogleChatUserID, "users/") {
+		return fmt.Sprintf("<%s>", user.GoogleChatUserID)
+	}
+	return fallback
+}
+
+// DailyThreadKey returns the thread key a daily_thread standup posts under on day. It only
+// depends on the standup and the date, so re-sends on the same day land in the same thread.
+func DailyThreadKey(standupID int, day time.Time) string {
+	return fmt.Sprintf("standup-%d-%s", standupID, day.Format("20060102"))
+}
+
+// standupThreadKey returns the thread key for today's reminder, or "" to post to the main timeline.
+// A thread_key puts every reminder into one thread; with daily_thread as well, it prefixes each
+// day's key instead.
+func standupThreadKey(standup *database.Standup) string {
+	if !standup.DailyThread {
+		return standup.ThreadKey
+	}
+
+	day := now().In(standupLocation(standup))
+	if standup.ThreadKey != "" {
+		return fmt.Sprintf("%s-%s", standup.ThreadKey, day.Format("20060102"))
+	}
+	return DailyThreadKey(standup.ID, day)
+}
+
+// standupWebhookURL returns the webhook a standup posts to, falling back to the global webhook
+func standupWebhookURL(standup *database.Standup) string {
+	if standup.WebhookURL != "" {
+		return standup.WebhookURL
+	}
+	return config.Config.WebhookURL
+}
+
+// standupWebhookURLs returns every webhook a standup's reminder goes to: its primary webhook
+// (or the global one) followed by any extra webhook_urls
+func standupWebhookURLs(standup *database.Standup) []string {
+	urls := []string{}
+	if primary := standupWebhookURL(standup); primary != "" {
+		urls = append(urls, primary)
+	}
+	for _, webhookURL := range standup.WebhookURLs {
+		if webhookURL != "" && !containsString(urls, webhookURL) {
+			urls = append(urls, webhookURL)
+		}
+	}
+	return urls
+}
+
+// containsString reports whether values includes value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// displayName returns the name used for a user in reminder messages, honouring DISPLAY_FIELD
+// and falling back to display_name when the chosen field is empty
+func displayName(user *database.User) string {
+	if config.Config.DisplayField == "email" && user.Email != "" {
+		return user.Email
+	}
+	return user.DisplayName
+}
+
+// memberName returns the member's alias in this standup, falling back to displayName
+func (reminder *StandupReminder) memberName(user *database.User) string {
+	if alias := reminder.Aliases[user.ID]; alias != "" {
+		return alias
+	}
+	return displayName(user)
+}
+
+// facilitatorName names today's facilitator, as an @-mention when the standup has
+// mention_facilitator set and the user has a "users/..." Google Chat ID
+func (reminder *StandupReminder) facilitatorName(user *database.User) string {
+	name := reminder.memberName(user)
+	if reminder.Standup.MentionFacilitator {
+		return mention(user, name)
+	}
+	return name
+}
+
+// PreviewStandupReminder renders a standup's reminder and runs the pre-flight checks
+// that would cause a real send to be skipped or fail, without sending anything
+func PreviewStandupReminder(standupID int) (*ReminderPreview, error) {
+	reminder, err := BuildStandupMessage(standupID, ReminderOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ReminderPreview{
+		StandupID:          standupID,
+		Message:            reminder.Message,
+		CurrentFacilitator: reminder.CurrentFacilitator,
+		NextFacilitator:    reminder.NextFacilitator,
+		EligibleUsers:      len(reminder.EligibleUsers),
+		OnLeave:            len(reminder.ActiveLeaves),
+		Diagnostics:        []ReminderDiagnostic{},
+	}
+
+	if reason, detail := scheduledRunSkipReason(reminder.Standup, now()); reason != "" {
+		preview.Diagnostics = append(preview.Diagnostics, ReminderDiagnostic{Reason: reason, Detail: detail})
+	}
+
+	if len(reminder.EligibleUsers) == 0 {
+		preview.Diagnostics = append(preview.Diagnostics, ReminderDiagnostic{
+			Reason: SkipReasonNoEligibleUsers,
+			Detail: "0 eligible users (all members inactive or on leave)",
+		})
+	}
+
+	if reminder.Standup.SkipWhenAlone && len(reminder.EligibleUsers) == 1 {
+		preview.Diagnostics = append(preview.Diagnostics, ReminderDiagnostic{
+			Reason: SkipReasonSingleEligible,
+			Detail: "only 1 eligible user and skip_when_alone is enabled",
+		})
+	}
+
+	if standupWebhookURL(reminder.Standup) == "" {
+		preview.Diagnostics = append(preview.Diagnostics, ReminderDiagnostic{
+			Reason: SkipReasonNoWebhook,
+			Detail: "webhook not configured",
+		})
+	}
+
+	if reminder.TemplateErr != nil {
+		preview.Diagnostics = append(preview.Diagnostics, ReminderDiagnostic{
+			Reason: SkipReasonTemplateError,
+			Detail: fmt.Sprintf("template parse error, the built-in format would be posted instead: %v", reminder.TemplateErr),
+		})
+	}
+
+	preview.WouldSend = len(preview.Diagnostics) == 0
+	return preview, nil
+}
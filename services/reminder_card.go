@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+	"google-chat-bot/integrations"
+)
+
+// ChatActionParamStandupID is the action parameter carrying the standup ID in card button clicks
+const ChatActionParamStandupID = "standup_id"
+
+// ChatActionPath is the endpoint that receives card button clicks from Google Chat
+const ChatActionPath = "/api/chat/action"
+
+// sendReminderMessage posts a built reminder to each of the standup's webhooks in its message
+// format. A failing webhook doesn't stop the others: the send only fails when no webhook accepted
+// the reminder, and partial failures are reported through the usual failure alert.
+func sendReminderMessage(ctx context.Context, reminder *StandupReminder) error {
+	standup := reminder.Standup
+	urls := standupWebhookURLs(standup)
+	if len(urls) == 0 {
+		return errors.New("webhook not configured")
+	}
+
+	var errs []error
+	for _, webhookURL := range urls {
+		redacted := integrations.RedactWebhookURL(webhookURL)
+		if err := postReminderMessage(ctx, reminder, webhookURL); err != nil {
+			slog.Error("Failed to post reminder", "standup_id", standup.ID, "webhook", redacted, "error", err)
+			if len(urls) > 1 {
+				err = fmt.Errorf("%s: %w", redacted, err)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		slog.Debug("Posted reminder", "standup_id", standup.ID, "webhook", redacted)
+	}
+
+	if len(errs) == len(urls) {
+		return joinSendErrors(errs)
+	}
+	if len(errs) > 0 {
+		notifySendFailure(standup, joinSendErrors(errs))
+	}
+	return nil
+}
+
+// joinSendErrors combines per-webhook failures into one single-line error
+func joinSendErrors(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// postReminderMessage posts a built reminder to one webhook in the standup's message format
+func postReminderMessage(ctx context.Context, reminder *StandupReminder, webhookURL string) error {
+	standup := reminder.Standup
+	switch standup.MessageFormat {
+	case database.MessageFormatCard, database.MessageFormatStatusCard:
+		return integrations.SendThreadedCardV2Message(ctx, webhookURL, renderReminderCard(reminder), standupThreadKey(standup))
+	}
+	return integrations.SendThreadedMessage(ctx, webhookURL, reminder.Message, standupThreadKey(standup))
+}
+
+// renderReminderCard builds the card for the card formats. Like the text format, a
+// facilitator_only standup only calls out today's facilitator.
+func renderReminderCard(reminder *StandupReminder) integrations.CardV2Message {
+	if reminder.Standup.FacilitatorOnly && reminder.CurrentFacilitator != nil {
+		return renderFacilitatorCard(reminder)
+	}
+	if reminder.Standup.MessageFormat == database.MessageFormatStatusCard {
+		return renderStatusCard(reminder)
+	}
+	return renderStandupCard(reminder)
+}
+
+// renderFacilitatorCard is the card version of renderFacilitatorCallout: just today's
+// facilitator, without the team, leaves or tomorrow's facilitator
+func renderFacilitatorCard(reminder *StandupReminder) integrations.CardV2Message {
+	card := integrations.CardV2{Header: reminderCardHeader(reminder)}
+	card.Sections = append(card.Sections, integrations.CardV2Section{Widgets: []integrations.WidgetV2{
+		{DecoratedText: &integrations.DecoratedText{
+			TopLabel: "👤 Today's Facilitator",
+			Text:     reminder.memberName(reminder.CurrentFacilitator),
+		}},
+	}})
+
+	return reminderCardMessage(reminder, card)
+}
+
+// renderStandupCard builds the cardsV2 reminder from the gathered reminder data. The
+// "Rotate facilitator" button is only added when PUBLIC_URL tells us where Chat can reach the bot.
+func renderStandupCard(reminder *StandupReminder) integrations.CardV2Message {
+	standup := reminder.Standup
+	card := integrations.CardV2{Header: reminderCardHeader(reminder)}
+
+	main := integrations.CardV2Section{Widgets: []integrations.WidgetV2{}}
+	if reminder.CurrentFacilitator != nil {
+		main.Widgets = append(main.Widgets, integrations.WidgetV2{DecoratedText: &integrations.DecoratedText{
+			TopLabel: "👤 Today's Facilitator",
+			Text:     reminder.memberName(reminder.CurrentFacilitator),
+		}})
+	}
+	if reminder.NextFacilitator != nil {
+		main.Widgets = append(main.Widgets, integrations.WidgetV2{DecoratedText: &integrations.DecoratedText{
+			TopLabel: "📅 Tomorrow's Facilitator",
+			Text:     reminder.memberName(reminder.NextFacilitator),
+		}})
+	}
+	main.Widgets = append(main.Widgets, integrations.WidgetV2{TextParagraph: &integrations.TextParagraph{Text: standup.Message}})
+	card.Sections = append(card.Sections, main)
+
+	if len(reminder.ActiveLeaves) > 0 {
+		leaves := integrations.CardV2Section{Header: "🏖️ On Leave Today", Widgets: []integrations.WidgetV2{}}
+		for _, leave := range reminder.ActiveLeaves {
+			leaves.Widgets = append(leaves.Widgets, integrations.WidgetV2{DecoratedText: &integrations.DecoratedText{
+				Text:        reminder.memberName(&leave.User),
+				BottomLabel: leaveLabel(leave.Leave),
+			}})
+		}
+		card.Sections = append(card.Sections, leaves)
+	} else if standup.FullTeamMessage != "" {
+		card.Sections = append(card.Sections, integrations.CardV2Section{Widgets: []integrations.WidgetV2{
+			{TextParagraph: &integrations.TextParagraph{Text: standup.FullTeamMessage}},
+		}})
+	}
+
+	if len(reminder.ReturningLeaves) > 0 {
+		returning := integrations.CardV2Section{Header: "🔙 Returning Tomorrow", Widgets: []integrations.WidgetV2{}}
+		for _, leave := range reminder.ReturningLeaves {
+			returning.Widgets = append(returning.Widgets, integrations.WidgetV2{DecoratedText: &integrations.DecoratedText{
+				Text: reminder.memberName(&leave.User),
+			}})
+		}
+		card.Sections = append(card.Sections, returning)
+	}
+
+	return reminderCardMessage(reminder, card)
+}
+
+// Member statuses shown on the status card
+const (
+	statusFacilitator = "⭐ Facilitator"
+	statusIn          = "✅ In"
+	statusWFH         = "🏠 WFH"
+	statusLeave       = "🏖️ Leave"
+)
+
+// renderStatusCard builds the status_card reminder: a team board with one line per member
+// showing their status today, followed by tomorrow's facilitator and the standup message
+func renderStatusCard(reminder *StandupReminder) integrations.CardV2Message {
+	standup := reminder.Standup
+	card := integrations.CardV2{Header: reminderCardHeader(reminder)}
+
+	card.Sections = append(card.Sections, integrations.CardV2Section{
+		Header:  "Team status",
+		Widgets: statusCardWidgets(reminder),
+	})
+
+	main := integrations.CardV2Section{Widgets: []integrations.WidgetV2{}}
+	if reminder.NextFacilitator != nil {
+		main.Widgets = append(main.Widgets, integrations.WidgetV2{DecoratedText: &integrations.DecoratedText{
+			TopLabel: "📅 Tomorrow's Facilitator",
+			Text:     reminder.memberName(reminder.NextFacilitator),
+		}})
+	}
+	main.Widgets = append(main.Widgets, integrations.WidgetV2{TextParagraph: &integrations.TextParagraph{Text: standup.Message}})
+	card.Sections = append(card.Sections, main)
+
+	return reminderCardMessage(reminder, card)
+}
+
+// statusCardWidgets lists every member taking part today, eligible members in rotation order
+// then members on leave, as a label/status line each. A leave of type "wfh" shows as WFH.
+func statusCardWidgets(reminder *StandupReminder) []integrations.WidgetV2 {
+	widgets := []integrations.WidgetV2{}
+	line := func(user *database.User, status string) {
+		widgets = append(widgets, integrations.WidgetV2{DecoratedText: &integrations.DecoratedText{
+			TopLabel: reminder.memberName(user),
+			Text:     status,
+		}})
+	}
+
+	for i := range reminder.EligibleUsers {
+		user := &reminder.EligibleUsers[i]
+		if reminder.CurrentFacilitator != nil && user.ID == reminder.CurrentFacilitator.ID {
+			line(user, statusFacilitator)
+		} else {
+			line(user, statusIn)
+		}
+	}
+
+	// A member can have overlapping leaves; show them once
+	listed := make(map[int]bool)
+	for i := range reminder.ActiveLeaves {
+		leave := &reminder.ActiveLeaves[i]
+		if listed[leave.User.ID] {
+			continue
+		}
+		listed[leave.User.ID] = true
+
+		if strings.EqualFold(leave.LeaveType, "wfh") {
+			line(&leave.User, statusWFH)
+		} else {
+			line(&leave.User, fmt.Sprintf("%s (%s)", statusLeave, leaveLabel(leave.Leave)))
+		}
+	}
+
+	return widgets
+}
+
+// reminderCardHeader is the header shared by the card formats: the standup name and, when
+// MESSAGE_LOCALE is set, the reminder's date
+func reminderCardHeader(reminder *StandupReminder) *integrations.CardHeader {
+	header := &integrations.CardHeader{Title: "🌅 " + reminder.Standup.Name}
+	if config.Config.MessageLocale != "" {
+		header.Subtitle = formatLocalDate(reminder.Day, config.Config.MessageLocale)
+	}
+	return header
+}
+
+// reminderCardMessage wraps a reminder card into a message, adding the "Rotate facilitator"
+// button when PUBLIC_URL is set and there is a facilitator to rotate
+func reminderCardMessage(reminder *StandupReminder, card integrations.CardV2) integrations.CardV2Message {
+	if config.Config.PublicURL != "" && reminder.CurrentFacilitator != nil {
+		card.Sections = append(card.Sections, integrations.CardV2Section{Widgets: []integrations.WidgetV2{
+			{ButtonList: &integrations.ButtonList{Buttons: []integrations.Button{rotateFacilitatorButton(reminder.Standup.ID)}}},
+		}})
+	}
+
+	return integrations.CardV2Message{
+		CardsV2: []integrations.CardWithID{{CardID: fmt.Sprintf("standup-%d", reminder.Standup.ID), Card: card}},
+	}
+}
+
+// rotateFacilitatorButton calls back into ChatActionPath with the standup ID
+func rotateFacilitatorButton(standupID int) integrations.Button {
+	return integrations.Button{
+		Text: "🔄 Rotate facilitator",
+		OnClick: integrations.OnClick{Action: &integrations.Action{
+			Function: config.Config.PublicURL + ChatActionPath,
+			Parameters: []integrations.ActionParameter{
+				{Key: ChatActionParamStandupID, Value: strconv.Itoa(standupID)},
+			},
+		}},
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+)
+
+func TestStatusCardJSON(t *testing.T) {
+	const rotateButton = `{"widgets": [{"buttonList": {"buttons": [{"text": "🔄 Rotate facilitator", "onClick": {"action": {
+		"function": "https://bot.example.com/api/chat/action", "parameters": [{"key": "standup_id", "value": "1"}]}}}]}}]}`
+
+	tests := []struct {
+		name            string
+		publicURL       string
+		davePortion     string
+		facilitatorOnly bool
+		want            string
+	}{
+		{
+			name:      "team board",
+			publicURL: "https://bot.example.com",
+			want: `{"cardsV2": [{"cardId": "standup-1", "card": {"header": {"title": "🌅 daily"}, "sections": [
+				{"header": "Team status", "widgets": [
+					{"decoratedText": {"topLabel": "alice", "text": "⭐ Facilitator"}},
+					{"decoratedText": {"topLabel": "bob", "text": "✅ In"}},
+					{"decoratedText": {"topLabel": "carol", "text": "🏠 WFH"}},
+					{"decoratedText": {"topLabel": "dave", "text": "🏖️ Leave (sick)"}}
+				]},
+				{"widgets": [
+					{"decoratedText": {"topLabel": "📅 Tomorrow's Facilitator", "text": "bob"}},
+					{"textParagraph": {"text": "Standup time!"}}
+				]},
+				` + rotateButton + `
+			]}}]}`,
+		},
+		{
+			name:        "half-day leave, no PUBLIC_URL",
+			davePortion: database.DayPortionMorning,
+			want: `{"cardsV2": [{"cardId": "standup-1", "card": {"header": {"title": "🌅 daily"}, "sections": [
+				{"header": "Team status", "widgets": [
+					{"decoratedText": {"topLabel": "alice", "text": "⭐ Facilitator"}},
+					{"decoratedText": {"topLabel": "bob", "text": "✅ In"}},
+					{"decoratedText": {"topLabel": "carol", "text": "🏠 WFH"}},
+					{"decoratedText": {"topLabel": "dave", "text": "🏖️ Leave (sick, morning)"}}
+				]},
+				{"widgets": [
+					{"decoratedText": {"topLabel": "📅 Tomorrow's Facilitator", "text": "bob"}},
+					{"textParagraph": {"text": "Standup time!"}}
+				]}
+			]}}]}`,
+		},
+		{
+			name:            "facilitator_only",
+			publicURL:       "https://bot.example.com",
+			facilitatorOnly: true,
+			want: `{"cardsV2": [{"cardId": "standup-1", "card": {"header": {"title": "🌅 daily"}, "sections": [
+				{"widgets": [{"decoratedText": {"topLabel": "👤 Today's Facilitator", "text": "alice"}}]},
+				` + rotateButton + `
+			]}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			setNow(time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC))
+			config.Config.PublicURL = tt.publicURL
+
+			alice := mustCreateUser(t, "alice")
+			bob := mustCreateUser(t, "bob")
+			carol := mustCreateUser(t, "carol")
+			dave := mustCreateUser(t, "dave")
+			standup := mustCreateStandup(t, "daily", alice, bob, carol, dave)
+			if err := SetStandupMessageFormat(standup.ID, database.MessageFormatStatusCard); err != nil {
+				t.Fatalf("SetStandupMessageFormat: %v", err)
+			}
+			if err := SetStandupFacilitatorOnly(standup.ID, tt.facilitatorOnly); err != nil {
+				t.Fatalf("SetStandupFacilitatorOnly: %v", err)
+			}
+
+			mustCreateApprovedLeave(t, carol, "wfh", "2025-03-12", "2025-03-12")
+			sick := mustCreateApprovedLeave(t, dave, "sick", "2025-03-11", "2025-03-13")
+			if tt.davePortion != "" {
+				if _, err := database.DB.Exec("UPDATE leaves SET day_portion = ? WHERE id = ?", tt.davePortion, sick.ID); err != nil {
+					t.Fatalf("set day_portion: %v", err)
+				}
+			}
+
+			reminder, err := BuildStandupMessage(standup.ID, ReminderOptions{})
+			if err != nil {
+				t.Fatalf("BuildStandupMessage: %v", err)
+			}
+			encoded, err := json.Marshal(renderReminderCard(reminder))
+			if err != nil {
+				t.Fatalf("marshal card: %v", err)
+			}
+
+			var got, want interface{}
+			if err := json.Unmarshal(encoded, &got); err != nil {
+				t.Fatalf("decode card: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("decode want: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("card =\n%s\nwant\n%s", encoded, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"google-chat-bot/config"
+)
+
+// ErrInvalidTemplate is returned when a standup template does not parse or uses unknown fields
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// ReminderTemplateData is what a standup's template is executed with. Facilitator names are
+// empty when there is none.
+type ReminderTemplateData struct {
+	StandupName        string
+	Date               string // The reminder's date, in MESSAGE_LOCALE when set, otherwise YYYY-MM-DD
+	Message            string
+	CurrentFacilitator string
+	NextFacilitator    string
+	OnLeave            []TemplateLeave
+	Returning          []string // Names of members back from leave tomorrow (show_returning only)
+	FullTeamMessage    string
+}
+
+// TemplateLeave is one member on leave today, as seen by templates
+type TemplateLeave struct {
+	Name string
+	Type string // Leave type, plus the half of the day for half-day leaves
+}
+
+// parseReminderTemplate parses a standup template
+func parseReminderTemplate(tmpl string) (*template.Template, error) {
+	return template.New("reminder").Parse(tmpl)
+}
+
+// ValidateReminderTemplate checks that tmpl parses and only refers to ReminderTemplateData fields.
+// An empty template is valid and selects the built-in format.
+func ValidateReminderTemplate(tmpl string) error {
+	if tmpl == "" {
+		return nil
+	}
+
+	parsed, err := parseReminderTemplate(tmpl)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	// Executing against sample data catches references to fields that don't exist
+	sample := ReminderTemplateData{
+		OnLeave:   []TemplateLeave{{}},
+		Returning: []string{""},
+	}
+	if err := parsed.Execute(io.Discard, sample); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	return nil
+}
+
+// renderReminderTemplate renders a reminder with the standup's template, returning an
+// ErrInvalidTemplate when the template no longer parses or executes
+func renderReminderTemplate(reminder *StandupReminder) (string, error) {
+	parsed, err := parseReminderTemplate(reminder.Standup.Template)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	var message strings.Builder
+	if err := parsed.Execute(&message, reminderTemplateData(reminder)); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return message.String(), nil
+}
+
+// reminderTemplateData flattens a gathered reminder into the data templates see
+func reminderTemplateData(reminder *StandupReminder) ReminderTemplateData {
+	data := ReminderTemplateData{
+		StandupName:     reminder.Standup.Name,
+		Date:            reminder.Day.Format("2006-01-02"),
+		Message:         reminder.Standup.Message,
+		OnLeave:         []TemplateLeave{},
+		Returning:       []string{},
+		FullTeamMessage: reminder.Standup.FullTeamMessage,
+	}
+
+	if config.Config.MessageLocale != "" {
+		data.Date = formatLocalDate(reminder.Day, config.Config.MessageLocale)
+	}
+	if reminder.CurrentFacilitator != nil {
+		data.CurrentFacilitator = reminder.facilitatorName(reminder.CurrentFacilitator)
+	}
+	if reminder.NextFacilitator != nil {
+		data.NextFacilitator = reminder.memberName(reminder.NextFacilitator)
+	}
+	for _, leave := range reminder.ActiveLeaves {
+		data.OnLeave = append(data.OnLeave, TemplateLeave{Name: reminder.memberName(&leave.User), Type: leaveLabel(leave.Leave)})
+	}
+	for _, leave := range reminder.ReturningLeaves {
+		data.Returning = append(data.Returning, reminder.memberName(&leave.User))
+	}
+
+	return data
+}
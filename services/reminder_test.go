@@ -0,0 +1,382 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+)
+
+func TestFacilitatorName(t *testing.T) {
+	tests := []struct {
+		name         string
+		mention      bool
+		chatID       string
+		alias        string
+		displayField string
+		want         string
+	}{
+		{"mention", true, "users/123", "", "display_name", "<users/123>"},
+		{"mention ignores the alias", true, "users/123", "Ally", "display_name", "<users/123>"},
+		{"mention without a users/ ID falls back to the display name", true, "alice@example.com", "", "display_name", "Alice"},
+		{"mention without a users/ ID falls back to the alias", true, "", "Ally", "display_name", "Ally"},
+		{"mention without a users/ ID falls back to the email", true, "spaces/abc", "", "email", "alice@example.com"},
+		{"no mention", false, "users/123", "", "display_name", "Alice"},
+		{"no mention with an alias", false, "users/123", "Ally", "display_name", "Ally"},
+		{"no mention with DISPLAY_FIELD=email", false, "users/123", "", "email", "alice@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			config.Config.DisplayField = tt.displayField
+
+			user := &database.User{ID: 1, GoogleChatUserID: tt.chatID, DisplayName: "Alice", Email: "alice@example.com"}
+			reminder := &StandupReminder{
+				Standup: &database.Standup{MentionFacilitator: tt.mention},
+				Aliases: map[int]string{},
+			}
+			if tt.alias != "" {
+				reminder.Aliases[user.ID] = tt.alias
+			}
+
+			if got := reminder.facilitatorName(user); got != tt.want {
+				t.Errorf("facilitatorName = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReminderMentionsFacilitator(t *testing.T) {
+	tests := []struct {
+		mention bool
+		want    string
+	}{
+		{true, "*Today's Facilitator:* <users/alice>\n"},
+		{false, "*Today's Facilitator:* alice\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("mention_facilitator=%v", tt.mention), func(t *testing.T) {
+			setupTestDB(t)
+			alice := mustCreateUser(t, "alice")
+			standup := mustCreateStandup(t, "daily", alice, mustCreateUser(t, "bob"))
+			if err := SetStandupMentionFacilitator(standup.ID, tt.mention); err != nil {
+				t.Fatalf("SetStandupMentionFacilitator: %v", err)
+			}
+
+			reminder, err := BuildStandupMessage(standup.ID, ReminderOptions{})
+			if err != nil {
+				t.Fatalf("BuildStandupMessage: %v", err)
+			}
+			if reminder.CurrentFacilitator.ID != alice.ID || !strings.Contains(reminder.Message, tt.want) {
+				t.Errorf("message %q does not contain %q", reminder.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestReminderReturningTomorrow(t *testing.T) {
+	tests := []struct {
+		name          string
+		showReturning bool
+		want          string // the Returning Tomorrow section, "" = none
+	}{
+		{"off", false, ""},
+		{"on", true, "\n🔙 *Returning Tomorrow:*\n• bob\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			setNow(time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC))
+			alice := mustCreateUser(t, "alice")
+			bob := mustCreateUser(t, "bob")
+			carol := mustCreateUser(t, "carol")
+			dave := mustCreateUser(t, "dave")
+			standup := mustCreateStandup(t, "daily", alice, bob, carol, dave)
+			if err := SetStandupShowReturning(standup.ID, tt.showReturning); err != nil {
+				t.Fatalf("SetStandupShowReturning: %v", err)
+			}
+
+			mustCreateApprovedLeave(t, bob, "vacation", "2025-03-10", "2025-03-12")   // back tomorrow
+			mustCreateApprovedLeave(t, carol, "vacation", "2025-03-11", "2025-03-14") // still away tomorrow
+			mustCreateApprovedLeave(t, dave, "sick", "2025-03-10", "2025-03-11")      // already back today
+
+			reminder, err := BuildStandupMessage(standup.ID, ReminderOptions{})
+			if err != nil {
+				t.Fatalf("BuildStandupMessage: %v", err)
+			}
+
+			hasSection := strings.Contains(reminder.Message, "Returning Tomorrow")
+			if tt.want == "" && hasSection {
+				t.Errorf("message lists returning members with show_returning off:\n%s", reminder.Message)
+			}
+			if tt.want != "" && !strings.Contains(reminder.Message, tt.want) {
+				t.Errorf("message does not contain %q:\n%s", tt.want, reminder.Message)
+			}
+		})
+	}
+}
+
+func TestReminderFullTeamMessage(t *testing.T) {
+	const fullTeam = "👍 Full team in today"
+
+	tests := []struct {
+		name        string
+		message     string
+		bobOnLeave  bool
+		wantLine    bool
+		wantOnLeave bool
+	}{
+		{"set, nobody away", fullTeam, false, true, false},
+		{"set, someone away", fullTeam, true, false, true},
+		{"unset, nobody away", "", false, false, false},
+		{"unset, someone away", "", true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			setNow(time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC))
+			alice := mustCreateUser(t, "alice")
+			bob := mustCreateUser(t, "bob")
+			standup := mustCreateStandup(t, "daily", alice, bob)
+			if err := SetStandupFullTeamMessage(standup.ID, tt.message); err != nil {
+				t.Fatalf("SetStandupFullTeamMessage: %v", err)
+			}
+			if tt.bobOnLeave {
+				mustCreateApprovedLeave(t, bob, "vacation", "2025-03-12", "2025-03-12")
+			}
+
+			reminder, err := BuildStandupMessage(standup.ID, ReminderOptions{})
+			if err != nil {
+				t.Fatalf("BuildStandupMessage: %v", err)
+			}
+
+			if got := strings.Contains(reminder.Message, "\n"+fullTeam+"\n"); got != tt.wantLine {
+				t.Errorf("full team line shown = %v, want %v:\n%s", got, tt.wantLine, reminder.Message)
+			}
+			if got := strings.Contains(reminder.Message, "*On Leave Today:*\n• bob (vacation)\n"); got != tt.wantOnLeave {
+				t.Errorf("bob listed on leave = %v, want %v:\n%s", got, tt.wantOnLeave, reminder.Message)
+			}
+		})
+	}
+}
+
+func TestReminderFacilitatorOnly(t *testing.T) {
+	tests := []struct {
+		name            string
+		facilitatorOnly bool
+		template        string
+		wantText        string   // exact text message, "" = not checked
+		wantInText      []string // substrings of the text message
+		wantInCard      []string
+		wantNotInCard   []string
+	}{
+		{
+			name:            "on",
+			facilitatorOnly: true,
+			wantText:        "🌅 *daily*\n\n👤 <users/alice>, you're facilitating today.",
+			wantInCard:      []string{`"topLabel":"👤 Today's Facilitator","text":"alice"`},
+			wantNotInCard:   []string{"Tomorrow's Facilitator", "On Leave Today", "Standup time!"},
+		},
+		{
+			name:            "on overrides the template",
+			facilitatorOnly: true,
+			template:        "{{.StandupName}} with {{.CurrentFacilitator}}",
+			wantText:        "🌅 *daily*\n\n👤 <users/alice>, you're facilitating today.",
+		},
+		{
+			name:       "off",
+			wantInText: []string{"*Today's Facilitator:* alice\n", "*Tomorrow's Facilitator:* bob\n", "• carol (vacation)\n", "Standup time!"},
+			wantInCard: []string{"Tomorrow's Facilitator", "On Leave Today", "Standup time!"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			setNow(time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC))
+			alice := mustCreateUser(t, "alice")
+			bob := mustCreateUser(t, "bob")
+			carol := mustCreateUser(t, "carol")
+			standup := mustCreateStandup(t, "daily", alice, bob, carol)
+			mustCreateApprovedLeave(t, carol, "vacation", "2025-03-12", "2025-03-12")
+			if err := SetStandupFacilitatorOnly(standup.ID, tt.facilitatorOnly); err != nil {
+				t.Fatalf("SetStandupFacilitatorOnly: %v", err)
+			}
+			if err := SetStandupTemplate(standup.ID, tt.template); err != nil {
+				t.Fatalf("SetStandupTemplate: %v", err)
+			}
+
+			reminder, err := BuildStandupMessage(standup.ID, ReminderOptions{})
+			if err != nil {
+				t.Fatalf("BuildStandupMessage: %v", err)
+			}
+			if tt.wantText != "" && reminder.Message != tt.wantText {
+				t.Errorf("message = %q, want %q", reminder.Message, tt.wantText)
+			}
+			for _, want := range tt.wantInText {
+				if !strings.Contains(reminder.Message, want) {
+					t.Errorf("message does not contain %q:\n%s", want, reminder.Message)
+				}
+			}
+
+			encoded, err := json.Marshal(renderReminderCard(reminder))
+			if err != nil {
+				t.Fatalf("marshal card: %v", err)
+			}
+			card := string(encoded)
+			for _, want := range tt.wantInCard {
+				if !strings.Contains(card, want) {
+					t.Errorf("card does not contain %q:\n%s", want, card)
+				}
+			}
+			for _, unwanted := range tt.wantNotInCard {
+				if strings.Contains(card, unwanted) {
+					t.Errorf("card contains %q:\n%s", unwanted, card)
+				}
+			}
+		})
+	}
+}
+
+func TestStandupThreadKey(t *testing.T) {
+	// 20:00 UTC on 12 March is already the 13th in Tokyo
+	clock := time.Date(2025, 3, 12, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		dailyThread bool
+		threadKey   string
+		timezone    string
+		want        string
+	}{
+		{"main timeline", false, "", "", ""},
+		{"fixed thread", false, "standup", "", "standup"},
+		{"daily thread", true, "", "", "standup-7-20250312"},
+		{"daily thread with a key prefix", true, "standup", "", "standup-20250312"},
+		{"daily thread in the standup's timezone", true, "", "Asia/Tokyo", "standup-7-20250313"},
+		{"daily thread prefix in the standup's timezone", true, "standup", "Asia/Tokyo", "standup-20250313"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			setNow(clock)
+
+			standup := &database.Standup{ID: 7, DailyThread: tt.dailyThread, ThreadKey: tt.threadKey, Timezone: tt.timezone}
+			if got := standupThreadKey(standup); got != tt.want {
+				t.Errorf("standupThreadKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailyThreadSends(t *testing.T) {
+	setupTestDB(t)
+	webhook := newWebhookRecorder(t)
+	alice := mustCreateUser(t, "alice")
+	standup := mustCreateStandup(t, "daily", alice)
+	if err := SetStandupDailyThread(standup.ID, true); err != nil {
+		t.Fatalf("SetStandupDailyThread: %v", err)
+	}
+
+	days := []time.Time{
+		time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 12, 15, 0, 0, 0, time.UTC), // a re-send the same day joins the thread
+		time.Date(2025, 3, 13, 9, 0, 0, 0, time.UTC),
+	}
+	for _, day := range days {
+		setNow(day)
+		if err := SendManualStandupReminder(standup.ID, ReminderOptions{}); err != nil {
+			t.Fatalf("SendManualStandupReminder: %v", err)
+		}
+	}
+
+	var keys []string
+	for _, body := range webhook.posts() {
+		var message struct {
+			Thread struct {
+				ThreadKey string `json:"threadKey"`
+			} `json:"thread"`
+		}
+		if err := json.Unmarshal([]byte(body), &message); err != nil {
+			t.Fatalf("decode post: %v", err)
+		}
+		keys = append(keys, message.Thread.ThreadKey)
+	}
+	prefix := fmt.Sprintf("standup-%d-", standup.ID)
+	want := []string{prefix + "20250312", prefix + "20250312", prefix + "20250313"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("thread keys = %v, want %v", keys, want)
+	}
+
+	for _, url := range webhook.requestURLs() {
+		if !strings.Contains(url, "messageReplyOption=REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD") {
+			t.Errorf("post to %s does not ask to reply in the thread", url)
+		}
+	}
+}
+
+func TestPreviewStandupReminder(t *testing.T) {
+	tuesday := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		at           time.Time
+		daysOfWeek   string
+		skipWeekends bool
+		template     string
+		want         []SkipReason
+	}{
+		{name: "scheduled day", at: tuesday, daysOfWeek: "MON,TUE"},
+		{name: "every day", at: tuesday},
+		{name: "not in days_of_week", at: tuesday, daysOfWeek: "MON,WED,FRI", want: []SkipReason{SkipReasonNotScheduled}},
+		{name: "weekend", at: tuesday.AddDate(0, 0, 4), skipWeekends: true, want: []SkipReason{SkipReasonWeekend}},
+		{name: "broken template", at: tuesday, template: "{{.Nope}}", want: []SkipReason{SkipReasonTemplateError}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			newWebhookRecorder(t)
+			config.Config.SkipWeekends = tt.skipWeekends
+			setNow(tt.at)
+
+			standup := mustCreateStandup(t, "daily", mustCreateUser(t, "alice"), mustCreateUser(t, "bob"))
+			if err := SetStandupDaysOfWeek(standup.ID, tt.daysOfWeek); err != nil {
+				t.Fatalf("SetStandupDaysOfWeek: %v", err)
+			}
+			// Stored directly: SetStandupTemplate refuses templates that do not render
+			if _, err := database.DB.Exec("UPDATE standups SET template = ? WHERE id = ?", tt.template, standup.ID); err != nil {
+				t.Fatalf("set template: %v", err)
+			}
+
+			preview, err := PreviewStandupReminder(standup.ID)
+			if err != nil {
+				t.Fatalf("PreviewStandupReminder: %v", err)
+			}
+
+			var got []SkipReason
+			for _, diagnostic := range preview.Diagnostics {
+				got = append(got, diagnostic.Reason)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diagnostics = %v, want %v", preview.Diagnostics, tt.want)
+			}
+			if preview.WouldSend != (len(tt.want) == 0) {
+				t.Errorf("would_send = %v with diagnostics %v", preview.WouldSend, preview.Diagnostics)
+			}
+			if tt.template != "" && !strings.HasPrefix(preview.Message, "🌅 *daily*") {
+				t.Errorf("message = %q, want the built-in format", preview.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"google-chat-bot/database"
+)
+
+// scheduleEmptyRetry registers the one-shot re-check for a scheduled send that found nobody
+// eligible, empty_retry_minutes later, and returns the run history detail describing it.
+// No retry is registered when it would fall on the next day. Like snoozes, the retry job is
+// lost if the process restarts before it fires.
+func scheduleEmptyRetry(standup *database.Standup) string {
+	loc := standupLocation(standup)
+	current := now().In(loc)
+	at := current.Add(time.Duration(standup.EmptyRetryMinutes) * time.Minute)
+	if at.Format("2006-01-02") != current.Format("2006-01-02") {
+		slog.Info("⏭️  [NO RETRY] Retry would cross midnight, skipping the day", "standup_id", standup.ID)
+		return "retry would fall on the next day"
+	}
+
+	standupID := standup.ID
+	schedulerMu.Lock()
+	cronScheduler.Schedule(onceSchedule{at: at}, cron.FuncJob(func() {
+		// Someone may have sent the reminder by hand in the meantime
+		sent, err := HasRunWithStatus(standupID, StandupToday(standupID), RunStatusSent)
+		if err != nil {
+			slog.Warn("Could not check today's runs", "standup_id", standupID, "error", err)
+		} else if sent {
+			slog.Info("⏭️  [RETRY CANCELLED] Already sent today", "standup_id", standupID)
+			return
+		}
+
+		_ = sendStandupReminder(standupID, ReminderOptions{Trigger: RunTriggerRetry})
+	}))
+	schedulerMu.Unlock()
+
+	slog.Info("🔁 [RETRY SCHEDULED] Will re-check eligibility", "standup_id", standupID, "at", at.Format("15:04"))
+	return fmt.Sprintf("retrying at %s", at.Format("15:04"))
+}
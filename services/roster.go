@@ -2,45 +2,71 @@ package services
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"net/mail"
 	"time"
 
 	"google-chat-bot/database"
 )
 
+var (
+	// ErrUserNotFound is returned when no user matches the given ID
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUserExists is returned when a user with the same google_chat_user_id is already on the roster
+	ErrUserExists = errors.New("a user with this google_chat_user_id already exists")
+	// ErrInvalidEmail is returned for a non-empty email that is not a plain address
+	ErrInvalidEmail = errors.New("invalid email")
+)
+
+// ValidateEmail checks that a non-empty email is a single plain address like name@example.com.
+// Email is optional, so "" is valid.
+func ValidateEmail(email string) error {
+	if email == "" {
+		return nil
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return fmt.Errorf("%w %q", ErrInvalidEmail, email)
+	}
+	return nil
+}
+
 // CreateUser adds a new user to the roster
 func CreateUser(googleChatUserID, displayName, email string) (*database.User, error) {
+	if err := ValidateEmail(email); err != nil {
+		return nil, err
+	}
+
 	query := `
 		INSERT INTO users (google_chat_user_id, display_name, email, is_active)
-		VALUES (?, ?, ?, 1)
+		VALUES (?, ?, ?, TRUE)
+		RETURNING id
 	`
 
-	result, err := database.DB.Exec(query, googleChatUserID, displayName, email)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+	var id int
+	err := database.DB.QueryRow(query, googleChatUserID, displayName, email).Scan(&id)
+	if database.IsUniqueViolation(err) {
+		return nil, ErrUserExists
 	}
-
-	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	return GetUserByID(int(id))
+	return GetUserByID(id)
 }
 
-// GetUserByID retrieves a user by ID
-func GetUserByID(id int) (*database.User, error) {
-	query := `
-		SELECT id, google_chat_user_id, display_name, email, is_active,
-		       joined_at, left_at, created_at, updated_at
-		FROM users
-		WHERE id = ?
-	`
+// userColumns is the column list shared by all user queries, in scanUser order
+const userColumns = `id, google_chat_user_id, display_name, email, is_active,
+	       joined_at, left_at, created_at, updated_at`
 
+// scanUser scans a single user row selected with userColumns
+func scanUser(row interface{ Scan(...interface{}) error }) (database.User, error) {
 	var user database.User
 	var leftAt sql.NullTime
 
-	err := database.DB.QueryRow(query, id).Scan(
+	err := row.Scan(
 		&user.ID,
 		&user.GoogleChatUserID,
 		&user.DisplayName,
@@ -51,31 +77,20 @@ func GetUserByID(id int) (*database.User, error) {
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
-		}
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return user, err
 	}
 
 	if leftAt.Valid {
 		user.LeftAt = &leftAt.Time
 	}
 
-	return &user, nil
+	return user, nil
 }
 
-// GetAllUsers retrieves all users
-func GetAllUsers() ([]database.User, error) {
-	query := `
-		SELECT id, google_chat_user_id, display_name, email, is_active,
-		       joined_at, left_at, created_at, updated_at
-		FROM users
-		ORDER BY display_name
-	`
-
-	rows, err := database.DB.Query(query)
+// queryUsers runs a user query and scans every returned row
+func queryUsers(query string, args ...interface{}) ([]database.User, error) {
+	rows, err := database.DB.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query users: %w", err)
 	}
@@ -83,82 +98,132 @@ func GetAllUsers() ([]database.User, error) {
 
 	var users []database.User
 	for rows.Next() {
-		var user database.User
-		var leftAt sql.NullTime
-
-		err := rows.Scan(
-			&user.ID,
-			&user.GoogleChatUserID,
-			&user.DisplayName,
-			&user.Email,
-			&user.IsActive,
-			&user.JoinedAt,
-			&leftAt,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// GetUserByID retrieves a user by ID
+func GetUserByID(id int) (*database.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id = ?
+	`
 
-		if leftAt.Valid {
-			user.LeftAt = &leftAt.Time
+	user, err := scanUser(database.DB.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
 		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
 
-		users = append(users, user)
+	return &user, nil
+}
+
+// GetUserByGoogleChatID retrieves a user by their Google Chat user ID (e.g. "users/123")
+func GetUserByGoogleChatID(googleChatUserID string) (*database.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE google_chat_user_id = ?
+	`
+
+	user, err := scanUser(database.DB.QueryRow(query, googleChatUserID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return users, nil
+	return &user, nil
 }
 
-// GetActiveUsers retrieves all active users (not permanently deactivated)
-func GetActiveUsers() ([]database.User, error) {
+// GetAllUsers retrieves all users
+func GetAllUsers() ([]database.User, error) {
+	defer database.TimeQuery("GetAllUsers", time.Now())
+
 	query := `
-		SELECT id, google_chat_user_id, display_name, email, is_active,
-		       joined_at, left_at, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE is_active = 1
 		ORDER BY display_name
 	`
 
-	rows, err := database.DB.Query(query)
+	return queryUsers(query)
+}
+
+// GetAllUsersPaginated retrieves a page of users along with the total count
+func GetAllUsersPaginated(limit, offset int) ([]database.User, int, error) {
+	var total int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM users").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		ORDER BY display_name, id
+		LIMIT ? OFFSET ?
+	`
+
+	users, err := queryUsers(query, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query active users: %w", err)
+		return nil, 0, err
 	}
-	defer rows.Close()
 
-	var users []database.User
-	for rows.Next() {
-		var user database.User
-		var leftAt sql.NullTime
-
-		err := rows.Scan(
-			&user.ID,
-			&user.GoogleChatUserID,
-			&user.DisplayName,
-			&user.Email,
-			&user.IsActive,
-			&user.JoinedAt,
-			&leftAt,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
-		}
+	return users, total, nil
+}
 
-		if leftAt.Valid {
-			user.LeftAt = &leftAt.Time
-		}
+// GetActiveUsers retrieves all active users (not permanently deactivated)
+func GetActiveUsers() ([]database.User, error) {
+	defer database.TimeQuery("GetActiveUsers", time.Now())
 
-		users = append(users, user)
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE is_active = TRUE
+		ORDER BY display_name
+	`
+
+	return queryUsers(query)
+}
+
+// GetActiveUsersPaginated retrieves a page of active users along with the total count
+func GetActiveUsersPaginated(limit, offset int) ([]database.User, int, error) {
+	var total int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM users WHERE is_active = TRUE").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count active users: %w", err)
 	}
 
-	return users, nil
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE is_active = TRUE
+		ORDER BY display_name, id
+		LIMIT ? OFFSET ?
+	`
+
+	users, err := queryUsers(query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
 }
 
 // UpdateUser updates a user's information
 func UpdateUser(id int, displayName, email string) error {
+	if err := ValidateEmail(email); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE users
 		SET display_name = ?, email = ?, updated_at = CURRENT_TIMESTAMP
@@ -183,7 +248,7 @@ func DeactivateUser(id int) error {
 	now := time.Now()
 	query := `
 		UPDATE users
-		SET is_active = 0, left_at = ?, updated_at = CURRENT_TIMESTAMP
+		SET is_active = FALSE, left_at = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
 
@@ -204,7 +269,7 @@ func DeactivateUser(id int) error {
 func ReactivateUser(id int) error {
 	query := `
 		UPDATE users
-		SET is_active = 1, left_at = NULL, updated_at = CURRENT_TIMESTAMP
+		SET is_active = TRUE, left_at = NULL, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
 
@@ -215,8 +280,57 @@ func ReactivateUser(id int) error {
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
 	}
 
 	return nil
 }
+
+// UserStandupToday is one standup a user is expected at today
+type UserStandupToday struct {
+	Standup       database.Standup `json:"standup"`
+	IsFacilitator bool             `json:"is_facilitator"`
+}
+
+// GetUserStandupsToday returns the active standups that run today where the user is eligible,
+// and whether they are the computed current facilitator for each. "Today" is each standup's own
+// date, and standups skipped today (weekend, holiday, not in days_of_week) are left out.
+func GetUserStandupsToday(userID int) ([]UserStandupToday, error) {
+	defer database.TimeQuery("GetUserStandupsToday", time.Now())
+
+	if _, err := GetUserByID(userID); err != nil {
+		return nil, err
+	}
+
+	standups, err := GetStandupsForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	obligations := []UserStandupToday{}
+	for _, standup := range standups {
+		if reason, _ := scheduledRunSkipReason(&standup, now()); reason != "" {
+			continue
+		}
+
+		eligible, err := database.GetEligibleUsersForStandup(standup.ID, standupToday(&standup))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get eligible users for standup %d: %w", standup.ID, err)
+		}
+		if findUser(eligible, userID) == nil {
+			continue
+		}
+
+		current, err := GetCurrentFacilitator(standup.ID, eligible)
+		if err != nil {
+			return nil, err
+		}
+
+		obligations = append(obligations, UserStandupToday{
+			Standup:       standup,
+			IsFacilitator: current.ID == userID,
+		})
+	}
+
+	return obligations, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"google-chat-bot/database"
+)
+
+// MaxRosterImportRows caps how many users a single roster import may contain
+const MaxRosterImportRows = 500
+
+// ErrTooManyImportRows is returned when a roster import exceeds MaxRosterImportRows
+var ErrTooManyImportRows = fmt.Errorf("a roster import may contain at most %d rows", MaxRosterImportRows)
+
+// Roster import row outcomes
+const (
+	RosterImportCreated = "created"
+	RosterImportUpdated = "updated"
+	RosterImportSkipped = "skipped"
+)
+
+// RosterImportRow is one user in a roster import
+type RosterImportRow struct {
+	Line             int // Line in the uploaded file, reported back in the results
+	GoogleChatUserID string
+	DisplayName      string
+	Email            *string // nil keeps an existing user's email (no email column)
+}
+
+// RosterImportResult is the outcome of one roster import row
+type RosterImportResult struct {
+	Line             int    `json:"line"`
+	GoogleChatUserID string `json:"google_chat_user_id"`
+	Status           string `json:"status"` // created, updated or skipped
+	UserID           int    `json:"user_id,omitempty"`
+	Reason           string `json:"reason,omitempty"` // Why the row was skipped
+}
+
+// ImportRoster creates users that don't exist yet and updates the display name and email of
+// those that do (matched by google_chat_user_id), in one transaction. Invalid rows are skipped
+// and reported rather than failing the import, so the valid rows are still applied.
+func ImportRoster(rows []RosterImportRow) ([]RosterImportResult, error) {
+	if len(rows) > MaxRosterImportRows {
+		return nil, ErrTooManyImportRows
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	results := make([]RosterImportResult, 0, len(rows))
+	seen := make(map[string]int)
+	for _, row := range rows {
+		result := RosterImportResult{
+			Line:             row.Line,
+			GoogleChatUserID: strings.TrimSpace(row.GoogleChatUserID),
+			Status:           RosterImportSkipped,
+		}
+		displayName := strings.TrimSpace(row.DisplayName)
+		var email *string
+		if row.Email != nil {
+			trimmed := strings.TrimSpace(*row.Email)
+			email = &trimmed
+		}
+
+		switch {
+		case result.GoogleChatUserID == "":
+			result.Reason = "google_chat_user_id is required"
+		case displayName == "":
+			result.Reason = "display_name is required"
+		case seen[result.GoogleChatUserID] != 0:
+			result.Reason = fmt.Sprintf("duplicate of line %d", seen[result.GoogleChatUserID])
+		default:
+			if email != nil {
+				if err := ValidateEmail(*email); err != nil {
+					result.Reason = err.Error()
+					break
+				}
+			}
+			seen[result.GoogleChatUserID] = row.Line
+
+			if err := importRosterRow(tx, &result, displayName, email); err != nil {
+				return nil, err
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return results, nil
+}
+
+// importRosterRow creates or updates one validated row, filling in its outcome
+func importRosterRow(tx *sql.Tx, result *RosterImportResult, displayName string, email *string) error {
+	var (
+		id           int
+		currentName  string
+		currentEmail sql.NullString
+	)
+	err := tx.QueryRow(
+		"SELECT id, display_name, email FROM users WHERE google_chat_user_id = ?",
+		result.GoogleChatUserID,
+	).Scan(&id, &currentName, &currentEmail)
+
+	if err == sql.ErrNoRows {
+		if email == nil {
+			email = new(string)
+		}
+		err := tx.QueryRow(
+			"INSERT INTO users (google_chat_user_id, display_name, email, is_active) VALUES (?, ?, ?, TRUE) RETURNING id",
+			result.GoogleChatUserID, displayName, *email,
+		).Scan(&result.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to create user %s: %w", result.GoogleChatUserID, err)
+		}
+
+		result.Status = RosterImportCreated
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up user %s: %w", result.GoogleChatUserID, err)
+	}
+
+	result.UserID = id
+	if email == nil {
+		email = &currentEmail.String
+	}
+	if currentName == displayName && currentEmail.String == *email {
+		result.Reason = "unchanged"
+		return nil
+	}
+
+	_, err = tx.Exec(
+		"UPDATE users SET display_name = ?, email = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		displayName, *email, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user %s: %w", result.GoogleChatUserID, err)
+	}
+
+	result.Status = RosterImportUpdated
+	return nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"google-chat-bot/config"
+)
+
+func TestGetUserStandupsToday(t *testing.T) {
+	// Tuesday 2026-03-10, 23:00 UTC: already Wednesday 2026-03-11 in Kiritimati (UTC+14)
+	tuesdayNight := time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		at           time.Time
+		skipWeekends bool
+		holiday      string
+		leave        string // a one-day leave for alice
+		want         []string
+	}{
+		{name: "regular day", at: tuesdayNight, want: []string{"daily", "mwf", "pacific"}},
+		{name: "weekend", at: tuesdayNight.AddDate(0, 0, 4), skipWeekends: true, want: []string{}},
+		{name: "holiday", at: tuesdayNight, holiday: "2026-03-10", want: []string{"mwf", "pacific"}},
+		{name: "holiday on the standup's own date", at: tuesdayNight, holiday: "2026-03-11", want: []string{"daily"}},
+		{name: "leave on the standup's own date", at: tuesdayNight, leave: "2026-03-11", want: []string{"daily"}},
+		{name: "not in days_of_week", at: tuesdayNight.AddDate(0, 0, 1), want: []string{"daily", "pacific"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			config.Config.SkipWeekends = tt.skipWeekends
+			setNow(tt.at)
+
+			alice := mustCreateUser(t, "alice")
+			mustCreateStandup(t, "daily", alice)
+
+			// Runs Monday, Wednesday and Friday in Kiritimati, where it is Wednesday
+			mwf := mustCreateStandup(t, "mwf", alice)
+			if err := setStandupField(mwf.ID, "timezone", "Pacific/Kiritimati"); err != nil {
+				t.Fatalf("set timezone: %v", err)
+			}
+			if err := SetStandupDaysOfWeek(mwf.ID, "MON,WED,FRI"); err != nil {
+				t.Fatalf("SetStandupDaysOfWeek: %v", err)
+			}
+
+			pacific := mustCreateStandup(t, "pacific", alice)
+			if err := setStandupField(pacific.ID, "timezone", "Pacific/Kiritimati"); err != nil {
+				t.Fatalf("set timezone: %v", err)
+			}
+			if tt.holiday != "" {
+				if _, err := CreateHoliday(tt.holiday, ""); err != nil {
+					t.Fatalf("CreateHoliday: %v", err)
+				}
+			}
+
+			if tt.leave != "" {
+				mustCreateApprovedLeave(t, alice, "vacation", tt.leave, tt.leave)
+			}
+
+			obligations, err := GetUserStandupsToday(alice.ID)
+			if err != nil {
+				t.Fatalf("GetUserStandupsToday: %v", err)
+			}
+
+			got := []string{}
+			for _, obligation := range obligations {
+				got = append(got, obligation.Standup.Name)
+				if !obligation.IsFacilitator {
+					t.Errorf("%s: alice is the only member but is not the facilitator", obligation.Standup.Name)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("standups today = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
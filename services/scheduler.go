@@ -1,26 +1,96 @@
 package services
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
+	"sync"
 	"time"
 
 	"google-chat-bot/config"
 	"google-chat-bot/database"
-	"google-chat-bot/integrations"
+	"google-chat-bot/metrics"
 	"github.com/robfig/cron/v3"
 )
 
 var cronScheduler *cron.Cron
 
+// standupEntries maps standup ID to its reminder job on cronScheduler, so a single
+// standup can be rescheduled without rebuilding the scheduler (guarded by schedulerMu)
+var standupEntries = make(map[int]cron.EntryID)
+
+// maintenanceEntries are the maintenance jobs on cronScheduler, so RefreshScheduler can
+// replace them without touching one-shot snooze and retry jobs (guarded by schedulerMu)
+var maintenanceEntries []cron.EntryID
+
+// scheduledSendLocks holds a *sync.Mutex per standup ID. Scheduled sends of a standup run
+// one at a time, so a duplicate fire sees the run recorded by the first (see sendStandupReminder).
+var scheduledSendLocks sync.Map
+
+// schedulerMu serialises scheduler refreshes and per-standup job changes so concurrent updates don't interleave
+var schedulerMu sync.Mutex
+
+var (
+	// sendSlots throttles concurrent webhook posts across all standups (sized by SEND_CONCURRENCY)
+	sendSlots     chan struct{}
+	sendSlotsOnce sync.Once
+)
+
+// inFlightSends tracks reminder sends still running, so shutdown can let them finalize their history
+var inFlightSends sync.WaitGroup
+
+// schedulerStopped is done once the jobs running when StopScheduler was called have returned
+var schedulerStopped context.Context
+
+// WaitForSends blocks until in-flight reminder sends finish or the timeout passes.
+// It reports whether every send finished. Call it after StopScheduler, so no new
+// cron jobs start and jobs that fired just before the stop are waited for too.
+func WaitForSends(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		if schedulerStopped != nil {
+			<-schedulerStopped.Done()
+		}
+		inFlightSends.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// acquireSendSlot blocks until a webhook send slot is free and returns a func that releases it
+func acquireSendSlot() func() {
+	sendSlotsOnce.Do(func() {
+		sendSlots = make(chan struct{}, config.Config.SendConcurrency)
+	})
+
+	sendSlots <- struct{}{}
+	return func() { <-sendSlots }
+}
+
 // StartScheduler initializes and starts the cron scheduler
 func StartScheduler() error {
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
+
 	cronScheduler = cron.New()
+	standupEntries = make(map[int]cron.EntryID)
+	maintenanceEntries = nil
+
+	if locale := config.Config.MessageLocale; locale != "" && !IsSupportedLocale(locale) {
+		slog.Warn("Unsupported MESSAGE_LOCALE, dates will be rendered in English", "locale", locale)
+	}
 
-	// Schedule leave expiration check (runs daily at midnight)
-	_, err := cronScheduler.AddFunc("0 0 * * *", ExpireLeaves)
+	// Schedule leave maintenance jobs (expiration, retention purge)
+	err := scheduleMaintenanceJobs()
 	if err != nil {
-		return fmt.Errorf("failed to schedule leave expiration: %w", err)
+		return err
 	}
 
 	// Schedule all active standups
@@ -28,20 +98,52 @@ func StartScheduler() error {
 	if err != nil {
 		return fmt.Errorf("failed to schedule standups: %w", err)
 	}
+	if err := schedulePendingSnoozes(); err != nil {
+		return err
+	}
 
 	cronScheduler.Start()
-	log.Println("Scheduler started")
+	slog.Info("Scheduler started")
 	return nil
 }
 
-// StopScheduler stops the cron scheduler
+// StopScheduler stops the cron scheduler; jobs already running carry on (see WaitForSends)
 func StopScheduler() {
 	if cronScheduler != nil {
-		cronScheduler.Stop()
-		log.Println("Scheduler stopped")
+		schedulerStopped = cronScheduler.Stop()
+		slog.Info("Scheduler stopped")
 	}
 }
 
+// scheduleMaintenanceJobs registers the nightly leave expiration, the hourly idempotency
+// key cleanup and, when a retention period is configured, the leave purge job
+func scheduleMaintenanceJobs() error {
+	// Leave expiration check (runs daily at midnight)
+	entryID, err := cronScheduler.AddFunc("0 0 * * *", ExpireLeaves)
+	if err != nil {
+		return fmt.Errorf("failed to schedule leave expiration: %w", err)
+	}
+	maintenanceEntries = append(maintenanceEntries, entryID)
+
+	// Leave purge (runs daily shortly after expiration)
+	if config.Config.LeaveRetentionDays > 0 {
+		entryID, err = cronScheduler.AddFunc("30 0 * * *", PurgeExpiredLeaves)
+		if err != nil {
+			return fmt.Errorf("failed to schedule leave purge: %w", err)
+		}
+		maintenanceEntries = append(maintenanceEntries, entryID)
+	}
+
+	// Expired idempotency keys (runs hourly)
+	entryID, err = cronScheduler.AddFunc("15 * * * *", PurgeExpiredIdempotencyKeys)
+	if err != nil {
+		return fmt.Errorf("failed to schedule idempotency key cleanup: %w", err)
+	}
+	maintenanceEntries = append(maintenanceEntries, entryID)
+
+	return nil
+}
+
 // ScheduleAllStandups schedules reminder jobs for all active standups
 func ScheduleAllStandups() error {
 	standups, err := GetActiveStandups()
@@ -52,15 +154,16 @@ func ScheduleAllStandups() error {
 	for _, standup := range standups {
 		err = ScheduleStandup(standup)
 		if err != nil {
-			log.Printf("Warning: Failed to schedule standup %d (%s): %v", standup.ID, standup.Name, err)
+			slog.Warn("Failed to schedule standup", "standup_id", standup.ID, "standup", standup.Name, "error", err)
 		}
 	}
 
-	log.Printf("Scheduled %d active standup(s)", len(standups))
+	slog.Info("Scheduled active standups", "count", len(standups))
 	return nil
 }
 
-// ScheduleStandup schedules a single standup
+// ScheduleStandup schedules a single standup and records its job in standupEntries.
+// Callers must hold schedulerMu.
 func ScheduleStandup(standup database.Standup) error {
 	// Parse run_at time (format: HH:MM)
 	parsedTime, err := time.Parse("15:04", standup.RunAt)
@@ -71,114 +174,270 @@ func ScheduleStandup(standup database.Standup) error {
 	hour := parsedTime.Hour()
 	minute := parsedTime.Minute()
 
-	// Build cron expression: "minute hour * * *"
-	cronSpec := fmt.Sprintf("%d %d * * *", minute, hour)
+	// Build cron expression: "minute hour * * days", in the standup's own timezone when it has one
+	daysOfWeek := "*"
+	if standup.DaysOfWeek != "" {
+		daysOfWeek = standup.DaysOfWeek
+	}
+	cronSpec := fmt.Sprintf("%d %d * * %s", minute, hour, daysOfWeek)
+	if standup.Timezone != "" {
+		cronSpec = fmt.Sprintf("CRON_TZ=%s %s", standup.Timezone, cronSpec)
+	}
 
 	// Add the job
-	_, err = cronScheduler.AddFunc(cronSpec, func() {
+	entryID, err := cronScheduler.AddFunc(cronSpec, func() {
 		SendStandupReminder(standup.ID)
 	})
 
 	if err != nil {
 		return fmt.Errorf("failed to add cron job: %w", err)
 	}
+	standupEntries[standup.ID] = entryID
 
-	log.Printf("Scheduled standup '%s' (ID: %d) at %s", standup.Name, standup.ID, standup.RunAt)
+	slog.Debug("Scheduled standup", "standup_id", standup.ID, "standup", standup.Name, "run_at", standup.RunAt, "timezone", standup.EffectiveTimezone)
 	return nil
 }
 
-// SendStandupReminder sends a reminder for a specific standup
-func SendStandupReminder(standupID int) {
-	startTime := time.Now()
-	log.Printf("⏰ [SCHEDULE TRIGGER] Standup reminder job started for ID: %d at %s", standupID, startTime.Format("2006-01-02 15:04:05"))
-
-	// Check if we should skip today (weekends)
-	if config.Config.SkipWeekends {
-		today := time.Now().Weekday()
-		if today == time.Saturday || today == time.Sunday {
-			log.Printf("⏭️  [SKIPPED] Standup ID: %d skipped - Weekend (%s)", standupID, today.String())
-			return
-		}
+// ReminderOptions customises a single reminder send
+type ReminderOptions struct {
+	// FacilitatorID forces a specific facilitator for this send instead of the computed one (0 = computed)
+	FacilitatorID int
+	// SkipRotation leaves last_facilitator_id untouched after the send
+	SkipRotation bool
+	// Rotate overrides the standup's manual_send_rotates setting for a manual send (nil = use the setting)
+	Rotate *bool
+	// Trigger records what started the send in run history (defaults to scheduled)
+	Trigger string
+	// Force bypasses the weekend, holiday and single-eligible-member skips for this send
+	Force bool
+}
+
+// RescheduleStandup replaces a single standup's reminder job with one built from its
+// current settings, leaving every other job running. Inactive standups end up unscheduled.
+func RescheduleStandup(id int) error {
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
+
+	if cronScheduler == nil {
+		return nil
 	}
 
-	// Get standup details
-	standup, err := GetStandupByID(standupID)
+	unscheduleStandup(id)
+
+	standup, err := GetStandupByID(id)
 	if err != nil {
-		log.Printf("Error getting standup: %v", err)
-		return
+		return err
 	}
 
-	// Check if standup is still active
 	if !standup.IsActive {
-		log.Printf("Standup %d (%s) is no longer active", standupID, standup.Name)
-		return
+		slog.Debug("Unscheduled inactive standup", "standup_id", standup.ID, "standup", standup.Name)
+		return nil
 	}
 
-	// Get eligible users (active and not on leave)
-	users, err := database.GetEligibleUsersForStandup(standupID)
-	if err != nil {
-		log.Printf("Error getting eligible users for standup %d: %v", standupID, err)
+	return ScheduleStandup(*standup)
+}
+
+// UnscheduleStandup removes a single standup's reminder job, if it has one
+func UnscheduleStandup(id int) {
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
+
+	if cronScheduler == nil {
 		return
 	}
 
-	if len(users) == 0 {
-		log.Printf("No eligible users for standup %d (%s)", standupID, standup.Name)
-		return
+	if unscheduleStandup(id) {
+		slog.Debug("Unscheduled standup", "standup_id", id)
+	}
+}
+
+// unscheduleStandup removes a standup's job and reports whether it had one. Callers must hold schedulerMu.
+func unscheduleStandup(id int) bool {
+	entryID, ok := standupEntries[id]
+	if !ok {
+		return false
+	}
+
+	cronScheduler.Remove(entryID)
+	delete(standupEntries, id)
+	return true
+}
+
+// maxSkippedRuns bounds how many skipped fire times NextRunTime looks past before giving up
+const maxSkippedRuns = 366
+
+// NextRunTime returns when the standup's scheduled job will next actually send, looking past
+// fire times that would be skipped for weekends or holidays. It returns nil when the standup
+// has no job (inactive, or the scheduler is not running) or no send within maxSkippedRuns.
+func NextRunTime(standupID int) (*time.Time, error) {
+	schedulerMu.Lock()
+	entryID, ok := standupEntries[standupID]
+	var schedule cron.Schedule
+	if ok && cronScheduler != nil {
+		schedule = cronScheduler.Entry(entryID).Schedule
+	}
+	schedulerMu.Unlock()
+
+	if schedule == nil {
+		return nil, nil
 	}
 
-	// Calculate current facilitator from eligible users
-	currentFacilitator, err := GetCurrentFacilitator(standupID, users)
+	standup, err := GetStandupByID(standupID)
 	if err != nil {
-		log.Printf("Error getting current facilitator for standup %d: %v", standupID, err)
-		return
+		return nil, err
+	}
+	if !standup.IsActive {
+		return nil, nil
 	}
 
-	// Calculate tomorrow's facilitator
-	var nextFacilitator *database.User
-	if currentFacilitator != nil {
-		nextFacilitator, err = GetNextFacilitator(standupID, users, currentFacilitator.ID)
-		if err != nil {
-			log.Printf("Warning: Could not get next facilitator for standup %d: %v", standupID, err)
+	next := now()
+	for i := 0; i < maxSkippedRuns; i++ {
+		next = schedule.Next(next)
+		if reason, _ := scheduleSkipReason(standup, next); reason == "" {
+			next = next.In(standupLocation(standup))
+			return &next, nil
 		}
 	}
 
-	// Get active leaves for today
-	activeLeaves, err := database.GetActiveLeavesForStandup(standupID)
+	return nil, nil
+}
+
+// SendStandupReminder sends a reminder for a specific standup. It is the cron job entry
+// point: the outcome is logged and recorded in run history by sendStandupReminder.
+func SendStandupReminder(standupID int) {
+	_ = sendStandupReminder(standupID, ReminderOptions{Trigger: RunTriggerScheduled})
+}
+
+// sendStandupReminder sends a reminder for a specific standup using the given options.
+// It returns a *SkipError when the send was skipped and wraps ErrSendFailed when the
+// webhook post failed; a failure to advance the rotation afterwards is only logged.
+func sendStandupReminder(standupID int, opts ReminderOptions) error {
+	inFlightSends.Add(1)
+	defer inFlightSends.Done()
+
+	startTime := time.Now()
+	slog.Debug("⏰ [SCHEDULE TRIGGER] Standup reminder job started", "standup_id", standupID, "started_at", startTime.Format("2006-01-02 15:04:05"))
+
+	if opts.Trigger == "" {
+		opts.Trigger = RunTriggerScheduled
+	}
+
+	// Scheduled sends of one standup run one at a time, for the same-day guard below
+	if opts.Trigger == RunTriggerScheduled {
+		lock, _ := scheduledSendLocks.LoadOrStore(standupID, &sync.Mutex{})
+		lock.(*sync.Mutex).Lock()
+		defer lock.(*sync.Mutex).Unlock()
+	}
+
+	// Get standup details
+	standup, err := GetStandupByID(standupID)
 	if err != nil {
-		log.Printf("Warning: Could not get active leaves for standup %d: %v", standupID, err)
+		slog.Error("Failed to get standup", "standup_id", standupID, "error", err)
+		return err
 	}
 
-	// Build the reminder message
-	message := fmt.Sprintf("🌅 *%s*\n\n", standup.Name)
+	// The recurring job fires once a day, so a second scheduled run for the same day is a
+	// duplicate fire (e.g. during a scheduler refresh) and is dropped without recording anything
+	if opts.Trigger == RunTriggerScheduled {
+		ran, err := HasRunWithTrigger(standupID, standupToday(standup), RunTriggerScheduled)
+		if err != nil {
+			slog.Warn("Could not check today's runs", "standup_id", standupID, "error", err)
+		} else if ran {
+			slog.Info("⏭️  [SKIPPED] Already ran today", "standup_id", standupID)
+			return &SkipError{Reason: SkipReasonAlreadyRan, Detail: "a scheduled run already exists for today"}
+		}
+	}
 
-	// Add current facilitator if available
-	if currentFacilitator != nil {
-		message += fmt.Sprintf("👤 *Today's Facilitator:* %s\n", currentFacilitator.DisplayName)
+	// Check if we should skip today (inactive standup, weekends, holidays)
+	reason, detail := scheduleSkipReason(standup, now())
+	if (reason == SkipReasonWeekend || reason == SkipReasonHoliday) && opts.Force {
+		slog.Info("⚡ [FORCED] Sending despite skip reason", "standup_id", standupID, "reason", reason)
+		reason = ""
 	}
 
-	// Add tomorrow's facilitator if available
-	if nextFacilitator != nil {
-		message += fmt.Sprintf("📅 *Tomorrow's Facilitator:* %s\n", nextFacilitator.DisplayName)
+	switch reason {
+	case SkipReasonWeekend:
+		slog.Info("⏭️  [SKIPPED] Weekend", "standup_id", standupID, "weekday", now().In(standupLocation(standup)).Weekday().String())
+		recordRun(standupID, RunStatusSkipped, reason, opts.Trigger, nil, detail)
+		return &SkipError{Reason: reason, Detail: detail}
+	case SkipReasonHoliday:
+		slog.Info("⏭️  [SKIPPED] Holiday", "standup_id", standupID, "date", now().In(standupLocation(standup)).Format("2006-01-02"))
+		recordRun(standupID, RunStatusSkipped, reason, opts.Trigger, nil, detail)
+		return &SkipError{Reason: reason, Detail: detail}
+	case SkipReasonInactive:
+		slog.Info("Standup is no longer active", "standup_id", standupID, "standup", standup.Name)
+		return ErrStandupInactive
 	}
 
-	message += fmt.Sprintf("\n%s\n", standup.Message)
+	// A snoozed standup's regular fire is handled by the one-shot snooze job instead
+	if opts.Trigger == RunTriggerScheduled {
+		snoozed, err := HasRunWithStatus(standupID, standupToday(standup), RunStatusSnoozed)
+		if err != nil {
+			slog.Warn("Could not check snooze", "standup_id", standupID, "error", err)
+		} else if snoozed {
+			slog.Info("⏭️  [SKIPPED] Snoozed today", "standup_id", standupID)
+			recordRun(standupID, RunStatusSkipped, SkipReasonSnoozed, opts.Trigger, nil, "regular send replaced by snooze")
+			return &SkipError{Reason: SkipReasonSnoozed, Detail: "regular send replaced by snooze"}
+		}
+	}
 
-	// Add leave information if there are active leaves
-	if len(activeLeaves) > 0 {
-		message += fmt.Sprintf("\n🏖️ *On Leave Today:*\n")
-		for _, leave := range activeLeaves {
-			message += fmt.Sprintf("• %s (%s)\n", leave.User.DisplayName, leave.LeaveType)
+	// Gather facilitators and leaves, and render the message
+	reminder, err := BuildStandupMessage(standupID, opts)
+	if err != nil {
+		slog.Error("Failed to build reminder", "standup_id", standupID, "error", err)
+		return err
+	}
+
+	users := reminder.EligibleUsers
+	if len(users) == 0 {
+		slog.Info("⏭️  [SKIPPED] No eligible users", "standup_id", standupID, "standup", standup.Name)
+		detail := ""
+		if opts.Trigger == RunTriggerScheduled && standup.EmptyRetryMinutes > 0 {
+			detail = scheduleEmptyRetry(standup)
 		}
+		recordRun(standupID, RunStatusSkipped, SkipReasonNoEligibleUsers, opts.Trigger, nil, detail)
+		return &SkipError{Reason: SkipReasonNoEligibleUsers, Detail: "all members are inactive or on leave"}
 	}
 
-	message += fmt.Sprintf("\n_Have a great day!_ ☀️")
+	// A one-person standup is pointless to remind when the standup opts out of it
+	if standup.SkipWhenAlone && len(users) == 1 && !opts.Force {
+		slog.Info("⏭️  [SKIPPED] Only one member is eligible", "standup_id", standupID, "user", users[0].DisplayName)
+		recordRun(standupID, RunStatusSkipped, SkipReasonSingleEligible, opts.Trigger, &users[0], "")
+		return &SkipError{Reason: SkipReasonSingleEligible, Detail: "only 1 eligible user and skip_when_alone is enabled"}
+	}
+
+	currentFacilitator := reminder.CurrentFacilitator
+	nextFacilitator := reminder.NextFacilitator
+	activeLeaves := reminder.ActiveLeaves
+
+	if opts.FacilitatorID != 0 {
+		slog.Info("👤 [OVERRIDE] Facilitator forced", "standup_id", standupID, "facilitator", currentFacilitator.DisplayName)
+	}
+
+	// Record the attempt before sending so an interrupted send still shows up in history
+	runID := startRun(standupID, opts.Trigger, currentFacilitator)
 
-	// Send the message via webhook
+	// Send the message via webhook, waiting for a free slot when many standups fire together
+	release := acquireSendSlot()
 	sendTime := time.Now()
-	err = integrations.SendSimpleMessage(config.Config.WebhookURL, message)
+	err = sendReminderMessage(context.Background(), reminder)
+	release()
 	if err != nil {
-		log.Printf("❌ [SEND FAILED] Failed to send reminder for standup %d (%s): %v", standupID, standup.Name, err)
-		return
+		slog.Error("❌ [SEND FAILED] Failed to send reminder", "standup_id", standupID, "standup", standup.Name, "error", err)
+		metrics.RemindersFailed.WithLabelValues(strconv.Itoa(standupID)).Inc()
+		finishRun(runID, standupID, RunStatusFailed, opts.Trigger, currentFacilitator, err.Error())
+		notifySendFailure(standup, err)
+		return fmt.Errorf("%w: %v", ErrSendFailed, err)
+	}
+
+	finishRun(runID, standupID, RunStatusSent, opts.Trigger, currentFacilitator, "")
+	metrics.RemindersSent.Inc()
+
+	// Today's reminder is out, so a pending snooze must not send it again
+	if opts.Trigger != RunTriggerSnooze {
+		if err := cancelSnooze(standupID); err != nil {
+			slog.Warn("Failed to cancel snooze", "standup_id", standupID, "error", err)
+		}
 	}
 
 	// Log successful send with details
@@ -186,81 +445,216 @@ func SendStandupReminder(standupID int) {
 	if currentFacilitator != nil {
 		facilitatorInfo = currentFacilitator.DisplayName
 	}
-	log.Printf("✅ [MESSAGE SENT] Standup: '%s' (ID: %d) | Scheduled time: %s | Sent at: %s | Facilitator: %s | Eligible users: %d | On leave: %d",
-		standup.Name,
-		standupID,
-		standup.RunAt,
-		sendTime.Format("15:04:05"),
-		facilitatorInfo,
-		len(users),
-		len(activeLeaves),
+	slog.Info("✅ [MESSAGE SENT]",
+		"standup_id", standupID,
+		"standup", standup.Name,
+		"run_at", standup.RunAt,
+		"sent_at", sendTime.Format("15:04:05"),
+		"facilitator", facilitatorInfo,
+		"eligible_users", len(users),
+		"on_leave", len(activeLeaves),
 	)
 
+	// A nomination lasts one rotating send, whether or not the nominee was eligible for it
+	if !opts.SkipRotation && standup.NominatedFacilitatorID != nil {
+		if err := clearNomination(standupID); err != nil {
+			slog.Warn("Failed to clear nominated facilitator", "standup_id", standupID, "error", err)
+		}
+	}
+
 	// Update last_facilitator_id to current facilitator for next rotation
-	if currentFacilitator != nil {
-		err = RotateFacilitator(standupID, currentFacilitator.ID)
+	if opts.SkipRotation {
+		slog.Info("⏸️  [ROTATION SKIPPED] Last facilitator left unchanged", "standup_id", standupID)
+	} else if reminder.Nominated {
+		// The nominee's turn leaves the rotation where it was
+		recordFacilitation(standupID, currentFacilitator.ID)
+		slog.Info("🔄 [ROTATION] Nominee facilitated, rotation unchanged", "standup_id", standupID, "facilitator", currentFacilitator.DisplayName)
+	} else if reminder.RotationSlot != nil {
+		// Rotate from whose turn it was, so a substitute does not shift the normal order
+		err = RotateFacilitator(standupID, reminder.RotationSlot.ID)
 		if err != nil {
-			log.Printf("⚠️  [WARNING] Failed to update last facilitator for standup %d: %v", standupID, err)
+			slog.Warn("Failed to update last facilitator", "standup_id", standupID, "error", err)
 		} else {
+			// History records who actually facilitated, which is the substitute when there is one
+			if currentFacilitator != nil {
+				recordFacilitation(standupID, currentFacilitator.ID)
+			}
+
 			nextName := "unknown"
 			if nextFacilitator != nil {
 				nextName = nextFacilitator.DisplayName
 			}
-			log.Printf("🔄 [ROTATION] Last facilitator set to: %s | Next facilitator will be: %s", currentFacilitator.DisplayName, nextName)
+			slog.Info("🔄 [ROTATION] Last facilitator updated", "standup_id", standupID, "last_facilitator", reminder.RotationSlot.DisplayName, "next_facilitator", nextName)
 		}
 	}
 
 	// Log completion time
 	duration := time.Since(startTime)
-	log.Printf("✨ [COMPLETED] Standup reminder job completed in %v", duration)
+	slog.Debug("✨ [COMPLETED] Standup reminder job completed", "standup_id", standupID, "duration", duration)
+	return nil
+}
+
+// SendManualStandupReminder sends a standup reminder now (for testing or covering irregular days)
+// and waits for the outcome, so callers can report skips and webhook failures
+func SendManualStandupReminder(standupID int, opts ReminderOptions) error {
+	if opts.FacilitatorID != 0 {
+		if err := validateFacilitatorOverride(standupID, opts.FacilitatorID); err != nil {
+			return err
+		}
+	}
+
+	opts.Trigger = RunTriggerManual
+
+	// An explicit rotate in the request wins; otherwise the standup decides (best effort, default rotate)
+	if opts.Rotate != nil {
+		opts.SkipRotation = !*opts.Rotate
+	} else if standup, err := GetStandupByID(standupID); err == nil {
+		opts.SkipRotation = !standup.ManualSendRotates
+	}
+
+	slog.Info("🚀 [MANUAL TRIGGER] Manually triggering standup reminder", "standup_id", standupID)
+	return sendStandupReminder(standupID, opts)
+}
+
+// ForceSendStandupReminder sends a standup's reminder right now even if today would normally
+// be skipped (weekend, single eligible member) and waits for the outcome. The run is recorded
+// with the forced trigger.
+func ForceSendStandupReminder(standupID int) error {
+	standup, err := GetStandupByID(standupID)
+	if err != nil {
+		return err
+	}
+	if !standup.IsActive {
+		return ErrStandupInactive
+	}
+
+	slog.Info("⚡ [FORCE TRIGGER] Force-sending standup reminder", "standup_id", standupID)
+	return sendStandupReminder(standupID, ReminderOptions{Trigger: RunTriggerForced, Force: true})
 }
 
-// SendManualStandupReminder manually triggers a standup reminder (for testing)
-func SendManualStandupReminder(standupID int) error {
-	log.Printf("🚀 [MANUAL TRIGGER] Manually triggering standup reminder for ID: %d at %s", standupID, time.Now().Format("2006-01-02 15:04:05"))
-	go SendStandupReminder(standupID)
+// validateFacilitatorOverride checks that a forced facilitator is a member of the standup and eligible today
+func validateFacilitatorOverride(standupID, userID int) error {
+	isMember, err := IsStandupMember(standupID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return ErrNotStandupMember
+	}
+
+	users, err := database.GetEligibleUsersForStandup(standupID, StandupToday(standupID))
+	if err != nil {
+		return fmt.Errorf("failed to get eligible users: %w", err)
+	}
+	if findUser(users, userID) == nil {
+		return ErrUserNotEligible
+	}
+
+	return nil
+}
+
+// findUser returns the user with the given ID from a list, or nil if absent
+func findUser(users []database.User, userID int) *database.User {
+	for i := range users {
+		if users[i].ID == userID {
+			return &users[i]
+		}
+	}
 	return nil
 }
 
 // ExpireLeaves marks leaves as completed if their end date has passed
 func ExpireLeaves() {
-	log.Println("Running leave expiration job...")
+	slog.Debug("Running leave expiration job")
 
-	err := database.ExpireOldLeaves()
+	err := database.ExpireOldLeaves(Today())
 	if err != nil {
-		log.Printf("Error expiring leaves: %v", err)
+		slog.Error("Failed to expire leaves", "error", err)
 		return
 	}
 
-	log.Println("Leave expiration check completed")
+	slog.Debug("Leave expiration check completed")
 }
 
-// RefreshScheduler stops and restarts the scheduler (useful after creating/updating standups)
+// PurgeExpiredLeaves deletes completed/cancelled leaves older than the configured retention period
+func PurgeExpiredLeaves() {
+	cutoff := now().In(config.Config.Location()).AddDate(0, 0, -config.Config.LeaveRetentionDays)
+	slog.Debug("Running leave purge job", "cutoff", cutoff.Format("2006-01-02"))
+
+	removed, err := PurgeLeaves(cutoff, PurgeableLeaveStatuses)
+	if err != nil {
+		slog.Error("Failed to purge leaves", "error", err)
+		return
+	}
+
+	slog.Info("Leave purge completed", "removed", removed)
+}
+
+// PurgeExpiredIdempotencyKeys deletes idempotency keys older than IDEMPOTENCY_KEY_TTL_HOURS
+func PurgeExpiredIdempotencyKeys() {
+	removed, err := PurgeIdempotencyKeys()
+	if err != nil {
+		slog.Error("Failed to purge idempotency keys", "error", err)
+		return
+	}
+
+	slog.Debug("Idempotency key cleanup completed", "removed", removed)
+}
+
+// RefreshScheduler rebuilds every standup and maintenance job. Prefer RescheduleStandup and
+// UnscheduleStandup when a single standup changes. The jobs are swapped inside the running
+// scheduler, new ones added before old ones are removed, so there is never a window in which a
+// due job could be dropped, and one-shot snooze and retry jobs are kept. A fire time falling in
+// the instant both are registered is caught by the same-day guard in sendStandupReminder.
+// If the new jobs cannot be added, the old ones stay.
 func RefreshScheduler() error {
-	log.Println("Refreshing scheduler...")
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
 
-	// Stop current scheduler
-	if cronScheduler != nil {
-		cronScheduler.Stop()
+	if cronScheduler == nil {
+		return fmt.Errorf("scheduler is not running")
 	}
 
-	// Create new scheduler
-	cronScheduler = cron.New()
+	slog.Debug("Refreshing scheduler")
 
-	// Re-add leave expiration
-	_, err := cronScheduler.AddFunc("0 0 * * *", ExpireLeaves)
+	oldStandupEntries := standupEntries
+	oldMaintenanceEntries := maintenanceEntries
+	standupEntries = make(map[int]cron.EntryID)
+	maintenanceEntries = nil
+
+	// Drop whatever was added so far and keep the old jobs
+	rollback := func() {
+		for _, entryID := range standupEntries {
+			cronScheduler.Remove(entryID)
+		}
+		for _, entryID := range maintenanceEntries {
+			cronScheduler.Remove(entryID)
+		}
+		standupEntries, maintenanceEntries = oldStandupEntries, oldMaintenanceEntries
+	}
+
+	// Re-add leave maintenance jobs
+	err := scheduleMaintenanceJobs()
 	if err != nil {
-		return fmt.Errorf("failed to schedule leave expiration: %w", err)
+		rollback()
+		return err
 	}
 
 	// Re-schedule all standups
 	err = ScheduleAllStandups()
 	if err != nil {
+		rollback()
 		return fmt.Errorf("failed to schedule standups: %w", err)
 	}
 
-	// Start scheduler
-	cronScheduler.Start()
-	log.Println("Scheduler refreshed successfully")
+	// The new jobs are in place; retire the old ones
+	for _, entryID := range oldStandupEntries {
+		cronScheduler.Remove(entryID)
+	}
+	for _, entryID := range oldMaintenanceEntries {
+		cronScheduler.Remove(entryID)
+	}
+
+	slog.Info("Scheduler refreshed successfully")
 	return nil
 }
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+)
+
+// startTestScheduler starts the scheduler on the test database and stops it afterwards
+func startTestScheduler(t *testing.T) {
+	t.Helper()
+
+	if err := StartScheduler(); err != nil {
+		t.Fatalf("StartScheduler: %v", err)
+	}
+	t.Cleanup(func() {
+		<-cronScheduler.Stop().Done()
+	})
+}
+
+func TestRefreshSchedulerKeepsJobDueAtTheBoundary(t *testing.T) {
+	setupTestDB(t)
+	alice := mustCreateUser(t, "alice")
+	standup := mustCreateStandup(t, "daily", alice)
+	startTestScheduler(t)
+
+	entriesBefore := len(cronScheduler.Entries())
+
+	// A job due right as the refresh happens must still fire, exactly once
+	fired := make(chan struct{}, 2)
+	schedulerMu.Lock()
+	cronScheduler.Schedule(onceSchedule{at: time.Now().Add(50 * time.Millisecond)}, cron.FuncJob(func() {
+		fired <- struct{}{}
+	}))
+	schedulerMu.Unlock()
+
+	if err := RefreshScheduler(); err != nil {
+		t.Fatalf("RefreshScheduler: %v", err)
+	}
+
+	select {
+	case <-fired:
+	case <-time.After(3 * time.Second):
+		t.Fatal("job due during the refresh never fired")
+	}
+	select {
+	case <-fired:
+		t.Fatal("job due during the refresh fired twice")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// The refresh replaced the recurring jobs rather than adding a second set
+	if got := len(cronScheduler.Entries()); got != entriesBefore+1 {
+		t.Errorf("entries after refresh = %d, want %d (recurring jobs plus the one-shot)", got, entriesBefore+1)
+	}
+	entryID, ok := standupEntries[standup.ID]
+	if !ok {
+		t.Fatal("standup has no job after refresh")
+	}
+	if entry := cronScheduler.Entry(entryID); !entry.Valid() {
+		t.Error("standup job recorded after refresh is not on the scheduler")
+	}
+}
+
+func TestScheduledSendRunsOncePerDay(t *testing.T) {
+	tests := []struct {
+		name       string
+		concurrent bool
+	}{
+		{"sequential duplicate fire", false},
+		{"concurrent duplicate fire", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			webhook := newWebhookRecorder(t)
+			alice := mustCreateUser(t, "alice")
+			standup := mustCreateStandup(t, "daily", alice)
+
+			send := func() error {
+				return sendStandupReminder(standup.ID, ReminderOptions{Trigger: RunTriggerScheduled})
+			}
+
+			var errs [2]error
+			if tt.concurrent {
+				var wg sync.WaitGroup
+				for i := range errs {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						errs[i] = send()
+					}(i)
+				}
+				wg.Wait()
+			} else {
+				errs[0] = send()
+				errs[1] = send()
+			}
+
+			if got := len(webhook.posts()); got != 1 {
+				t.Fatalf("webhook posts = %d, want 1", got)
+			}
+
+			skipped := 0
+			for _, err := range errs {
+				var skipErr *SkipError
+				switch {
+				case errors.As(err, &skipErr) && skipErr.Reason == SkipReasonAlreadyRan:
+					skipped++
+				case err != nil:
+					t.Errorf("send: %v", err)
+				}
+			}
+			if skipped != 1 {
+				t.Errorf("sends skipped as already_ran = %d, want 1", skipped)
+			}
+
+			runs, _, err := GetStandupRuns(standup.ID, 10, "")
+			if err != nil {
+				t.Fatalf("GetStandupRuns: %v", err)
+			}
+			if len(runs) != 1 {
+				t.Errorf("runs recorded = %d, want 1", len(runs))
+			}
+		})
+	}
+}
+
+func TestManualSendDoesNotBlockScheduledSend(t *testing.T) {
+	setupTestDB(t)
+	webhook := newWebhookRecorder(t)
+	alice := mustCreateUser(t, "alice")
+	standup := mustCreateStandup(t, "daily", alice)
+
+	if err := sendStandupReminder(standup.ID, ReminderOptions{Trigger: RunTriggerManual}); err != nil {
+		t.Fatalf("manual send: %v", err)
+	}
+	if err := sendStandupReminder(standup.ID, ReminderOptions{Trigger: RunTriggerScheduled}); err != nil {
+		t.Fatalf("scheduled send: %v", err)
+	}
+
+	if got := len(webhook.posts()); got != 2 {
+		t.Errorf("webhook posts = %d, want 2", got)
+	}
+}
+
+func TestSendSlotsLimitConcurrentSends(t *testing.T) {
+	tests := []struct {
+		concurrency int
+		senders     int
+	}{
+		{1, 4},
+		{2, 6},
+		{4, 8},
+		{4, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d slots, %d senders", tt.concurrency, tt.senders), func(t *testing.T) {
+			setupTestDB(t)
+			config.Config.SendConcurrency = tt.concurrency
+			sendSlots, sendSlotsOnce = nil, sync.Once{}
+			t.Cleanup(func() { sendSlots, sendSlotsOnce = nil, sync.Once{} })
+
+			var mu sync.Mutex
+			inFlight, maxInFlight := 0, 0
+			var wg sync.WaitGroup
+			for i := 0; i < tt.senders; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					release := acquireSendSlot()
+					defer release()
+
+					mu.Lock()
+					inFlight++
+					if inFlight > maxInFlight {
+						maxInFlight = inFlight
+					}
+					mu.Unlock()
+
+					time.Sleep(20 * time.Millisecond)
+
+					mu.Lock()
+					inFlight--
+					mu.Unlock()
+				}()
+			}
+			wg.Wait()
+
+			want := tt.concurrency
+			if tt.senders < want {
+				want = tt.senders
+			}
+			if maxInFlight != want {
+				t.Errorf("at most %d sends ran at once, want %d", maxInFlight, want)
+			}
+		})
+	}
+}
+
+func TestManualSendRotation(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name              string
+		manualSendRotates bool
+		rotate            *bool
+		wantRotated       bool
+	}{
+		{"rotating standup", true, nil, true},
+		{"rotating standup, rotate false", true, &no, false},
+		{"rotating standup, rotate true", true, &yes, true},
+		{"non-rotating standup", false, nil, false},
+		{"non-rotating standup, rotate true", false, &yes, true},
+		{"non-rotating standup, rotate false", false, &no, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			webhook := newWebhookRecorder(t)
+			alice := mustCreateUser(t, "alice")
+			bob := mustCreateUser(t, "bob")
+			standup := mustCreateStandup(t, "daily", alice, bob)
+			if err := SetStandupManualSendRotates(standup.ID, tt.manualSendRotates); err != nil {
+				t.Fatalf("SetStandupManualSendRotates: %v", err)
+			}
+
+			if err := SendManualStandupReminder(standup.ID, ReminderOptions{Rotate: tt.rotate}); err != nil {
+				t.Fatalf("SendManualStandupReminder: %v", err)
+			}
+			if got := len(webhook.posts()); got != 1 {
+				t.Fatalf("%d reminders posted, want 1", got)
+			}
+
+			updated, err := GetStandupByID(standup.ID)
+			if err != nil {
+				t.Fatalf("GetStandupByID: %v", err)
+			}
+			rotated := updated.LastFacilitatorID != nil && *updated.LastFacilitatorID == alice.ID
+			if rotated != tt.wantRotated || !rotated && updated.LastFacilitatorID != nil {
+				t.Errorf("last_facilitator_id = %v, want rotated to alice: %v", updated.LastFacilitatorID, tt.wantRotated)
+			}
+
+			wantNext := "alice"
+			if tt.wantRotated {
+				wantNext = "bob"
+			}
+			if got := currentSlotName(t, standup.ID, alice, bob); got != wantNext {
+				t.Errorf("facilitator after the send = %s, want %s", got, wantNext)
+			}
+		})
+	}
+}
+
+func TestWaitForSendsLetsRunsFinalize(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantStatus string
+	}{
+		{"webhook accepts", http.StatusOK, RunStatusSent},
+		{"webhook fails", http.StatusInternalServerError, RunStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			alice := mustCreateUser(t, "alice")
+			standup := mustCreateStandup(t, "daily", alice)
+
+			received, release := make(chan struct{}), make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(received)
+				<-release
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte("{}"))
+			}))
+			t.Cleanup(server.Close)
+			config.Config.WebhookURL = server.URL + "/webhook"
+
+			go SendManualStandupReminder(standup.ID, ReminderOptions{})
+			<-received
+
+			runStatus := func() string {
+				var status string
+				if err := database.DB.QueryRow("SELECT status FROM standup_runs WHERE standup_id = ?", standup.ID).Scan(&status); err != nil {
+					t.Fatalf("read run: %v", err)
+				}
+				return status
+			}
+
+			// Mid-send, the run is already recorded and shutdown has to wait for it
+			if got := runStatus(); got != RunStatusAttempting {
+				t.Errorf("run status during the send = %s, want %s", got, RunStatusAttempting)
+			}
+			if WaitForSends(50 * time.Millisecond) {
+				t.Error("WaitForSends returned true while a send was still running")
+			}
+
+			close(release)
+			if !WaitForSends(3 * time.Second) {
+				t.Fatal("WaitForSends timed out after the send finished")
+			}
+			if got := runStatus(); got != tt.wantStatus {
+				t.Errorf("run status after the send = %s, want %s", got, tt.wantStatus)
+			}
+		})
+	}
+}
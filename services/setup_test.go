@@ -0,0 +1,139 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+)
+
+var testDBCount atomic.Int64
+
+// setupTestDB points the services at a fresh in-memory database with every migration applied
+// and a default configuration (UTC, weekends not skipped). The clock is restored afterwards.
+func setupTestDB(t *testing.T) {
+	t.Helper()
+
+	config.Config = &config.AppConfig{
+		Timezone:               "UTC",
+		DisplayField:           "display_name",
+		SendConcurrency:        2,
+		HistoryPageSize:        20,
+		CardFormat:             "cardsV2",
+		MaxBodyBytes:           1 << 20,
+		IdempotencyKeyTTLHours: 24,
+	}
+
+	// A named shared-cache database lives as long as a connection to it is open, and every
+	// connection in the pool sees the same data
+	dsn := fmt.Sprintf("file:services_test_%d?mode=memory&cache=shared", testDBCount.Add(1))
+	if err := database.InitDB(database.DriverSQLite, dsn); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+
+	originalNow := now
+	t.Cleanup(func() {
+		now = originalNow
+		database.CloseDB()
+	})
+}
+
+// setNow pins the services clock to the given time
+func setNow(t time.Time) {
+	now = func() time.Time { return t }
+}
+
+// mustCreateUser adds an active user to the roster
+func mustCreateUser(t *testing.T, name string) *database.User {
+	t.Helper()
+
+	user, err := CreateUser("users/"+name, name, "")
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", name, err)
+	}
+	return user
+}
+
+// mustCreateStandup adds a standup with the given members, in order
+func mustCreateStandup(t *testing.T, name string, members ...*database.User) *database.Standup {
+	t.Helper()
+
+	standup, err := CreateStandup(name, "Standup time!", "09:00", "", "test")
+	if err != nil {
+		t.Fatalf("CreateStandup(%s): %v", name, err)
+	}
+
+	ids := make([]int, len(members))
+	for i, member := range members {
+		ids[i] = member.ID
+	}
+	if err := SetStandupMembers(standup.ID, ids); err != nil {
+		t.Fatalf("SetStandupMembers: %v", err)
+	}
+	return standup
+}
+
+// mustCreateApprovedLeave adds an active leave covering start..end (YYYY-MM-DD)
+func mustCreateApprovedLeave(t *testing.T, user *database.User, leaveType, start, end string) *database.Leave {
+	t.Helper()
+
+	startDate, _ := time.Parse("2006-01-02", start)
+	endDate, _ := time.Parse("2006-01-02", end)
+	leave, err := CreateLeave(user.ID, leaveType, startDate, endDate, "", "")
+	if err != nil {
+		t.Fatalf("CreateLeave: %v", err)
+	}
+	if err := ApproveLeave(leave.ID, "test"); err != nil {
+		t.Fatalf("ApproveLeave: %v", err)
+	}
+	return leave
+}
+
+// webhookRecorder is a fake Google Chat webhook that keeps the bodies posted to it
+type webhookRecorder struct {
+	mu     sync.Mutex
+	bodies []string
+	urls   []string
+}
+
+// newWebhookRecorder starts a fake webhook and makes it the configured GOOGLE_CHAT_WEBHOOK_URL
+func newWebhookRecorder(t *testing.T) *webhookRecorder {
+	t.Helper()
+
+	recorder := &webhookRecorder{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		recorder.mu.Lock()
+		recorder.bodies = append(recorder.bodies, string(body))
+		recorder.urls = append(recorder.urls, r.URL.String())
+		recorder.mu.Unlock()
+
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(server.Close)
+
+	config.Config.WebhookURL = server.URL + "/webhook"
+	return recorder
+}
+
+// posts returns the bodies posted so far
+func (r *webhookRecorder) posts() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.bodies...)
+}
+
+// requestURLs returns the URL (path and query) of each post so far
+func (r *webhookRecorder) requestURLs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.urls...)
+}
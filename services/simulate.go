@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"google-chat-bot/config"
+	"google-chat-bot/database"
+)
+
+// MaxSimulationDays caps how far ahead SimulateStandup projects
+const MaxSimulationDays = 31
+
+// SimulatedDay is the projected outcome of one day's reminder
+type SimulatedDay struct {
+	Date       string     `json:"date"`
+	Skipped    bool       `json:"skipped"`
+	SkipReason SkipReason `json:"skip_reason,omitempty"`
+	Message    string     `json:"message,omitempty"`
+}
+
+// SimulateStandup renders the reminders a standup would post over the next days, using each
+// day's eligibility, leaves and substitutions and advancing the rotation after every projected
+// send. Today is included only while its run_at is still ahead. Nothing is stored or sent.
+func SimulateStandup(standupID, days int) ([]SimulatedDay, error) {
+	standup, err := GetStandupByID(standupID)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, _ := GetStandupMemberAliases(standupID)
+
+	loc := standupLocation(standup)
+	start := now().In(loc)
+	if start.Format("15:04") >= standup.RunAt {
+		start = start.AddDate(0, 0, 1)
+	}
+
+	// The projected rotation, advanced after every projected send
+	rotation, err := loadRotation(standupID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SimulatedDay, 0, days)
+	for i := 0; i < days; i++ {
+		day := start.AddDate(0, 0, i)
+		date := day.Format("2006-01-02")
+
+		if reason := simulatedSkipReason(standup, day); reason != "" {
+			result = append(result, SimulatedDay{Date: date, Skipped: true, SkipReason: reason})
+			continue
+		}
+
+		users, err := database.GetEligibleUsersForStandup(standupID, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get eligible users: %w", err)
+		}
+		if len(users) == 0 {
+			result = append(result, SimulatedDay{Date: date, Skipped: true, SkipReason: SkipReasonNoEligibleUsers})
+			continue
+		}
+		if standup.SkipWhenAlone && len(users) == 1 {
+			result = append(result, SimulatedDay{Date: date, Skipped: true, SkipReason: SkipReasonSingleEligible})
+			continue
+		}
+
+		reminder := &StandupReminder{
+			Standup:       standup,
+			Day:           day,
+			EligibleUsers: users,
+			Aliases:       aliases,
+		}
+
+		// A pending nomination takes the first projected send, without moving the rotation
+		if nominee := nominatedFacilitator(standup, users); nominee != nil {
+			reminder.CurrentFacilitator = nominee
+		} else {
+			reminder.RotationSlot, reminder.CurrentFacilitator, err = projectedFacilitator(standupID, rotation, users, date)
+			if err != nil {
+				return nil, err
+			}
+			rotation.advance(reminder.RotationSlot.ID)
+		}
+		standup.NominatedFacilitatorID = nil
+
+		// Tomorrow's line is projected with the next calendar day's substitutions, as on a real send
+		tomorrow := day.AddDate(0, 0, 1).Format("2006-01-02")
+		_, reminder.NextFacilitator, _ = projectedFacilitator(standupID, rotation, users, tomorrow)
+
+		reminder.ActiveLeaves, _ = database.GetActiveLeavesForStandup(standupID, date)
+		if standup.ShowReturning {
+			reminder.ReturningLeaves, _ = database.GetLeavesEndingSoon(standupID, date)
+		}
+
+		// A broken template shows up as the built-in format, as it would be posted
+		message, _ := renderStandupMessage(reminder)
+		result = append(result, SimulatedDay{Date: date, Message: message})
+	}
+
+	return result, nil
+}
+
+// simulatedSkipReason reports why a standup would not send on day regardless of who is eligible
+func simulatedSkipReason(standup *database.Standup, day time.Time) SkipReason {
+	if !standup.IsActive {
+		return SkipReasonInactive
+	}
+
+	if standupWebhookURL(standup) == "" {
+		return SkipReasonNoWebhook
+	}
+
+	if config.Config.SkipWeekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+		return SkipReasonWeekend
+	}
+
+	// Best effort: a failed lookup projects the day as a regular one
+	if holiday, _ := HolidayOn(day.Format("2006-01-02")); holiday != nil {
+		return SkipReasonHoliday
+	}
+
+	if !runsOnWeekday(standup, day.Weekday()) {
+		return SkipReasonNotScheduled
+	}
+
+	return ""
+}
+
+// projectedFacilitator returns the rotation slot and facilitator on day for the projected rotation
+func projectedFacilitator(standupID int, rotation *facilitatorRotation, eligibleUsers []database.User, day string) (slot, facilitator *database.User, err error) {
+	candidates, substitutes, err := rotationCandidates(standupID, eligibleUsers, day)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slot = rotation.slot(candidates)
+	if substitute, ok := substitutes[slot.ID]; ok {
+		return slot, substitute, nil
+	}
+	return slot, slot, nil
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google-chat-bot/database"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	// ErrAlreadySentToday is returned when snoozing a standup whose reminder has already gone out today
+	ErrAlreadySentToday = errors.New("today's reminder has already been sent")
+	// ErrSnoozeCrossesDay is returned when a snooze would push the reminder into tomorrow
+	ErrSnoozeCrossesDay = errors.New("snooze must keep the reminder within the same day")
+)
+
+// onceSchedule is a cron.Schedule that fires a single time
+type onceSchedule struct {
+	at time.Time
+}
+
+// Next returns the one-shot time until it has passed, then the zero time (never again)
+func (s onceSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.at) {
+		return s.at
+	}
+	return time.Time{}
+}
+
+// SnoozeStandup postpones today's reminder by the given number of minutes.
+// The regular scheduled fire for today is skipped and a one-shot send is registered instead.
+// The send time is stored in standups.snoozed_until, so the snooze survives a restart.
+func SnoozeStandup(standupID, minutes int) (time.Time, error) {
+	if minutes <= 0 {
+		return time.Time{}, fmt.Errorf("minutes must be positive")
+	}
+
+	standup, err := GetStandupByID(standupID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !standup.IsActive {
+		return time.Time{}, ErrStandupInactive
+	}
+
+	today := standupToday(standup)
+	sent, err := HasRunWithStatus(standupID, today, RunStatusSent)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if sent {
+		return time.Time{}, ErrAlreadySentToday
+	}
+
+	at := now().In(standupLocation(standup)).Add(time.Duration(minutes) * time.Minute)
+	if at.Format("2006-01-02") != today {
+		return time.Time{}, ErrSnoozeCrossesDay
+	}
+
+	// Hold the scheduler lock so a concurrent refresh can't swap schedulers between
+	// recording the snooze and registering its job
+	schedulerMu.Lock()
+	_, err = database.DB.Exec(
+		"UPDATE standups SET snoozed_until = ? WHERE id = ?",
+		at.UTC().Format(time.RFC3339Nano), standupID,
+	)
+	if err != nil {
+		schedulerMu.Unlock()
+		return time.Time{}, fmt.Errorf("failed to store snooze: %w", err)
+	}
+	scheduleSnooze(standupID, at)
+	schedulerMu.Unlock()
+
+	recordRun(standupID, RunStatusSnoozed, "", RunTriggerManual, nil, fmt.Sprintf("snoozed %d minute(s) until %s", minutes, at.Format("15:04")))
+	slog.Info("😴 [SNOOZED] Standup snoozed", "standup_id", standupID, "standup", standup.Name, "until", at.Format("15:04"))
+
+	return at, nil
+}
+
+// scheduleSnooze registers the one-shot send job for a snoozed standup
+func scheduleSnooze(standupID int, at time.Time) {
+	cronScheduler.Schedule(onceSchedule{at: at}, cron.FuncJob(func() {
+		// Claim the snooze; a newer snooze replaced it, or a send since cancelled it, when it no
+		// longer matches, and then there is nothing to send
+		claimed, err := clearSnooze(standupID, at)
+		if err != nil {
+			slog.Error("Failed to claim snooze", "standup_id", standupID, "error", err)
+			return
+		}
+		if !claimed {
+			return
+		}
+
+		_ = sendStandupReminder(standupID, ReminderOptions{Trigger: RunTriggerSnooze})
+	}))
+}
+
+// clearSnooze removes the stored snooze at the given time and reports whether it was still there
+func clearSnooze(standupID int, at time.Time) (bool, error) {
+	result, err := database.DB.Exec(
+		"UPDATE standups SET snoozed_until = '' WHERE id = ? AND snoozed_until = ?",
+		standupID, at.UTC().Format(time.RFC3339Nano),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	cleared, _ := result.RowsAffected()
+	return cleared > 0, nil
+}
+
+// cancelSnooze drops a standup's pending snooze, if any, so its one-shot job sends nothing
+func cancelSnooze(standupID int) error {
+	_, err := database.DB.Exec("UPDATE standups SET snoozed_until = '' WHERE id = ? AND snoozed_until != ''", standupID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel snooze: %w", err)
+	}
+	return nil
+}
+
+// schedulePendingSnoozes registers the stored snoozes that have not fired yet, e.g. after a
+// restart, and drops those whose time passed while the bot was down
+func schedulePendingSnoozes() error {
+	rows, err := database.DB.Query("SELECT id, snoozed_until FROM standups WHERE snoozed_until != ''")
+	if err != nil {
+		return fmt.Errorf("failed to get pending snoozes: %w", err)
+	}
+
+	pending := make(map[int]string)
+	for rows.Next() {
+		var standupID int
+		var until string
+		if err := rows.Scan(&standupID, &until); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan pending snooze: %w", err)
+		}
+		pending[standupID] = until
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get pending snoozes: %w", err)
+	}
+
+	current := now()
+	for standupID, until := range pending {
+		at, err := time.Parse(time.RFC3339Nano, until)
+		if err != nil || at.Before(current) {
+			if err := cancelSnooze(standupID); err != nil {
+				return err
+			}
+			slog.Warn("Dropped a snooze that was already due", "standup_id", standupID, "until", until)
+			continue
+		}
+		scheduleSnooze(standupID, at)
+	}
+
+	return nil
+}
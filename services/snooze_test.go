@@ -0,0 +1,127 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"google-chat-bot/database"
+)
+
+// snoozeJobs returns the one-shot snooze entries on the running scheduler
+func snoozeJobs() []func() {
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
+
+	var jobs []func()
+	for _, entry := range cronScheduler.Entries() {
+		if _, ok := entry.Schedule.(onceSchedule); ok {
+			jobs = append(jobs, entry.Job.Run)
+		}
+	}
+	return jobs
+}
+
+// storedSnooze returns a standup's snoozed_until column
+func storedSnooze(t *testing.T, standupID int) string {
+	t.Helper()
+
+	var until string
+	if err := database.DB.QueryRow("SELECT snoozed_until FROM standups WHERE id = ?", standupID).Scan(&until); err != nil {
+		t.Fatalf("read snoozed_until: %v", err)
+	}
+	return until
+}
+
+func TestSnoozeSendsOnce(t *testing.T) {
+	tests := []struct {
+		name       string
+		sendFirst  ReminderOptions // a send before the snooze fires, if Trigger is set
+		wantPosts  int
+		wantSnooze bool // whether the snooze is still stored before it fires
+	}{
+		{"snooze fires", ReminderOptions{}, 1, true},
+		{"manual send cancels the snooze", ReminderOptions{Trigger: RunTriggerManual}, 1, false},
+		{"forced send cancels the snooze", ReminderOptions{Trigger: RunTriggerForced, Force: true}, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			webhook := newWebhookRecorder(t)
+			alice := mustCreateUser(t, "alice")
+			standup := mustCreateStandup(t, "daily", alice)
+			setNow(time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC))
+			startTestScheduler(t)
+
+			if _, err := SnoozeStandup(standup.ID, 30); err != nil {
+				t.Fatalf("SnoozeStandup: %v", err)
+			}
+			if tt.sendFirst.Trigger != "" {
+				if err := sendStandupReminder(standup.ID, tt.sendFirst); err != nil {
+					t.Fatalf("send before the snooze: %v", err)
+				}
+			}
+
+			if got := storedSnooze(t, standup.ID) != ""; got != tt.wantSnooze {
+				t.Errorf("snooze stored = %v, want %v", got, tt.wantSnooze)
+			}
+
+			jobs := snoozeJobs()
+			if len(jobs) != 1 {
+				t.Fatalf("snooze jobs = %d, want 1", len(jobs))
+			}
+			jobs[0]()
+			// A duplicate fire finds the snooze already claimed
+			jobs[0]()
+
+			if got := len(webhook.posts()); got != tt.wantPosts {
+				t.Errorf("webhook posts = %d, want %d", got, tt.wantPosts)
+			}
+			if got := storedSnooze(t, standup.ID); got != "" {
+				t.Errorf("snoozed_until after firing = %q, want empty", got)
+			}
+		})
+	}
+}
+
+func TestSnoozeSurvivesRestart(t *testing.T) {
+	tests := []struct {
+		name     string
+		restart  time.Time
+		wantJobs int
+	}{
+		{"restart before the snooze is due", time.Date(2026, 3, 10, 8, 10, 0, 0, time.UTC), 1},
+		{"restart after the snooze was due", time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			alice := mustCreateUser(t, "alice")
+			standup := mustCreateStandup(t, "daily", alice)
+			setNow(time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC))
+			startTestScheduler(t)
+
+			at, err := SnoozeStandup(standup.ID, 30)
+			if err != nil {
+				t.Fatalf("SnoozeStandup: %v", err)
+			}
+
+			<-cronScheduler.Stop().Done()
+			setNow(tt.restart)
+			startTestScheduler(t)
+
+			if got := len(snoozeJobs()); got != tt.wantJobs {
+				t.Errorf("snooze jobs after restart = %d, want %d", got, tt.wantJobs)
+			}
+
+			want := ""
+			if tt.wantJobs > 0 {
+				want = at.UTC().Format(time.RFC3339Nano)
+			}
+			if got := storedSnooze(t, standup.ID); got != want {
+				t.Errorf("snoozed_until after restart = %q, want %q", got, want)
+			}
+		})
+	}
+}
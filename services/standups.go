@@ -2,56 +2,261 @@ package services
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 
+	"google-chat-bot/config"
 	"google-chat-bot/database"
 )
 
 // CreateStandup creates a new standup meeting
-func CreateStandup(name, message, runAt, createdBy string) (*database.Standup, error) {
+func CreateStandup(name, message, runAt, timezone, createdBy string) (*database.Standup, error) {
+	return CreateStandupWithSettings(name, message, runAt, timezone, createdBy, StandupSettings{})
+}
+
+// StandupSettings are the optional settings of a new standup. Zero values keep the column
+// defaults, except ManualSendRotates where nil keeps the default (true).
+type StandupSettings struct {
+	Members            []int                          // user IDs, in rotation order
+	MemberSettings     map[int]database.StandupMember // alias and can_facilitate by user ID; others keep the defaults
+	WebhookURL         string
+	WebhookURLs        []string
+	Membership         string
+	SkipWhenAlone      bool
+	ShowReturning      bool
+	FullTeamMessage    string
+	ManualSendRotates  *bool
+	FacilitatorOnly    bool
+	DaysOfWeek         string
+	OwnerUserID        int
+	DailyThread        bool
+	EmptyRetryMinutes  int
+	MessageFormat      string
+	Template           string
+	MentionFacilitator bool
+	ThreadKey          string
+}
+
+// CreateStandupWithSettings creates a new standup together with its members and settings.
+// Everything is validated first and written in one transaction, so a failure creates nothing.
+func CreateStandupWithSettings(name, message, runAt, timezone, createdBy string, settings StandupSettings) (*database.Standup, error) {
+	if err := validateTimezone(timezone); err != nil {
+		return nil, err
+	}
+
+	membership := settings.Membership
+	if membership == "" {
+		membership = database.MembershipExplicit
+	}
+	if membership != database.MembershipExplicit && membership != database.MembershipAllActive {
+		return nil, ErrInvalidMembership
+	}
+
+	daysOfWeek, err := NormalizeDaysOfWeek(settings.DaysOfWeek)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateStandupOwner(settings.OwnerUserID); err != nil {
+		return nil, err
+	}
+	var owner interface{}
+	if settings.OwnerUserID != 0 {
+		owner = settings.OwnerUserID
+	}
+
+	if settings.EmptyRetryMinutes < 0 {
+		return nil, ErrInvalidEmptyRetry
+	}
+
+	messageFormat := settings.MessageFormat
+	if messageFormat == "" {
+		messageFormat = database.MessageFormatText
+	}
+	if !ValidMessageFormat(messageFormat) {
+		return nil, ErrInvalidMessageFormat
+	}
+
+	if err := ValidateReminderTemplate(settings.Template); err != nil {
+		return nil, err
+	}
+
+	webhookURLs, err := encodeWebhookURLs(settings.WebhookURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	manualSendRotates := true
+	if settings.ManualSendRotates != nil {
+		manualSendRotates = *settings.ManualSendRotates
+	}
+
+	if err := checkActiveStandupLimit(0); err != nil {
+		return nil, err
+	}
+
+	runAt = normalizeRunAt(runAt)
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO standups (name, message, run_at, created_by, is_active)
-		VALUES (?, ?, ?, ?, 1)
+		INSERT INTO standups (
+			name, message, run_at, timezone, created_by, is_active,
+			webhook_url, webhook_urls, membership, skip_when_alone, show_returning,
+			full_team_message, manual_send_rotates, facilitator_only, days_of_week,
+			owner_user_id, daily_thread, empty_retry_minutes, message_format, template,
+			mention_facilitator, thread_key
+		)
+		VALUES (?, ?, ?, ?, ?, TRUE, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
 	`
 
-	result, err := database.DB.Exec(query, name, message, runAt, createdBy)
+	var id int
+	err = tx.QueryRow(query, name, message, runAt, timezone, createdBy,
+		settings.WebhookURL, webhookURLs, membership, settings.SkipWhenAlone, settings.ShowReturning,
+		settings.FullTeamMessage, manualSendRotates, settings.FacilitatorOnly, daysOfWeek,
+		owner, settings.DailyThread, settings.EmptyRetryMinutes, messageFormat, settings.Template,
+		settings.MentionFacilitator, settings.ThreadKey).Scan(&id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create standup: %w", err)
 	}
 
-	id, err := result.LastInsertId()
+	stmt, err := tx.Prepare("INSERT INTO standup_members (standup_id, user_id, display_order, alias, can_facilitate) VALUES (?, ?, ?, ?, ?)")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+		return nil, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, userID := range settings.Members {
+		canFacilitate := true
+		if member, ok := settings.MemberSettings[userID]; ok {
+			canFacilitate = member.CanFacilitate
+		}
+
+		_, err = stmt.Exec(id, userID, i, settings.MemberSettings[userID].Alias, canFacilitate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert member %d: %w", userID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return GetStandupByID(int(id))
 }
 
-// GetStandupByID retrieves a standup by ID
-func GetStandupByID(id int) (*database.Standup, error) {
-	query := `
-		SELECT id, name, message, run_at, is_active, last_facilitator_id, created_by, created_at, updated_at
-		FROM standups
-		WHERE id = ?
-	`
+var (
+	// ErrNotStandupMember is returned when a user is not assigned to the standup
+	ErrNotStandupMember = errors.New("user is not a member of this standup")
+	// ErrUserNotEligible is returned when a member is inactive or on leave today
+	ErrUserNotEligible = errors.New("user is not eligible today (inactive or on leave)")
+	// ErrStandupLimitReached is returned when MAX_ACTIVE_STANDUPS active standups already exist
+	ErrStandupLimitReached = errors.New("maximum number of active standups reached")
+	// ErrStandupInactive is returned when an action needs an active standup
+	ErrStandupInactive = errors.New("standup is not active")
+	// ErrDynamicMembership is returned when reordering members of an all_active standup
+	ErrDynamicMembership = errors.New("members of an all_active standup are ordered by name and cannot be reordered")
+	// ErrInvalidMembership is returned for a membership mode other than explicit or all_active
+	ErrInvalidMembership = errors.New("membership must be 'explicit' or 'all_active'")
+	// ErrInvalidTimezone is returned when a standup timezone is not a known IANA zone name
+	ErrInvalidTimezone = errors.New("timezone must be an IANA zone name such as Europe/Berlin")
+	// ErrInvalidDaysOfWeek is returned when days_of_week is not a list of weekdays
+	ErrInvalidDaysOfWeek = errors.New("days_of_week must be comma-separated days as 0-6 (0 = Sunday) or SUN-SAT, e.g. MON,WED,FRI")
+	// ErrInvalidTimeRange is returned when a run_at range bound is not a valid HH:MM time
+	ErrInvalidTimeRange = errors.New("from_time and to_time must both be valid HH:MM times")
+	// ErrInvalidEmptyRetry is returned when empty_retry_minutes is negative
+	ErrInvalidEmptyRetry = errors.New("empty_retry_minutes must not be negative")
+	// ErrInvalidOwner is returned when a standup owner is not an existing user
+	ErrInvalidOwner = errors.New("owner_user_id must be an existing user")
+	// ErrInvalidMessageFormat is returned for a message format other than text, card or status_card
+	ErrInvalidMessageFormat = errors.New("message_format must be 'text', 'card' or 'status_card'")
+	// ErrInvalidRunAt is returned when run_at is not a valid 24-hour time
+	ErrInvalidRunAt = errors.New("run_at must be in HH:MM 24-hour format")
+	// ErrAlreadyStandupMember is returned when adding a user who is already assigned to the standup
+	ErrAlreadyStandupMember = errors.New("user is already a member of this standup")
+	// ErrMemberOrderMismatch is returned when a new member order does not list every current member exactly once
+	ErrMemberOrderMismatch = errors.New("user_ids must list every current member exactly once")
+	// ErrNoEligibleUsers is returned when an action needs someone eligible today and nobody is
+	ErrNoEligibleUsers = errors.New("no eligible users for standup")
+	// ErrCannotFacilitate is returned when nominating a member whose can_facilitate is off
+	ErrCannotFacilitate = errors.New("user does not take facilitation turns in this standup (can_facilitate is off)")
+)
+
+// checkActiveStandupLimit returns ErrStandupLimitReached if activating one more standup
+// would exceed MAX_ACTIVE_STANDUPS. excludeID is not counted (0 when creating).
+func checkActiveStandupLimit(excludeID int) error {
+	limit := config.Config.MaxActiveStandups
+	if limit <= 0 {
+		return nil
+	}
+
+	var count int
+	err := database.DB.QueryRow("SELECT COUNT(*) FROM standups WHERE is_active = TRUE AND id != ?", excludeID).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to count active standups: %w", err)
+	}
 
+	if count >= limit {
+		return ErrStandupLimitReached
+	}
+	return nil
+}
+
+// standupColumns is the column list shared by all standup queries, in scanStandup order
+const standupColumns = `id, name, message, run_at, is_active, last_facilitator_id, webhook_url, membership, skip_when_alone, show_returning, full_team_message, manual_send_rotates, timezone, facilitator_only, days_of_week, owner_user_id, daily_thread, empty_retry_minutes, message_format, template, webhook_urls, mention_facilitator, thread_key, nominated_facilitator_id, created_by, created_at, updated_at`
+
+// runAtOrder sorts by run_at as a time of day rather than as text, so legacy
+// unpadded values like "9:30" still sort before "10:00". The hour is everything
+// before the trailing ":MM", which works the same on SQLite and PostgreSQL.
+const runAtOrder = `CAST(substr(run_at, 1, length(run_at) - 3) AS INTEGER),
+		         CAST(substr(run_at, length(run_at) - 1) AS INTEGER)`
+
+// scanStandup scans a single standup row selected with standupColumns
+func scanStandup(row interface{ Scan(...interface{}) error }) (database.Standup, error) {
 	var standup database.Standup
-	var facilitatorID sql.NullInt64
-	err := database.DB.QueryRow(query, id).Scan(
+	var facilitatorID, ownerID, nominatedID sql.NullInt64
+	var webhookURLs string
+	err := row.Scan(
 		&standup.ID,
 		&standup.Name,
 		&standup.Message,
 		&standup.RunAt,
 		&standup.IsActive,
 		&facilitatorID,
+		&standup.WebhookURL,
+		&standup.Membership,
+		&standup.SkipWhenAlone,
+		&standup.ShowReturning,
+		&standup.FullTeamMessage,
+		&standup.ManualSendRotates,
+		&standup.Timezone,
+		&standup.FacilitatorOnly,
+		&standup.DaysOfWeek,
+		&ownerID,
+		&standup.DailyThread,
+		&standup.EmptyRetryMinutes,
+		&standup.MessageFormat,
+		&standup.Template,
+		&webhookURLs,
+		&standup.MentionFacilitator,
+		&standup.ThreadKey,
+		&nominatedID,
 		&standup.CreatedBy,
 		&standup.CreatedAt,
 		&standup.UpdatedAt,
 	)
-
 	if err != nil {
-		return nil, fmt.Errorf("failed to get standup: %w", err)
+		return standup, err
 	}
 
 	if facilitatorID.Valid {
@@ -59,6 +264,119 @@ func GetStandupByID(id int) (*database.Standup, error) {
 		standup.LastFacilitatorID = &id
 	}
 
+	if ownerID.Valid {
+		id := int(ownerID.Int64)
+		standup.OwnerUserID = &id
+	}
+
+	if nominatedID.Valid {
+		id := int(nominatedID.Int64)
+		standup.NominatedFacilitatorID = &id
+	}
+
+	if webhookURLs != "" {
+		if err := json.Unmarshal([]byte(webhookURLs), &standup.WebhookURLs); err != nil {
+			return standup, fmt.Errorf("failed to decode webhook_urls of standup %d: %w", standup.ID, err)
+		}
+	}
+
+	standup.EffectiveTimezone = standupLocation(&standup).String()
+
+	return standup, nil
+}
+
+// queryStandups runs a standup query and scans every returned row
+func queryStandups(query string, args ...interface{}) ([]database.Standup, error) {
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query standups: %w", err)
+	}
+	defer rows.Close()
+
+	var standups []database.Standup
+	for rows.Next() {
+		standup, err := scanStandup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan standup: %w", err)
+		}
+		standups = append(standups, standup)
+	}
+
+	return standups, nil
+}
+
+// validateTimezone accepts "" (use the global default) or a loadable IANA zone name
+func validateTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return ErrInvalidTimezone
+	}
+	return nil
+}
+
+// standupLocation returns the standup's own timezone, falling back to the configured one
+func standupLocation(standup *database.Standup) *time.Location {
+	if standup.Timezone != "" {
+		if loc, err := time.LoadLocation(standup.Timezone); err == nil {
+			return loc
+		}
+	}
+	return config.Config.Location()
+}
+
+// standupToday returns today's date (YYYY-MM-DD) in the standup's timezone, the day its reminder
+// belongs to. Around midnight it can differ from Today(), which uses the configured timezone.
+func standupToday(standup *database.Standup) string {
+	return now().In(standupLocation(standup)).Format("2006-01-02")
+}
+
+// standupNow returns the current time in the standup's timezone, falling back to the configured
+// one when the standup cannot be loaded
+func standupNow(standupID int) time.Time {
+	if standup, err := GetStandupByID(standupID); err == nil {
+		return now().In(standupLocation(standup))
+	}
+	return now().In(config.Config.Location())
+}
+
+// StandupToday returns today's date (YYYY-MM-DD) in the timezone of the standup with the given ID
+func StandupToday(standupID int) string {
+	return standupNow(standupID).Format("2006-01-02")
+}
+
+// ValidRunAt reports whether runAt is a valid HH:MM 24-hour time (an unpadded hour like "9:30" is accepted)
+func ValidRunAt(runAt string) bool {
+	_, err := time.Parse("15:04", runAt)
+	return err == nil
+}
+
+// normalizeRunAt zero-pads a parseable HH:MM time (e.g. "9:30" -> "09:30");
+// unparseable values are returned unchanged
+func normalizeRunAt(runAt string) string {
+	parsed, err := time.Parse("15:04", runAt)
+	if err != nil {
+		return runAt
+	}
+	return parsed.Format("15:04")
+}
+
+// GetStandupByID retrieves a standup by ID
+func GetStandupByID(id int) (*database.Standup, error) {
+	defer database.TimeQuery("GetStandupByID", time.Now())
+
+	query := `
+		SELECT ` + standupColumns + `
+		FROM standups
+		WHERE id = ?
+	`
+
+	standup, err := scanStandup(database.DB.QueryRow(query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get standup: %w", err)
+	}
+
 	return &standup, nil
 }
 
@@ -81,14 +399,17 @@ func GetStandupWithMembers(id int) (*database.StandupWithMembers, error) {
 
 	// Get last facilitator if set
 	if standup.LastFacilitatorID != nil {
-		facilitator, err := getUserByID(*standup.LastFacilitatorID)
+		facilitator, err := GetUserByID(*standup.LastFacilitatorID)
 		if err == nil {
 			result.LastFacilitator = facilitator
 		}
+
+		// Flag when the rotation will restart because the last facilitator left the standup
+		result.RotationReset = findUser(members, *standup.LastFacilitatorID) == nil
 	}
 
 	// Calculate current facilitator from eligible users
-	eligibleUsers, err := database.GetEligibleUsersForStandup(id)
+	eligibleUsers, err := database.GetEligibleUsersForStandup(id, standupToday(standup))
 	if err == nil && len(eligibleUsers) > 0 {
 		currentFac, err := GetCurrentFacilitator(id, eligibleUsers)
 		if err == nil {
@@ -96,192 +417,841 @@ func GetStandupWithMembers(id int) (*database.StandupWithMembers, error) {
 		}
 	}
 
+	result.NextRunAt, err = NextRunTime(id)
+	if err != nil {
+		slog.Warn("Could not compute next run", "standup_id", id, "error", err)
+	}
+
 	return result, nil
 }
 
-// getUserByID retrieves a user by ID (helper function)
-func getUserByID(id int) (*database.User, error) {
+// GetAllStandups retrieves all standups
+func GetAllStandups() ([]database.Standup, error) {
+	defer database.TimeQuery("GetAllStandups", time.Now())
+
 	query := `
-		SELECT id, google_chat_user_id, display_name, email, is_active,
-		       joined_at, left_at, created_at, updated_at
-		FROM users
-		WHERE id = ?
+		SELECT ` + standupColumns + `
+		FROM standups
+		ORDER BY ` + runAtOrder + `, name, id
 	`
 
-	var user database.User
-	var leftAt sql.NullTime
-	err := database.DB.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.GoogleChatUserID,
-		&user.DisplayName,
-		&user.Email,
-		&user.IsActive,
-		&user.JoinedAt,
-		&leftAt,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	return queryStandups(query)
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+// GetAllStandupsPaginated retrieves a page of standups along with the total count
+func GetAllStandupsPaginated(limit, offset int) ([]database.Standup, int, error) {
+	var total int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM standups").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count standups: %w", err)
 	}
 
-	if leftAt.Valid {
-		user.LeftAt = &leftAt.Time
+	query := `
+		SELECT ` + standupColumns + `
+		FROM standups
+		ORDER BY ` + runAtOrder + `, name, id
+		LIMIT ? OFFSET ?
+	`
+
+	standups, err := queryStandups(query, limit, offset)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return &user, nil
+	return standups, total, nil
 }
 
-// GetAllStandups retrieves all standups
-func GetAllStandups() ([]database.Standup, error) {
+// GetActiveStandups retrieves all active standups
+func GetActiveStandups() ([]database.Standup, error) {
+	defer database.TimeQuery("GetActiveStandups", time.Now())
+
+	query := `
+		SELECT ` + standupColumns + `
+		FROM standups
+		WHERE is_active = TRUE
+		ORDER BY ` + runAtOrder + `, name, id
+	`
+
+	return queryStandups(query)
+}
+
+// CountActiveStandups returns how many standups are active
+func CountActiveStandups() (int, error) {
+	var count int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM standups WHERE is_active = TRUE").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count active standups: %w", err)
+	}
+	return count, nil
+}
+
+// GetActiveStandupsPaginated retrieves a page of active standups along with the total count
+func GetActiveStandupsPaginated(limit, offset int) ([]database.Standup, int, error) {
+	var total int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM standups WHERE is_active = TRUE").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count active standups: %w", err)
+	}
+
 	query := `
-		SELECT id, name, message, run_at, is_active, last_facilitator_id, created_by, created_at, updated_at
+		SELECT ` + standupColumns + `
 		FROM standups
-		ORDER BY run_at, name
+		WHERE is_active = TRUE
+		ORDER BY ` + runAtOrder + `, name, id
+		LIMIT ? OFFSET ?
 	`
 
-	rows, err := database.DB.Query(query)
+	standups, err := queryStandups(query, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query standups: %w", err)
+		return nil, 0, err
 	}
-	defer rows.Close()
 
-	var standups []database.Standup
-	for rows.Next() {
-		var standup database.Standup
-		var facilitatorID sql.NullInt64
-		err := rows.Scan(
-			&standup.ID,
-			&standup.Name,
-			&standup.Message,
-			&standup.RunAt,
-			&standup.IsActive,
-			&facilitatorID,
-			&standup.CreatedBy,
-			&standup.CreatedAt,
-			&standup.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan standup: %w", err)
-		}
-		if facilitatorID.Valid {
-			id := int(facilitatorID.Int64)
-			standup.LastFacilitatorID = &id
-		}
-		standups = append(standups, standup)
+	return standups, total, nil
+}
+
+// runAtMinutes is run_at as minutes since midnight, so time ranges compare numerically
+const runAtMinutes = `(CAST(substr(run_at, 1, length(run_at) - 3) AS INTEGER) * 60
+		         + CAST(substr(run_at, length(run_at) - 1) AS INTEGER))`
+
+// GetStandupsByTimeRange retrieves standups whose run_at falls between from and to (HH:MM, inclusive).
+// A range that wraps past midnight (e.g. 23:00 to 01:00) matches both ends of the day.
+func GetStandupsByTimeRange(from, to string, activeOnly bool) ([]database.Standup, error) {
+	fromTime, err := time.Parse("15:04", from)
+	if err != nil {
+		return nil, ErrInvalidTimeRange
+	}
+	toTime, err := time.Parse("15:04", to)
+	if err != nil {
+		return nil, ErrInvalidTimeRange
 	}
 
-	return standups, nil
+	fromMinutes := fromTime.Hour()*60 + fromTime.Minute()
+	toMinutes := toTime.Hour()*60 + toTime.Minute()
+
+	condition := runAtMinutes + ` BETWEEN ? AND ?`
+	if fromMinutes > toMinutes {
+		condition = `(` + runAtMinutes + ` >= ? OR ` + runAtMinutes + ` <= ?)`
+	}
+	if activeOnly {
+		condition += ` AND is_active = TRUE`
+	}
+
+	query := `
+		SELECT ` + standupColumns + `
+		FROM standups
+		WHERE ` + condition + `
+		ORDER BY ` + runAtOrder + `, name, id
+	`
+
+	return queryStandups(query, fromMinutes, toMinutes)
 }
 
-// GetActiveStandups retrieves all active standups
-func GetActiveStandups() ([]database.Standup, error) {
+// GetStandupsByWebhookURL retrieves all standups posting to exactly the given webhook URL,
+// either as their main webhook or as one of their extra webhooks
+func GetStandupsByWebhookURL(webhookURL string) ([]database.Standup, error) {
 	query := `
-		SELECT id, name, message, run_at, is_active, last_facilitator_id, created_by, created_at, updated_at
+		SELECT ` + standupColumns + `
 		FROM standups
-		WHERE is_active = 1
-		ORDER BY run_at, name
+		WHERE webhook_url = ? OR ` + database.JSONListContains("webhook_urls") + `
+		ORDER BY ` + runAtOrder + `, name, id
 	`
 
-	rows, err := database.DB.Query(query)
+	return queryStandups(query, webhookURL, webhookURL)
+}
+
+// SetStandupWebhookURL sets the webhook a standup posts to (empty uses the global webhook)
+func SetStandupWebhookURL(id int, webhookURL string) error {
+	return setStandupField(id, "webhook_url", webhookURL)
+}
+
+// SetStandupWebhookURLs sets the extra webhooks a standup's reminder is also posted to.
+// Blank and repeated entries are dropped; an empty list removes them all.
+func SetStandupWebhookURLs(id int, webhookURLs []string) error {
+	value, err := encodeWebhookURLs(webhookURLs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query active standups: %w", err)
+		return err
 	}
-	defer rows.Close()
+	return setStandupField(id, "webhook_urls", value)
+}
 
-	var standups []database.Standup
-	for rows.Next() {
-		var standup database.Standup
-		var facilitatorID sql.NullInt64
-		err := rows.Scan(
-			&standup.ID,
-			&standup.Name,
-			&standup.Message,
-			&standup.RunAt,
-			&standup.IsActive,
-			&facilitatorID,
-			&standup.CreatedBy,
-			&standup.CreatedAt,
-			&standup.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan standup: %w", err)
+// encodeWebhookURLs stores extra webhooks as a JSON list without blank or repeated entries ("" when none are left)
+func encodeWebhookURLs(webhookURLs []string) (string, error) {
+	urls := []string{}
+	seen := make(map[string]bool)
+	for _, webhookURL := range webhookURLs {
+		webhookURL = strings.TrimSpace(webhookURL)
+		if webhookURL == "" || seen[webhookURL] {
+			continue
+		}
+		seen[webhookURL] = true
+		urls = append(urls, webhookURL)
+	}
+
+	if len(urls) == 0 {
+		return "", nil
+	}
+
+	encoded, err := json.Marshal(urls)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode webhook_urls: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// SetStandupMembership switches a standup between its explicit roster and all active users.
+// The explicit roster is kept while all_active is in effect, so switching back restores it.
+func SetStandupMembership(id int, membership string) error {
+	if membership != database.MembershipExplicit && membership != database.MembershipAllActive {
+		return ErrInvalidMembership
+	}
+	return setStandupField(id, "membership", membership)
+}
+
+// SetStandupSkipWhenAlone sets whether the reminder is skipped when only one member is eligible
+func SetStandupSkipWhenAlone(id int, skip bool) error {
+	return setStandupField(id, "skip_when_alone", skip)
+}
+
+// SetStandupShowReturning sets whether reminders list members returning from leave tomorrow
+func SetStandupShowReturning(id int, show bool) error {
+	return setStandupField(id, "show_returning", show)
+}
+
+// SetStandupFullTeamMessage sets the line shown when nobody is on leave (empty disables it)
+func SetStandupFullTeamMessage(id int, message string) error {
+	return setStandupField(id, "full_team_message", message)
+}
+
+// weekdayNames are the cron day-of-week names, indexed like time.Weekday
+var weekdayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// NormalizeDaysOfWeek turns "1,3,5" or "mon, wed, fri" into "MON,WED,FRI", ordered Sunday first
+// and without duplicates. "" stays "" (every day).
+func NormalizeDaysOfWeek(days string) (string, error) {
+	if strings.TrimSpace(days) == "" {
+		return "", nil
+	}
+
+	selected := make([]bool, len(weekdayNames))
+	for _, part := range strings.Split(days, ",") {
+		part = strings.ToUpper(strings.TrimSpace(part))
+
+		index := -1
+		for i, name := range weekdayNames {
+			if part == name || part == strconv.Itoa(i) {
+				index = i
+				break
+			}
 		}
-		if facilitatorID.Valid {
-			id := int(facilitatorID.Int64)
-			standup.LastFacilitatorID = &id
+		if index == -1 {
+			return "", ErrInvalidDaysOfWeek
 		}
-		standups = append(standups, standup)
+		selected[index] = true
 	}
 
-	return standups, nil
+	var names []string
+	for i, on := range selected {
+		if on {
+			names = append(names, weekdayNames[i])
+		}
+	}
+	return strings.Join(names, ","), nil
+}
+
+// SetStandupDaysOfWeek sets the days a standup's reminder fires ("" = every day)
+func SetStandupDaysOfWeek(id int, days string) error {
+	normalized, err := NormalizeDaysOfWeek(days)
+	if err != nil {
+		return err
+	}
+	return setStandupField(id, "days_of_week", normalized)
+}
+
+// SetStandupFacilitatorOnly sets whether the reminder only calls out today's facilitator
+func SetStandupFacilitatorOnly(id int, facilitatorOnly bool) error {
+	return setStandupField(id, "facilitator_only", facilitatorOnly)
+}
+
+// ValidateStandupOwner checks that ownerID (0 = no owner) is an existing user
+func ValidateStandupOwner(ownerID int) error {
+	if ownerID == 0 {
+		return nil
+	}
+
+	if _, err := GetUserByID(ownerID); err != nil {
+		return ErrInvalidOwner
+	}
+	return nil
+}
+
+// SetStandupOwner sets the user accountable for a standup (0 removes the owner)
+func SetStandupOwner(id, ownerID int) error {
+	if err := ValidateStandupOwner(ownerID); err != nil {
+		return err
+	}
+
+	var owner interface{}
+	if ownerID != 0 {
+		owner = ownerID
+	}
+	return setStandupField(id, "owner_user_id", owner)
+}
+
+// SetStandupManualSendRotates sets whether manual sends advance the rotation when the request doesn't say
+func SetStandupManualSendRotates(id int, rotates bool) error {
+	return setStandupField(id, "manual_send_rotates", rotates)
+}
+
+// SetStandupDailyThread sets whether each day's reminder is posted into its own thread
+func SetStandupDailyThread(id int, dailyThread bool) error {
+	return setStandupField(id, "daily_thread", dailyThread)
+}
+
+// SetStandupEmptyRetryMinutes sets how long after a scheduled send with nobody eligible the standup re-checks (0 disables)
+func SetStandupEmptyRetryMinutes(id int, minutes int) error {
+	if minutes < 0 {
+		return ErrInvalidEmptyRetry
+	}
+	return setStandupField(id, "empty_retry_minutes", minutes)
+}
+
+// ValidMessageFormat reports whether a standup message format is supported
+func ValidMessageFormat(format string) bool {
+	return format == database.MessageFormatText || format == database.MessageFormatCard ||
+		format == database.MessageFormatStatusCard
+}
+
+// SetStandupMessageFormat sets how the reminder is posted: plain text, a cardsV2 card or a status card
+func SetStandupMessageFormat(id int, format string) error {
+	if !ValidMessageFormat(format) {
+		return ErrInvalidMessageFormat
+	}
+	return setStandupField(id, "message_format", format)
+}
+
+// SetStandupTemplate sets the text/template the reminder is rendered with ("" = built-in format)
+func SetStandupTemplate(id int, tmpl string) error {
+	if err := ValidateReminderTemplate(tmpl); err != nil {
+		return err
+	}
+	return setStandupField(id, "template", tmpl)
+}
+
+// SetStandupMentionFacilitator sets whether text reminders @-mention today's facilitator
+func SetStandupMentionFacilitator(id int, enabled bool) error {
+	return setStandupField(id, "mention_facilitator", enabled)
+}
+
+// SetStandupThreadKey sets the Google Chat thread key reminders are posted into ("" = main timeline)
+func SetStandupThreadKey(id int, threadKey string) error {
+	return setStandupField(id, "thread_key", threadKey)
+}
+
+// setStandupField updates a single settings column on a standup.
+// column must be a trusted column name, never user input.
+func setStandupField(id int, column string, value interface{}) error {
+	query := `
+		UPDATE standups
+		SET ` + column + ` = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	result, err := database.DB.Exec(query, value, id)
+	if err != nil {
+		return fmt.Errorf("failed to update standup %s: %w", column, err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("standup not found")
+	}
+
+	return nil
+}
+
+// GetStandupsForUser retrieves the active standups a user is a member of
+func GetStandupsForUser(userID int) ([]database.Standup, error) {
+	defer database.TimeQuery("GetStandupsForUser", time.Now())
+
+	query := `
+		SELECT ` + standupColumns + `
+		FROM standups
+		WHERE is_active = TRUE
+		AND (
+			membership = 'all_active'
+			OR id IN (SELECT standup_id FROM standup_members WHERE user_id = ?)
+		)
+		ORDER BY ` + runAtOrder + `, name, id
+	`
+
+	return queryStandups(query, userID)
+}
+
+// GetStandupsByMember retrieves the standups a user is currently a member of: explicit standups
+// listing them, plus 'all_active' standups while the user is active. Unlike GetStandupsForUser
+// it includes inactive standups unless activeOnly is set. No matches is an empty list.
+func GetStandupsByMember(userID int, activeOnly bool) ([]database.Standup, error) {
+	defer database.TimeQuery("GetStandupsByMember", time.Now())
+
+	condition := `(
+			(membership = 'all_active' AND EXISTS (SELECT 1 FROM users WHERE id = ? AND is_active = TRUE))
+			OR (membership != 'all_active'
+				AND id IN (SELECT standup_id FROM standup_members WHERE user_id = ?))
+		)`
+	if activeOnly {
+		condition += ` AND is_active = TRUE`
+	}
+
+	query := `
+		SELECT ` + standupColumns + `
+		FROM standups
+		WHERE ` + condition + `
+		ORDER BY ` + runAtOrder + `, name, id
+	`
+
+	standups, err := queryStandups(query, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if standups == nil {
+		standups = []database.Standup{}
+	}
+	return standups, nil
+}
+
+// UpdateStandup updates a standup. A nil timezone leaves it unchanged; "" reverts to the global default.
+func UpdateStandup(id int, name, message, runAt string, timezone *string) error {
+	if timezone != nil {
+		if err := validateTimezone(*timezone); err != nil {
+			return err
+		}
+	}
+
+	runAt = normalizeRunAt(runAt)
+
+	// Get current standup to log changes
+	oldStandup, err := GetStandupByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get standup: %w", err)
+	}
+
+	query := `
+		UPDATE standups
+		SET name = ?, message = ?, run_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	result, err := database.DB.Exec(query, name, message, runAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update standup: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("standup not found")
+	}
+
+	// Log if schedule time changed
+	if oldStandup.RunAt != runAt {
+		slog.Info("📅 [SCHEDULE UPDATE] Schedule changed", "standup_id", id, "standup", name, "from", oldStandup.RunAt, "to", runAt)
+	}
+
+	if timezone != nil && *timezone != oldStandup.Timezone {
+		if err := setStandupField(id, "timezone", *timezone); err != nil {
+			return err
+		}
+		slog.Info("📅 [SCHEDULE UPDATE] Timezone changed", "standup_id", id, "standup", name, "from", oldStandup.Timezone, "to", *timezone)
+	}
+
+	return nil
+}
+
+// DeleteStandup deactivates a standup
+func DeleteStandup(id int) error {
+	query := `
+		UPDATE standups
+		SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	result, err := database.DB.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete standup: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("standup not found")
+	}
+
+	return nil
+}
+
+// ReactivateStandup reactivates a standup. Reactivation counts toward MAX_ACTIVE_STANDUPS.
+func ReactivateStandup(id int) error {
+	if err := checkActiveStandupLimit(id); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE standups
+		SET is_active = TRUE, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	result, err := database.DB.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to reactivate standup: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("standup not found")
+	}
+
+	return nil
+}
+
+// AddStandupMember appends a user to the end of a standup roster
+func AddStandupMember(standupID, userID int) error {
+	return AddStandupMembers(standupID, []int{userID})
+}
+
+// AddStandupMembers appends users to the end of a standup roster in the given order, leaving
+// existing members and their order untouched. Nothing is added unless every user exists
+// (ErrUserNotFound) and none is already a member (ErrAlreadyStandupMember).
+func AddStandupMembers(standupID int, userIDs []int) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	seen := make(map[int]bool, len(userIDs))
+	for _, userID := range userIDs {
+		var exists, member int
+		err := tx.QueryRow(`
+			SELECT
+				(SELECT COUNT(*) FROM users WHERE id = ?),
+				(SELECT COUNT(*) FROM standup_members WHERE standup_id = ? AND user_id = ?)
+		`, userID, standupID, userID).Scan(&exists, &member)
+		if err != nil {
+			return fmt.Errorf("failed to check member %d: %w", userID, err)
+		}
+		if exists == 0 {
+			return fmt.Errorf("%w: %d", ErrUserNotFound, userID)
+		}
+		if member > 0 || seen[userID] {
+			return fmt.Errorf("%w: %d", ErrAlreadyStandupMember, userID)
+		}
+		seen[userID] = true
+	}
+
+	var nextOrder int
+	err = tx.QueryRow(
+		"SELECT COALESCE(MAX(display_order) + 1, 0) FROM standup_members WHERE standup_id = ?",
+		standupID,
+	).Scan(&nextOrder)
+	if err != nil {
+		return fmt.Errorf("failed to get next display order: %w", err)
+	}
+
+	for i, userID := range userIDs {
+		_, err = tx.Exec(
+			"INSERT INTO standup_members (standup_id, user_id, display_order) VALUES (?, ?, ?)",
+			standupID, userID, nextOrder+i,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add standup member: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveStandupMember removes a user from a standup roster
+func RemoveStandupMember(standupID, userID int) error {
+	// Start transaction
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Get the display_order of the member being removed
+	var removedOrder int
+	err = tx.QueryRow(
+		"SELECT display_order FROM standup_members WHERE standup_id = ? AND user_id = ?",
+		standupID, userID,
+	).Scan(&removedOrder)
+
+	if err != nil {
+		return fmt.Errorf("member not found")
+	}
+
+	// Delete the member
+	_, err = tx.Exec(
+		"DELETE FROM standup_members WHERE standup_id = ? AND user_id = ?",
+		standupID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove standup member: %w", err)
+	}
+
+	// Reorder remaining members to fill the gap
+	_, err = tx.Exec(
+		"UPDATE standup_members SET display_order = display_order - 1 WHERE standup_id = ? AND display_order > ?",
+		standupID, removedOrder,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reorder members: %w", err)
+	}
+
+	// If the removed member facilitated last, hand last_facilitator_id to the member before them
+	// so the rotation carries on with whoever came after them instead of restarting from the top
+	_, err = tx.Exec(`
+		UPDATE standups SET last_facilitator_id = (
+			SELECT user_id FROM standup_members
+			WHERE standup_id = ?
+			ORDER BY display_order >= ?, display_order DESC
+			LIMIT 1
+		)
+		WHERE id = ? AND last_facilitator_id = ?
+	`, standupID, removedOrder, standupID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update last facilitator: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// IsStandupMember reports whether a user is assigned to a standup
+func IsStandupMember(standupID, userID int) (bool, error) {
+	var count int
+	err := database.DB.QueryRow(
+		"SELECT COUNT(*) FROM users u WHERE u.id = ? AND "+database.StandupMemberFilter,
+		userID, standupID,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check standup membership: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetStandupMembers retrieves all users assigned to a standup in rotation order
+// (display_order, or display_name for all_active standups)
+func GetStandupMembers(standupID int) ([]database.User, error) {
+	defer database.TimeQuery("GetStandupMembers", time.Now())
+
+	query := `
+		SELECT u.id, u.google_chat_user_id, u.display_name, u.email, u.is_active,
+		       u.joined_at, u.left_at, u.created_at, u.updated_at
+		FROM users u
+		WHERE ` + database.StandupMemberFilter + `
+		ORDER BY ` + database.StandupMemberOrder + `
+	`
+
+	users, err := queryUsers(query, standupID, standupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get standup members: %w", err)
+	}
+
+	return users, nil
+}
+
+// SetStandupMembers replaces all members of a standup with a new list; an empty list removes them all
+func SetStandupMembers(standupID int, userIDs []int) error {
+	// Start transaction
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Leave the roster untouched unless every user exists
+	if err := checkUsersExist(tx, userIDs); err != nil {
+		return err
+	}
+
+	// Keep the settings of members who stay on the roster
+	settings, err := queryMemberSettings(tx, standupID)
+	if err != nil {
+		return err
+	}
+
+	// Delete existing members
+	_, err = tx.Exec("DELETE FROM standup_members WHERE standup_id = ?", standupID)
+	if err != nil {
+		return fmt.Errorf("failed to delete existing members: %w", err)
+	}
+
+	// Insert new members with display_order
+	stmt, err := tx.Prepare("INSERT INTO standup_members (standup_id, user_id, display_order, alias, can_facilitate) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, userID := range userIDs {
+		canFacilitate := true
+		if member, ok := settings[userID]; ok {
+			canFacilitate = member.CanFacilitate
+		}
+
+		_, err = stmt.Exec(standupID, userID, i, settings[userID].Alias, canFacilitate)
+		if err != nil {
+			return fmt.Errorf("failed to insert member %d: %w", userID, err)
+		}
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateStandupMembers checks that every user in a roster exists; the ErrUserNotFound names the first that does not
+func ValidateStandupMembers(userIDs []int) error {
+	return checkUsersExist(database.DB, userIDs)
+}
+
+// checkUsersExist returns ErrUserNotFound for the first user ID with no user
+func checkUsersExist(q interface {
+	QueryRow(string, ...interface{}) *sql.Row
+}, userIDs []int) error {
+	for _, userID := range userIDs {
+		var exists int
+		if err := q.QueryRow("SELECT COUNT(*) FROM users WHERE id = ?", userID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check member %d: %w", userID, err)
+		}
+		if exists == 0 {
+			return fmt.Errorf("%w: %d", ErrUserNotFound, userID)
+		}
+	}
+	return nil
+}
+
+// queryMemberSettings returns the per-standup settings (alias, can_facilitate) of a standup's stored roster, by user ID
+func queryMemberSettings(q interface {
+	Query(string, ...interface{}) (*sql.Rows, error)
+}, standupID int) (map[int]database.StandupMember, error) {
+	rows, err := q.Query(
+		"SELECT standup_id, user_id, alias, can_facilitate, created_at FROM standup_members WHERE standup_id = ?",
+		standupID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member settings: %w", err)
+	}
+	defer rows.Close()
+
+	settings := make(map[int]database.StandupMember)
+	for rows.Next() {
+		var member database.StandupMember
+		if err := rows.Scan(&member.StandupID, &member.UserID, &member.Alias, &member.CanFacilitate, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan member settings: %w", err)
+		}
+		settings[member.UserID] = member
+	}
+
+	return settings, rows.Err()
+}
+
+// GetStandupMemberSettings returns the per-standup settings of a standup's stored roster, by user ID.
+// Users who are members only through all_active have no entry and use the defaults.
+func GetStandupMemberSettings(standupID int) (map[int]database.StandupMember, error) {
+	defer database.TimeQuery("GetStandupMemberSettings", time.Now())
+
+	return queryMemberSettings(database.DB, standupID)
 }
 
-// UpdateStandup updates a standup
-func UpdateStandup(id int, name, message, runAt string) error {
-	// Get current standup to log changes
-	oldStandup, err := GetStandupByID(id)
+// GetStandupMemberAliases maps user ID to the alias shown for that member in the standup's reminders
+func GetStandupMemberAliases(standupID int) (map[int]string, error) {
+	settings, err := GetStandupMemberSettings(standupID)
 	if err != nil {
-		return fmt.Errorf("failed to get standup: %w", err)
+		return nil, err
 	}
 
-	query := `
-		UPDATE standups
-		SET name = ?, message = ?, run_at = ?, updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
-	`
+	aliases := make(map[int]string)
+	for userID, member := range settings {
+		if member.Alias != "" {
+			aliases[userID] = member.Alias
+		}
+	}
+	return aliases, nil
+}
 
-	result, err := database.DB.Exec(query, name, message, runAt, id)
+// SetStandupMemberAlias sets the name a member is shown as in this standup's reminders
+// ("" falls back to display_name). Aliases live on the stored roster, so only users in
+// standup_members can have one, even on an all_active standup.
+func SetStandupMemberAlias(standupID, userID int, alias string) error {
+	result, err := database.DB.Exec(
+		"UPDATE standup_members SET alias = ? WHERE standup_id = ? AND user_id = ?",
+		alias, standupID, userID,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to update standup: %w", err)
+		return fmt.Errorf("failed to set member alias: %w", err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("standup not found")
-	}
-
-	// Log if schedule time changed
-	if oldStandup.RunAt != runAt {
-		log.Printf("📅 [SCHEDULE UPDATE] Standup '%s' (ID: %d) schedule changed from %s to %s", name, id, oldStandup.RunAt, runAt)
+		return ErrNotStandupMember
 	}
 
 	return nil
 }
 
-// DeleteStandup deactivates a standup
-func DeleteStandup(id int) error {
-	query := `
-		UPDATE standups
-		SET is_active = 0, updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
-	`
-
-	result, err := database.DB.Exec(query, id)
+// SetStandupMemberCanFacilitate sets whether a member takes facilitation turns in this standup.
+// Like aliases, the flag lives on the stored roster.
+func SetStandupMemberCanFacilitate(standupID, userID int, canFacilitate bool) error {
+	result, err := database.DB.Exec(
+		"UPDATE standup_members SET can_facilitate = ? WHERE standup_id = ? AND user_id = ?",
+		canFacilitate, standupID, userID,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to delete standup: %w", err)
+		return fmt.Errorf("failed to set member can_facilitate: %w", err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("standup not found")
+		return ErrNotStandupMember
 	}
 
 	return nil
 }
 
-// ReactivateStandup reactivates a standup
-func ReactivateStandup(id int) error {
-	query := `
+// SetLastFacilitator records that userID took the standup's latest rotation turn: the turn
+// counter advances, the member's turn is stamped with it and they become last_facilitator_id.
+// When the member had already had their turn this cycle, a new cycle starts with them.
+func SetLastFacilitator(standupID, userID int) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Every right-hand side sees the row as it was before the update
+	result, err := tx.Exec(`
 		UPDATE standups
-		SET is_active = 1, updated_at = CURRENT_TIMESTAMP
+		SET rotation_cycle_start = CASE
+		        WHEN COALESCE((SELECT turn FROM facilitator_turns WHERE standup_id = ? AND user_id = ?), 0) >= rotation_cycle_start
+		        THEN rotation_counter + 1
+		        ELSE rotation_cycle_start
+		    END,
+		    rotation_counter = rotation_counter + 1,
+		    last_facilitator_id = ?,
+		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
-	`
-
-	result, err := database.DB.Exec(query, id)
+	`, standupID, userID, userID, standupID)
 	if err != nil {
-		return fmt.Errorf("failed to reactivate standup: %w", err)
+		return fmt.Errorf("failed to set last facilitator: %w", err)
 	}
 
 	rowsAffected, _ := result.RowsAffected()
@@ -289,297 +1259,416 @@ func ReactivateStandup(id int) error {
 		return fmt.Errorf("standup not found")
 	}
 
-	return nil
-}
-
-// AddStandupMember adds a user to a standup roster
-func AddStandupMember(standupID, userID int) error {
-	query := `
-		INSERT OR IGNORE INTO standup_members (standup_id, user_id)
-		VALUES (?, ?)
-	`
-
-	_, err := database.DB.Exec(query, standupID, userID)
+	_, err = tx.Exec(`
+		INSERT INTO facilitator_turns (standup_id, user_id, turn)
+		SELECT id, ?, rotation_counter FROM standups WHERE id = ?
+		ON CONFLICT (standup_id, user_id) DO UPDATE SET turn = excluded.turn
+	`, userID, standupID)
 	if err != nil {
-		return fmt.Errorf("failed to add standup member: %w", err)
+		return fmt.Errorf("failed to record facilitator turn: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
-// RemoveStandupMember removes a user from a standup roster
-func RemoveStandupMember(standupID, userID int) error {
-	// Start transaction
-	tx, err := database.DB.Begin()
+// GetCurrentFacilitator calculates the current facilitator from eligible users based on last facilitator,
+// handing the turn to a substitute when the member whose turn it is has one today. A nominated
+// facilitator who is eligible takes precedence over the rotation.
+func GetCurrentFacilitator(standupID int, eligibleUsers []database.User) (*database.User, error) {
+	standup, err := GetStandupByID(standupID)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, err
+	}
+	if nominee := nominatedFacilitator(standup, eligibleUsers); nominee != nil {
+		return nominee, nil
 	}
-	defer tx.Rollback()
 
-	// Get the display_order of the member being removed
-	var removedOrder int
-	err = tx.QueryRow(
-		"SELECT display_order FROM standup_members WHERE standup_id = ? AND user_id = ?",
-		standupID, userID,
-	).Scan(&removedOrder)
+	_, facilitator, err := GetCurrentFacilitatorSlot(standupID, eligibleUsers)
+	return facilitator, err
+}
 
-	if err != nil {
-		return fmt.Errorf("member not found")
+// GetCurrentFacilitatorSlot returns whose turn it is in the rotation (slot) and who actually
+// facilitates today. They differ only when the slot owner is covered by a substitution; the
+// rotation should always advance from the slot so the normal order resumes afterwards.
+func GetCurrentFacilitatorSlot(standupID int, eligibleUsers []database.User) (slot, facilitator *database.User, err error) {
+	return facilitatorSlotOn(standupID, eligibleUsers, StandupToday(standupID))
+}
+
+// facilitatorSlotOn returns the current rotation slot and who facilitates it with day's substitutions
+func facilitatorSlotOn(standupID int, eligibleUsers []database.User, day string) (slot, facilitator *database.User, err error) {
+	if len(eligibleUsers) == 0 {
+		return nil, nil, fmt.Errorf("no eligible users")
 	}
 
-	// Delete the member
-	_, err = tx.Exec(
-		"DELETE FROM standup_members WHERE standup_id = ? AND user_id = ?",
-		standupID, userID,
-	)
+	candidates, substitutes, err := rotationCandidates(standupID, eligibleUsers, day)
 	if err != nil {
-		return fmt.Errorf("failed to remove standup member: %w", err)
+		return nil, nil, err
 	}
 
-	// Reorder remaining members to fill the gap
-	_, err = tx.Exec(
-		"UPDATE standup_members SET display_order = display_order - 1 WHERE standup_id = ? AND display_order > ?",
-		standupID, removedOrder,
-	)
+	slot, err = currentRotationSlot(standupID, candidates)
 	if err != nil {
-		return fmt.Errorf("failed to reorder members: %w", err)
+		return nil, nil, err
 	}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if substitute, ok := substitutes[slot.ID]; ok {
+		return slot, substitute, nil
 	}
+	return slot, slot, nil
+}
 
-	return nil
+// facilitatorRotation is a standup's place in the facilitator rotation. Every turn taken advances
+// a per-standup counter and is stamped on the member who took it. A cycle starts at a counter
+// value, and a member is due while their last turn is older than that. The turn goes to the first
+// due candidate after the last facilitator in the current member order, so reordering or removing
+// members only changes the order among those still due: nobody takes a second turn while an
+// eligible member is still due, and nobody due is passed over.
+type facilitatorRotation struct {
+	members    []database.User // every member, in rotation order
+	lastID     int             // last facilitator, 0 when there is none
+	counter    int             // turns taken so far
+	cycleStart int             // turn number the current cycle started at
+	turns      map[int]int     // user ID -> last turn they took (0 = never)
 }
 
-// GetStandupMembers retrieves all users assigned to a standup, ordered by display_order
-func GetStandupMembers(standupID int) ([]database.User, error) {
-	query := `
-		SELECT u.id, u.google_chat_user_id, u.display_name, u.email, u.is_active,
-		       u.joined_at, u.left_at, u.created_at, u.updated_at
-		FROM users u
-		INNER JOIN standup_members sm ON u.id = sm.user_id
-		WHERE sm.standup_id = ?
-		ORDER BY sm.display_order, u.display_name
-	`
+// loadRotation reads a standup's rotation state and current member order
+func loadRotation(standupID int) (*facilitatorRotation, error) {
+	rotation := &facilitatorRotation{turns: make(map[int]int)}
+
+	var lastID sql.NullInt64
+	err := database.DB.QueryRow(
+		"SELECT last_facilitator_id, rotation_counter, rotation_cycle_start FROM standups WHERE id = ?",
+		standupID,
+	).Scan(&lastID, &rotation.counter, &rotation.cycleStart)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("standup not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rotation: %w", err)
+	}
+	rotation.lastID = int(lastID.Int64)
 
-	rows, err := database.DB.Query(query, standupID)
+	rows, err := database.DB.Query("SELECT user_id, turn FROM facilitator_turns WHERE standup_id = ?", standupID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query standup members: %w", err)
+		return nil, fmt.Errorf("failed to get facilitator turns: %w", err)
 	}
 	defer rows.Close()
 
-	var users []database.User
 	for rows.Next() {
-		var user database.User
-		var leftAt sql.NullTime
-
-		err := rows.Scan(
-			&user.ID,
-			&user.GoogleChatUserID,
-			&user.DisplayName,
-			&user.Email,
-			&user.IsActive,
-			&user.JoinedAt,
-			&leftAt,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
+		var userID, turn int
+		if err := rows.Scan(&userID, &turn); err != nil {
+			return nil, fmt.Errorf("failed to scan facilitator turn: %w", err)
 		}
+		rotation.turns[userID] = turn
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get facilitator turns: %w", err)
+	}
+
+	rotation.members, err = GetStandupMembers(standupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rotation, nil
+}
 
-		if leftAt.Valid {
-			user.LeftAt = &leftAt.Time
+// due reports whether a member has not had their turn in the current cycle
+func (r *facilitatorRotation) due(userID int) bool {
+	return r.turns[userID] < r.cycleStart
+}
+
+// slot returns whose turn it is among candidates. When every candidate has had their turn this
+// cycle, a new cycle starts and all of them are due again.
+func (r *facilitatorRotation) slot(candidates []database.User) *database.User {
+	var due []database.User
+	for _, candidate := range candidates {
+		if r.due(candidate.ID) {
+			due = append(due, candidate)
 		}
+	}
+	if len(due) == 0 {
+		due = candidates
+	}
 
-		users = append(users, user)
+	// Walk the member order from just after the last facilitator, wrapping around
+	start := 0
+	for i, member := range r.members {
+		if member.ID == r.lastID {
+			start = i + 1
+			break
+		}
+	}
+	for i := range r.members {
+		if candidate := findUser(due, r.members[(start+i)%len(r.members)].ID); candidate != nil {
+			return candidate
+		}
 	}
 
-	return users, nil
+	return &due[0]
 }
 
-// SetStandupMembers replaces all members of a standup with a new list
-func SetStandupMembers(standupID int, userIDs []int) error {
-	// Start transaction
-	tx, err := database.DB.Begin()
+// advance applies userID taking the next turn, as SetLastFacilitator stores it
+func (r *facilitatorRotation) advance(userID int) {
+	if !r.due(userID) {
+		r.cycleStart = r.counter + 1
+	}
+	r.counter++
+	r.turns[userID] = r.counter
+	r.lastID = userID
+}
+
+// currentRotationSlot returns whose turn it is among the candidates. The walk uses the member
+// order as it is now, so after a reorder the turn goes to the first member still due this cycle
+// who follows the last facilitator in the new order.
+func currentRotationSlot(standupID int, eligibleUsers []database.User) (*database.User, error) {
+	if len(eligibleUsers) == 0 {
+		return nil, fmt.Errorf("no eligible users")
+	}
+
+	rotation, err := loadRotation(standupID)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, err
 	}
-	defer tx.Rollback()
 
-	// Delete existing members
-	_, err = tx.Exec("DELETE FROM standup_members WHERE standup_id = ?", standupID)
+	if rotation.lastID != 0 && findUser(rotation.members, rotation.lastID) == nil {
+		slog.Warn("⚠️  [ROTATION RESET] Last facilitator is no longer a member, walking the order from the top",
+			"standup_id", standupID, "user_id", rotation.lastID)
+	}
+
+	return rotation.slot(eligibleUsers), nil
+}
+
+// RotateFacilitator updates last_facilitator_id to the current facilitator
+// This should be called after sending a message
+func RotateFacilitator(standupID int, currentFacilitatorID int) error {
+	return SetLastFacilitator(standupID, currentFacilitatorID)
+}
+
+// SkipFacilitator passes the current facilitator's turn without sending anything: the rotation
+// advances past today's slot so the following eligible member becomes current. It returns the
+// new current facilitator (their substitute, if they have one today). When a nominee is up, only
+// the nomination is dropped and the rotation's current member is up again.
+func SkipFacilitator(standupID int) (*database.User, error) {
+	standup, err := GetStandupByID(standupID)
 	if err != nil {
-		return fmt.Errorf("failed to delete existing members: %w", err)
+		return nil, err
 	}
 
-	// Insert new members with display_order
-	stmt, err := tx.Prepare("INSERT INTO standup_members (standup_id, user_id, display_order) VALUES (?, ?, ?)")
+	eligibleUsers, err := database.GetEligibleUsersForStandup(standupID, standupToday(standup))
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return nil, fmt.Errorf("failed to get eligible users: %w", err)
+	}
+	if len(eligibleUsers) == 0 {
+		return nil, ErrNoEligibleUsers
 	}
-	defer stmt.Close()
 
-	for i, userID := range userIDs {
-		_, err = stmt.Exec(standupID, userID, i)
+	if nominee := nominatedFacilitator(standup, eligibleUsers); nominee != nil {
+		if err := clearNomination(standupID); err != nil {
+			return nil, err
+		}
+
+		_, facilitator, err := GetCurrentFacilitatorSlot(standupID, eligibleUsers)
 		if err != nil {
-			return fmt.Errorf("failed to insert member %d: %w", userID, err)
+			return nil, err
 		}
+
+		slog.Info("⏭️  [FACILITATOR SKIPPED] Nominee passed their turn", "standup_id", standupID, "from", nominee.DisplayName, "to", facilitator.DisplayName)
+		return facilitator, nil
 	}
 
-	// Commit transaction
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	// Advance from the slot, not a substitute, so the normal order resumes afterwards
+	slot, _, err := GetCurrentFacilitatorSlot(standupID, eligibleUsers)
+	if err != nil {
+		return nil, err
+	}
+	if err := RotateFacilitator(standupID, slot.ID); err != nil {
+		return nil, err
 	}
 
-	return nil
+	_, facilitator, err := GetCurrentFacilitatorSlot(standupID, eligibleUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("⏭️  [FACILITATOR SKIPPED] Passed their turn", "standup_id", standupID, "from", slot.DisplayName, "to", facilitator.DisplayName)
+	return facilitator, nil
 }
 
-// SetLastFacilitator sets the last facilitator for a standup
-func SetLastFacilitator(standupID, userID int) error {
-	query := `
-		UPDATE standups
-		SET last_facilitator_id = ?, updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
-	`
+// NominateFacilitator makes userID the facilitator of the next send, overriding the rotation for
+// one turn. The nominee must be an eligible member today who takes facilitation turns. The
+// nomination is stored apart from the rotation, whose position is kept: once the nominee's
+// reminder is sent, the member whose turn it was before the nomination is up again.
+func NominateFacilitator(standupID, userID int) error {
+	if err := validateFacilitatorOverride(standupID, userID); err != nil {
+		return err
+	}
 
-	result, err := database.DB.Exec(query, userID, standupID)
+	settings, err := GetStandupMemberSettings(standupID)
 	if err != nil {
-		return fmt.Errorf("failed to set last facilitator: %w", err)
+		return err
 	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("standup not found")
+	if member, ok := settings[userID]; ok && !member.CanFacilitate {
+		return ErrCannotFacilitate
 	}
 
-	return nil
+	return setStandupField(standupID, "nominated_facilitator_id", userID)
 }
 
-// GetCurrentFacilitator calculates the current facilitator from eligible users based on last facilitator
-func GetCurrentFacilitator(standupID int, eligibleUsers []database.User) (*database.User, error) {
-	standup, err := GetStandupByID(standupID)
+// nominatedFacilitator returns the standup's nominee when they are among users, or nil
+func nominatedFacilitator(standup *database.Standup, users []database.User) *database.User {
+	if standup.NominatedFacilitatorID == nil {
+		return nil
+	}
+	return findUser(users, *standup.NominatedFacilitatorID)
+}
+
+// clearNomination drops a standup's nominated facilitator, if any
+func clearNomination(standupID int) error {
+	_, err := database.DB.Exec(
+		"UPDATE standups SET nominated_facilitator_id = NULL WHERE id = ? AND nominated_facilitator_id IS NOT NULL",
+		standupID,
+	)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to clear nominated facilitator: %w", err)
 	}
+	return nil
+}
 
+// GetNextFacilitator returns who tomorrow's facilitator will be (calculated from eligible users),
+// applying tomorrow's substitutions. currentSlotID is today's rotation slot, not its substitute.
+func GetNextFacilitator(standupID int, eligibleUsers []database.User, currentSlotID int) (*database.User, error) {
 	if len(eligibleUsers) == 0 {
 		return nil, fmt.Errorf("no eligible users")
 	}
 
-	// If no last facilitator, return first eligible user
-	if standup.LastFacilitatorID == nil {
-		return &eligibleUsers[0], nil
+	tomorrow := standupNow(standupID).AddDate(0, 0, 1).Format("2006-01-02")
+	candidates, substitutes, err := rotationCandidates(standupID, eligibleUsers, tomorrow)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get all members ordered by display_order
-	allMembers, err := GetStandupMembers(standupID)
+	next, err := nextRotationSlot(standupID, candidates, currentSlotID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Find last facilitator index in the full member list
-	lastFacIndex := -1
-	for i, member := range allMembers {
-		if member.ID == *standup.LastFacilitatorID {
-			lastFacIndex = i
-			break
-		}
+	if substitute, ok := substitutes[next.ID]; ok {
+		return substitute, nil
 	}
+	return next, nil
+}
 
-	// If last facilitator not found, start from beginning
-	if lastFacIndex == -1 {
-		return &eligibleUsers[0], nil
+// nextRotationSlot returns whose turn it will be among the candidates once the current slot has had theirs
+func nextRotationSlot(standupID int, eligibleUsers []database.User, currentFacilitatorID int) (*database.User, error) {
+	if len(eligibleUsers) == 0 {
+		return nil, fmt.Errorf("no eligible users")
 	}
 
-	// Find next eligible facilitator after last facilitator in the rotation order
-	for i := lastFacIndex + 1; i < len(allMembers); i++ {
-		for _, eligible := range eligibleUsers {
-			if allMembers[i].ID == eligible.ID {
-				return &eligible, nil
-			}
-		}
+	rotation, err := loadRotation(standupID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Wrap around: check from start to lastFacIndex
-	for i := 0; i <= lastFacIndex; i++ {
-		for _, eligible := range eligibleUsers {
-			if allMembers[i].ID == eligible.ID {
-				return &eligible, nil
-			}
-		}
+	rotation.advance(currentFacilitatorID)
+	return rotation.slot(eligibleUsers), nil
+}
+
+// memberDisplayOrder returns a member's position in the standup, or ErrNotStandupMember
+// if the user is not on the standup's roster (including when the standup does not exist).
+// all_active standups have no manual order and return ErrDynamicMembership.
+func memberDisplayOrder(standupID, userID int) (int, error) {
+	var membership string
+	err := database.DB.QueryRow("SELECT membership FROM standups WHERE id = ?", standupID).Scan(&membership)
+	if err == nil && membership == database.MembershipAllActive {
+		return 0, ErrDynamicMembership
 	}
 
-	// Fallback: return first eligible user
-	return &eligibleUsers[0], nil
-}
+	var order int
+	err = database.DB.QueryRow(
+		"SELECT display_order FROM standup_members WHERE standup_id = ? AND user_id = ?",
+		standupID, userID,
+	).Scan(&order)
 
-// RotateFacilitator updates last_facilitator_id to the current facilitator
-// This should be called after sending a message
-func RotateFacilitator(standupID int, currentFacilitatorID int) error {
-	return SetLastFacilitator(standupID, currentFacilitatorID)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotStandupMember
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current order: %w", err)
+	}
+
+	return order, nil
 }
 
-// GetNextFacilitator returns who tomorrow's facilitator will be (calculated from eligible users)
-func GetNextFacilitator(standupID int, eligibleUsers []database.User, currentFacilitatorID int) (*database.User, error) {
-	if len(eligibleUsers) == 0 {
-		return nil, fmt.Errorf("no eligible users")
+// ReorderStandupMembers rewrites the whole rotation order in one transaction. orderedIDs must
+// be exactly the current members; all_active standups return ErrDynamicMembership.
+func ReorderStandupMembers(standupID int, orderedIDs []int) error {
+	standup, err := GetStandupByID(standupID)
+	if err != nil {
+		return err
+	}
+	if standup.Membership == database.MembershipAllActive {
+		return ErrDynamicMembership
 	}
 
-	// Get all members ordered by display_order
-	allMembers, err := GetStandupMembers(standupID)
+	tx, err := database.DB.Begin()
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Find current facilitator index in the full member list
-	currentFacIndex := -1
-	for i, member := range allMembers {
-		if member.ID == currentFacilitatorID {
-			currentFacIndex = i
-			break
+	rows, err := tx.Query("SELECT user_id FROM standup_members WHERE standup_id = ?", standupID)
+	if err != nil {
+		return fmt.Errorf("failed to get current members: %w", err)
+	}
+	current := make(map[int]bool)
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan member: %w", err)
 		}
+		current[userID] = true
 	}
+	rows.Close()
 
-	// If current facilitator not found, return first eligible user
-	if currentFacIndex == -1 {
-		return &eligibleUsers[0], nil
+	if len(orderedIDs) != len(current) {
+		return ErrMemberOrderMismatch
 	}
-
-	// Find next eligible facilitator after current facilitator in the rotation order
-	for i := currentFacIndex + 1; i < len(allMembers); i++ {
-		for _, eligible := range eligibleUsers {
-			if allMembers[i].ID == eligible.ID {
-				return &eligible, nil
-			}
+	seen := make(map[int]bool, len(orderedIDs))
+	for _, userID := range orderedIDs {
+		if !current[userID] || seen[userID] {
+			return ErrMemberOrderMismatch
 		}
+		seen[userID] = true
 	}
 
-	// Wrap around: check from start to currentFacIndex
-	for i := 0; i <= currentFacIndex; i++ {
-		for _, eligible := range eligibleUsers {
-			if allMembers[i].ID == eligible.ID {
-				return &eligible, nil
-			}
+	for i, userID := range orderedIDs {
+		_, err = tx.Exec(
+			"UPDATE standup_members SET display_order = ? WHERE standup_id = ? AND user_id = ?",
+			i, standupID, userID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to reorder members: %w", err)
 		}
 	}
 
-	// Fallback: return first eligible user
-	return &eligibleUsers[0], nil
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
 }
 
 // MoveMemberUp moves a member up in the display order
 func MoveMemberUp(standupID, userID int) error {
 	// Get current order
-	var currentOrder int
-	err := database.DB.QueryRow(
-		"SELECT display_order FROM standup_members WHERE standup_id = ? AND user_id = ?",
-		standupID, userID,
-	).Scan(&currentOrder)
-
+	currentOrder, err := memberDisplayOrder(standupID, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get current order: %w", err)
+		return err
 	}
 
 	if currentOrder == 0 {
@@ -622,16 +1711,13 @@ func MoveMemberUp(standupID, userID int) error {
 // MoveMemberDown moves a member down in the display order
 func MoveMemberDown(standupID, userID int) error {
 	// Get current order and max order
-	var currentOrder, maxOrder int
-	err := database.DB.QueryRow(
-		"SELECT display_order FROM standup_members WHERE standup_id = ? AND user_id = ?",
-		standupID, userID,
-	).Scan(&currentOrder)
-
+	currentOrder, err := memberDisplayOrder(standupID, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get current order: %w", err)
+		return err
 	}
 
+	var maxOrder int
+
 	err = database.DB.QueryRow(
 		"SELECT MAX(display_order) FROM standup_members WHERE standup_id = ?",
 		standupID,
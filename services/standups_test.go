@@ -0,0 +1,661 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google-chat-bot/database"
+)
+
+// takeTurns sends n turns of the rotation with the given members eligible and returns who
+// facilitated, checking on the way that each predicted next facilitator is the one who follows
+func takeTurns(t *testing.T, standupID, n int, eligible ...*database.User) []string {
+	t.Helper()
+
+	users := make([]database.User, len(eligible))
+	for i, user := range eligible {
+		users[i] = *user
+	}
+
+	var names []string
+	predicted := ""
+	for i := 0; i < n; i++ {
+		slot, _, err := GetCurrentFacilitatorSlot(standupID, users)
+		if err != nil {
+			t.Fatalf("GetCurrentFacilitatorSlot: %v", err)
+		}
+		if predicted != "" && slot.DisplayName != predicted {
+			t.Errorf("turn %d went to %s, but the previous turn predicted %s", i+1, slot.DisplayName, predicted)
+		}
+
+		next, err := GetNextFacilitator(standupID, users, slot.ID)
+		if err != nil {
+			t.Fatalf("GetNextFacilitator: %v", err)
+		}
+		predicted = next.DisplayName
+
+		if err := RotateFacilitator(standupID, slot.ID); err != nil {
+			t.Fatalf("RotateFacilitator: %v", err)
+		}
+		names = append(names, slot.DisplayName)
+	}
+	return names
+}
+
+func TestRotationAfterReorderMidCycle(t *testing.T) {
+	tests := []struct {
+		name   string
+		before int      // turns taken in the original order A, B, C, D
+		order  []string // the new order
+		want   []string // the following turns
+	}{
+		{
+			name:   "member who already went moved after the last facilitator",
+			before: 2,
+			order:  []string{"bob", "alice", "carol", "dave"},
+			want:   []string{"carol", "dave", "bob", "alice", "carol", "dave"},
+		},
+		{
+			name:   "due member moved before the last facilitator",
+			before: 1,
+			order:  []string{"carol", "alice", "bob", "dave"},
+			want:   []string{"bob", "dave", "carol", "alice", "bob", "dave"},
+		},
+		{
+			name:   "order reversed",
+			before: 2,
+			order:  []string{"dave", "carol", "bob", "alice"},
+			want:   []string{"dave", "carol", "bob", "alice", "dave", "carol"},
+		},
+		{
+			name:   "reordered at the end of a cycle",
+			before: 4,
+			order:  []string{"dave", "carol", "bob", "alice"},
+			want:   []string{"carol", "bob", "alice", "dave"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			users := map[string]*database.User{}
+			for _, name := range []string{"alice", "bob", "carol", "dave"} {
+				users[name] = mustCreateUser(t, name)
+			}
+			all := []*database.User{users["alice"], users["bob"], users["carol"], users["dave"]}
+			standup := mustCreateStandup(t, "daily", all...)
+
+			takeTurns(t, standup.ID, tt.before, all...)
+
+			ids := make([]int, len(tt.order))
+			for i, name := range tt.order {
+				ids[i] = users[name].ID
+			}
+			if err := ReorderStandupMembers(standup.ID, ids); err != nil {
+				t.Fatalf("ReorderStandupMembers: %v", err)
+			}
+
+			if got := takeTurns(t, standup.ID, len(tt.want), all...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("turns after reorder = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotationAfterRemovingLastFacilitatorMidCycle(t *testing.T) {
+	tests := []struct {
+		name   string
+		before int    // turns taken in the order A, B, C, D
+		remove string // the last facilitator, removed afterwards
+		want   []string
+	}{
+		{"middle of the order", 2, "bob", []string{"carol", "dave", "alice", "carol", "dave"}},
+		{"first in the order", 1, "alice", []string{"bob", "carol", "dave", "bob"}},
+		{"last in the order", 4, "dave", []string{"alice", "bob", "carol", "alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			users := map[string]*database.User{}
+			for _, name := range []string{"alice", "bob", "carol", "dave"} {
+				users[name] = mustCreateUser(t, name)
+			}
+			all := []*database.User{users["alice"], users["bob"], users["carol"], users["dave"]}
+			standup := mustCreateStandup(t, "daily", all...)
+
+			takeTurns(t, standup.ID, tt.before, all...)
+			if err := RemoveStandupMember(standup.ID, users[tt.remove].ID); err != nil {
+				t.Fatalf("RemoveStandupMember: %v", err)
+			}
+
+			var remaining []*database.User
+			for _, user := range all {
+				if user.DisplayName != tt.remove {
+					remaining = append(remaining, user)
+				}
+			}
+			if got := takeTurns(t, standup.ID, len(tt.want), remaining...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("turns after removal = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveStandupMemberHandsOnLastFacilitator(t *testing.T) {
+	tests := []struct {
+		name     string
+		members  []string
+		before   int    // turns taken in member order
+		remove   string // the last facilitator, removed afterwards
+		wantLast string // last_facilitator_id afterwards ("" = none)
+	}{
+		{"middle of the order", []string{"alice", "bob", "carol"}, 2, "bob", "alice"},
+		{"first in the order wraps around", []string{"alice", "bob", "carol"}, 1, "alice", "carol"},
+		{"first in the order in a later cycle", []string{"alice", "bob", "carol"}, 4, "alice", "carol"},
+		{"last in the order", []string{"alice", "bob", "carol"}, 3, "carol", "bob"},
+		{"one member left", []string{"alice", "bob"}, 1, "alice", "bob"},
+		{"only member", []string{"alice"}, 1, "alice", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			users := map[string]*database.User{}
+			var all, remaining []*database.User
+			for _, name := range tt.members {
+				users[name] = mustCreateUser(t, name)
+				all = append(all, users[name])
+				if name != tt.remove {
+					remaining = append(remaining, users[name])
+				}
+			}
+			standup := mustCreateStandup(t, "daily", all...)
+
+			takeTurns(t, standup.ID, tt.before, all...)
+			wantNext := ""
+			if len(remaining) > 0 {
+				wantNext = currentSlotName(t, standup.ID, all...)
+			}
+
+			if err := RemoveStandupMember(standup.ID, users[tt.remove].ID); err != nil {
+				t.Fatalf("RemoveStandupMember: %v", err)
+			}
+
+			updated, err := GetStandupByID(standup.ID)
+			if err != nil {
+				t.Fatalf("GetStandupByID: %v", err)
+			}
+			gotLast := ""
+			if updated.LastFacilitatorID != nil {
+				for name, user := range users {
+					if user.ID == *updated.LastFacilitatorID {
+						gotLast = name
+					}
+				}
+			}
+			if gotLast != tt.wantLast {
+				t.Errorf("last facilitator = %q, want %q", gotLast, tt.wantLast)
+			}
+
+			if len(remaining) == 0 {
+				// A newcomer to the emptied standup starts the rotation
+				dave := mustCreateUser(t, "dave")
+				if err := AddStandupMembers(standup.ID, []int{dave.ID}); err != nil {
+					t.Fatalf("AddStandupMembers: %v", err)
+				}
+				remaining, wantNext = []*database.User{dave}, "dave"
+			}
+			if got := currentSlotName(t, standup.ID, remaining...); got != wantNext {
+				t.Errorf("next facilitator after removal = %s, want %s as before", got, wantNext)
+			}
+		})
+	}
+}
+
+// currentSlotName returns who has the current rotation slot with the given members eligible
+func currentSlotName(t *testing.T, standupID int, eligible ...*database.User) string {
+	t.Helper()
+
+	users := make([]database.User, len(eligible))
+	for i, user := range eligible {
+		users[i] = *user
+	}
+
+	slot, _, err := GetCurrentFacilitatorSlot(standupID, users)
+	if err != nil {
+		t.Fatalf("GetCurrentFacilitatorSlot: %v", err)
+	}
+	return slot.DisplayName
+}
+
+func TestRotationKeepsAbsentMemberDue(t *testing.T) {
+	setupTestDB(t)
+	alice := mustCreateUser(t, "alice")
+	bob := mustCreateUser(t, "bob")
+	carol := mustCreateUser(t, "carol")
+	standup := mustCreateStandup(t, "daily", alice, bob, carol)
+
+	// Carol is away for a whole cycle, which starts over without her...
+	got := takeTurns(t, standup.ID, 3, alice, bob)
+	// ...and she is still due once back, ahead of anyone taking a second turn this cycle
+	got = append(got, takeTurns(t, standup.ID, 3, alice, bob, carol)...)
+
+	want := []string{"alice", "bob", "alice", "bob", "carol", "alice"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("turns = %v, want %v", got, want)
+	}
+}
+
+func TestNominateFacilitator(t *testing.T) {
+	tests := []struct {
+		name          string
+		canFacilitate bool
+		skipNominee   bool
+		wantErr       error
+		want          []string // facilitators of the sends after the nomination
+	}{
+		{name: "nominee runs one send and the rotation stays put", canFacilitate: true, want: []string{"carol", "bob", "carol", "alice"}},
+		{name: "skipping the nominee only drops the nomination", canFacilitate: true, skipNominee: true, want: []string{"bob", "carol", "alice"}},
+		{name: "member who does not facilitate is rejected", canFacilitate: false, wantErr: ErrCannotFacilitate, want: []string{"bob", "alice", "bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			webhook := newWebhookRecorder(t)
+			alice := mustCreateUser(t, "alice")
+			bob := mustCreateUser(t, "bob")
+			carol := mustCreateUser(t, "carol")
+			standup := mustCreateStandup(t, "daily", alice, bob, carol)
+			if err := SetStandupMemberCanFacilitate(standup.ID, carol.ID, tt.canFacilitate); err != nil {
+				t.Fatalf("SetStandupMemberCanFacilitate: %v", err)
+			}
+
+			takeTurns(t, standup.ID, 1, alice, bob, carol)
+
+			if err := NominateFacilitator(standup.ID, carol.ID); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NominateFacilitator error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.skipNominee {
+				if _, err := SkipFacilitator(standup.ID); err != nil {
+					t.Fatalf("SkipFacilitator: %v", err)
+				}
+			}
+
+			for range tt.want {
+				if err := sendStandupReminder(standup.ID, ReminderOptions{Trigger: RunTriggerManual}); err != nil {
+					t.Fatalf("send: %v", err)
+				}
+			}
+
+			history, err := GetFacilitatorHistory(standup.ID, "")
+			if err != nil {
+				t.Fatalf("GetFacilitatorHistory: %v", err)
+			}
+			names := map[int]string{alice.ID: "alice", bob.ID: "bob", carol.ID: "carol"}
+			var got []string
+			for _, entry := range history {
+				got = append(got, names[entry.UserID])
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("facilitators = %v, want %v", got, tt.want)
+			}
+			if got := len(webhook.posts()); got != len(tt.want) {
+				t.Errorf("webhook posts = %d, want %d", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestCreateStandupWithSettings(t *testing.T) {
+	setupTestDB(t)
+	alice := mustCreateUser(t, "alice")
+	bob := mustCreateUser(t, "bob")
+
+	rotates := false
+	standup, err := CreateStandupWithSettings("Daily", "Standup time", "09:30", "Asia/Tokyo", "admin", StandupSettings{
+		Members:            []int{bob.ID, alice.ID},
+		MemberSettings:     map[int]database.StandupMember{bob.ID: {Alias: "Bobby", CanFacilitate: false}},
+		WebhookURL:         "https://chat.example.com/main",
+		WebhookURLs:        []string{"https://chat.example.com/extra", " ", "https://chat.example.com/extra"},
+		SkipWhenAlone:      true,
+		ManualSendRotates:  &rotates,
+		DaysOfWeek:         "1,3,5",
+		OwnerUserID:        alice.ID,
+		EmptyRetryMinutes:  15,
+		MessageFormat:      database.MessageFormatCard,
+		MentionFacilitator: true,
+		ThreadKey:          "standup",
+	})
+	if err != nil {
+		t.Fatalf("CreateStandupWithSettings: %v", err)
+	}
+
+	if standup.WebhookURL != "https://chat.example.com/main" || !reflect.DeepEqual(standup.WebhookURLs, []string{"https://chat.example.com/extra"}) {
+		t.Errorf("webhooks = %q %q", standup.WebhookURL, standup.WebhookURLs)
+	}
+	if standup.Membership != database.MembershipExplicit || !standup.SkipWhenAlone || standup.ManualSendRotates {
+		t.Errorf("membership = %q, skip_when_alone = %v, manual_send_rotates = %v", standup.Membership, standup.SkipWhenAlone, standup.ManualSendRotates)
+	}
+	if standup.DaysOfWeek != "MON,WED,FRI" || standup.EmptyRetryMinutes != 15 || standup.ThreadKey != "standup" {
+		t.Errorf("days_of_week = %q, empty_retry_minutes = %d, thread_key = %q", standup.DaysOfWeek, standup.EmptyRetryMinutes, standup.ThreadKey)
+	}
+	if standup.OwnerUserID == nil || *standup.OwnerUserID != alice.ID {
+		t.Errorf("owner_user_id = %v, want %d", standup.OwnerUserID, alice.ID)
+	}
+	if standup.MessageFormat != database.MessageFormatCard || !standup.MentionFacilitator {
+		t.Errorf("message_format = %q, mention_facilitator = %v", standup.MessageFormat, standup.MentionFacilitator)
+	}
+
+	members, err := GetStandupMembers(standup.ID)
+	if err != nil {
+		t.Fatalf("GetStandupMembers: %v", err)
+	}
+	var names []string
+	for _, member := range members {
+		names = append(names, member.DisplayName)
+	}
+	if !reflect.DeepEqual(names, []string{"bob", "alice"}) {
+		t.Errorf("members = %v, want [bob alice]", names)
+	}
+
+	settings, err := GetStandupMemberSettings(standup.ID)
+	if err != nil {
+		t.Fatalf("GetStandupMemberSettings: %v", err)
+	}
+	if settings[bob.ID].Alias != "Bobby" || settings[bob.ID].CanFacilitate || !settings[alice.ID].CanFacilitate {
+		t.Errorf("member settings = %+v", settings)
+	}
+}
+
+func TestCreateStandupWithSettingsCreatesNothingOnFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings func(alice *database.User) StandupSettings
+		wantErr  error
+	}{
+		{
+			name:     "invalid owner",
+			settings: func(alice *database.User) StandupSettings { return StandupSettings{OwnerUserID: alice.ID + 100} },
+			wantErr:  ErrInvalidOwner,
+		},
+		{
+			name:     "invalid template",
+			settings: func(alice *database.User) StandupSettings { return StandupSettings{Template: "{{.Nope"} },
+		},
+		{
+			name:     "repeated member",
+			settings: func(alice *database.User) StandupSettings { return StandupSettings{Members: []int{alice.ID, alice.ID}} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			alice := mustCreateUser(t, "alice")
+
+			_, err := CreateStandupWithSettings("Daily", "Standup time", "09:30", "", "admin", tt.settings(alice))
+			if err == nil {
+				t.Fatal("CreateStandupWithSettings succeeded, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			var count int
+			if err := database.DB.QueryRow("SELECT COUNT(*) FROM standups").Scan(&count); err != nil {
+				t.Fatal(err)
+			}
+			if count != 0 {
+				t.Errorf("%d standups created, want none", count)
+			}
+		})
+	}
+}
+
+func TestNormalizeRunAt(t *testing.T) {
+	tests := []struct {
+		runAt string
+		want  string
+	}{
+		{"09:30", "09:30"},
+		{"9:30", "09:30"},
+		{"9:05", "09:05"},
+		{"23:59", "23:59"},
+		{"0:00", "00:00"},
+		{"noon", "noon"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeRunAt(tt.runAt); got != tt.want {
+			t.Errorf("normalizeRunAt(%q) = %q, want %q", tt.runAt, got, tt.want)
+		}
+	}
+}
+
+func TestStandupListOrder(t *testing.T) {
+	setupTestDB(t)
+
+	for _, standup := range []struct{ name, runAt string }{
+		{"Ops", "10:00"},
+		{"Design", "9:30"}, // stored zero-padded
+		{"Backend", "10:00"},
+		{"Backend", "10:00"}, // same time and name: the older one first
+		{"Paused", "08:00"},
+	} {
+		if _, err := CreateStandup(standup.name, "Standup time!", standup.runAt, "", "test"); err != nil {
+			t.Fatalf("CreateStandup(%s): %v", standup.name, err)
+		}
+	}
+	// Rows written before run_at was zero-padded still sort as a time of day
+	if _, err := database.DB.Exec("INSERT INTO standups (name, message, run_at, created_by, is_active) VALUES ('Legacy', 'Hi', '9:45', 'test', 1)"); err != nil {
+		t.Fatalf("insert legacy standup: %v", err)
+	}
+	if err := DeleteStandup(5); err != nil {
+		t.Fatalf("DeleteStandup: %v", err)
+	}
+
+	describe := func(standups []database.Standup) []string {
+		var got []string
+		for _, standup := range standups {
+			got = append(got, fmt.Sprintf("%s %s #%d", standup.RunAt, standup.Name, standup.ID))
+		}
+		return got
+	}
+
+	active := []string{"09:30 Design #2", "9:45 Legacy #6", "10:00 Backend #3", "10:00 Backend #4", "10:00 Ops #1"}
+	all := append([]string{"08:00 Paused #5"}, active...)
+
+	tests := []struct {
+		name string
+		list func() ([]database.Standup, error)
+		want []string
+	}{
+		{"active", GetActiveStandups, active},
+		{"all", GetAllStandups, all},
+		{"active page 2", func() ([]database.Standup, error) {
+			standups, _, err := GetActiveStandupsPaginated(2, 2)
+			return standups, err
+		}, active[2:4]},
+		{"all page 1", func() ([]database.Standup, error) {
+			standups, _, err := GetAllStandupsPaginated(3, 0)
+			return standups, err
+		}, all[:3]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standups, err := tt.list()
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if got := describe(standups); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStandupsByWebhookURL(t *testing.T) {
+	setupTestDB(t)
+
+	const (
+		mainURL   = "https://chat.example.com/main"
+		extraURL  = "https://chat.example.com/extra"
+		sharedURL = "https://chat.example.com/shared"
+	)
+	for _, standup := range []struct {
+		name    string
+		webhook string
+		extra   []string
+	}{
+		{"Main", mainURL, nil},
+		{"Extra", "", []string{extraURL, sharedURL}},
+		{"Both", sharedURL, []string{extraURL}},
+		{"Global", "", nil},
+	} {
+		created, err := CreateStandup(standup.name, "Standup time!", "09:00", "", "test")
+		if err != nil {
+			t.Fatalf("CreateStandup(%s): %v", standup.name, err)
+		}
+		if err := SetStandupWebhookURL(created.ID, standup.webhook); err != nil {
+			t.Fatalf("SetStandupWebhookURL(%s): %v", standup.name, err)
+		}
+		if err := SetStandupWebhookURLs(created.ID, standup.extra); err != nil {
+			t.Fatalf("SetStandupWebhookURLs(%s): %v", standup.name, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		webhookURL string
+		want       []string
+	}{
+		{"main webhook", mainURL, []string{"Main"}},
+		{"extra webhook", extraURL, []string{"Both", "Extra"}},
+		{"main or extra webhook", sharedURL, []string{"Both", "Extra"}},
+		{"prefix only", "https://chat.example.com/", nil},
+		{"unknown", "https://chat.example.com/other", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standups, err := GetStandupsByWebhookURL(tt.webhookURL)
+			if err != nil {
+				t.Fatalf("GetStandupsByWebhookURL: %v", err)
+			}
+			var got []string
+			for _, standup := range standups {
+				got = append(got, standup.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("standups = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStandupMemberAlias(t *testing.T) {
+	tests := []struct {
+		name        string
+		user        string
+		alias       string
+		wantErr     error
+		wantAliases map[string]string
+	}{
+		{"set", "alice", "Ally", nil, map[string]string{"alice": "Ally"}},
+		{"clear", "alice", "", nil, map[string]string{}},
+		{"replace", "bob", "Bobby", nil, map[string]string{"bob": "Bobby"}},
+		{"not a member", "carol", "Caz", ErrNotStandupMember, map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			users := map[string]*database.User{}
+			for _, name := range []string{"alice", "bob", "carol"} {
+				users[name] = mustCreateUser(t, name)
+			}
+			standup := mustCreateStandup(t, "daily", users["alice"], users["bob"])
+			if err := SetStandupMemberAlias(standup.ID, users["bob"].ID, "Robert"); err != nil {
+				t.Fatalf("SetStandupMemberAlias: %v", err)
+			}
+			if tt.user != "bob" {
+				if err := SetStandupMemberAlias(standup.ID, users["bob"].ID, ""); err != nil {
+					t.Fatalf("SetStandupMemberAlias: %v", err)
+				}
+			}
+
+			err := SetStandupMemberAlias(standup.ID, users[tt.user].ID, tt.alias)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			aliases, err := GetStandupMemberAliases(standup.ID)
+			if err != nil {
+				t.Fatalf("GetStandupMemberAliases: %v", err)
+			}
+			got := map[string]string{}
+			for name, user := range users {
+				if alias, ok := aliases[user.ID]; ok {
+					got[name] = alias
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantAliases) {
+				t.Errorf("aliases = %v, want %v", got, tt.wantAliases)
+			}
+		})
+	}
+}
+
+func TestMemberAliasSurvivesRosterChanges(t *testing.T) {
+	setupTestDB(t)
+	setNow(time.Date(2025, 3, 12, 8, 0, 0, 0, time.UTC))
+	alice := mustCreateUser(t, "alice")
+	bob := mustCreateUser(t, "bob")
+	carol := mustCreateUser(t, "carol")
+	standup := mustCreateStandup(t, "daily", alice, bob)
+	other := mustCreateStandup(t, "other", alice, bob)
+
+	for _, user := range []*database.User{alice, bob} {
+		if err := SetStandupMemberAlias(standup.ID, user.ID, "The "+user.DisplayName); err != nil {
+			t.Fatalf("SetStandupMemberAlias: %v", err)
+		}
+	}
+
+	// Replacing the roster keeps the alias of a member who stays and drops the one who left
+	if err := SetStandupMembers(standup.ID, []int{carol.ID, alice.ID}); err != nil {
+		t.Fatalf("SetStandupMembers: %v", err)
+	}
+	if err := SetStandupMembers(standup.ID, []int{carol.ID, alice.ID, bob.ID}); err != nil {
+		t.Fatalf("SetStandupMembers: %v", err)
+	}
+	mustCreateApprovedLeave(t, carol, "vacation", "2025-03-12", "2025-03-12")
+
+	tests := []struct {
+		standupID int
+		want      []string
+	}{
+		// The alias is per standup: alice facilitates both, but is only "The alice" in the first
+		{standup.ID, []string{"*Today's Facilitator:* The alice\n", "*Tomorrow's Facilitator:* bob\n", "• carol (vacation)\n"}},
+		{other.ID, []string{"*Today's Facilitator:* alice\n", "*Tomorrow's Facilitator:* bob\n"}},
+	}
+
+	for _, tt := range tests {
+		reminder, err := BuildStandupMessage(tt.standupID, ReminderOptions{})
+		if err != nil {
+			t.Fatalf("BuildStandupMessage: %v", err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(reminder.Message, want) {
+				t.Errorf("standup %d message does not contain %q:\n%s", tt.standupID, want, reminder.Message)
+			}
+		}
+	}
+}
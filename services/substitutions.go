@@ -0,0 +1,197 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"google-chat-bot/database"
+)
+
+var (
+	// ErrSubstitutionNotFound is returned when no substitution has the given ID on the standup
+	ErrSubstitutionNotFound = errors.New("substitution not found")
+	// ErrInvalidSubstitution is returned when a substitution names the same user twice or ends before it starts
+	ErrInvalidSubstitution = errors.New("substitute must differ from the original facilitator and end_date must not be before start_date")
+)
+
+// substitutionColumns is the column list shared by substitution queries, in scanSubstitution order
+const substitutionColumns = `id, standup_id, original_user_id, substitute_user_id, start_date, end_date, created_at`
+
+// scanSubstitution scans a single substitution row selected with substitutionColumns
+func scanSubstitution(row interface{ Scan(...interface{}) error }) (database.FacilitatorSubstitution, error) {
+	var sub database.FacilitatorSubstitution
+	err := row.Scan(
+		&sub.ID,
+		&sub.StandupID,
+		&sub.OriginalUserID,
+		&sub.SubstituteUserID,
+		&sub.StartDate,
+		&sub.EndDate,
+		&sub.CreatedAt,
+	)
+	return sub, err
+}
+
+// CreateSubstitution hands the original member's facilitation turns to the substitute between
+// start and end (YYYY-MM-DD, inclusive). Both users must be members of the standup.
+func CreateSubstitution(standupID, originalUserID, substituteUserID int, startDate, endDate string) (*database.FacilitatorSubstitution, error) {
+	if _, err := GetStandupByID(standupID); err != nil {
+		return nil, err
+	}
+
+	if originalUserID == substituteUserID || endDate < startDate {
+		return nil, ErrInvalidSubstitution
+	}
+
+	for _, userID := range []int{originalUserID, substituteUserID} {
+		isMember, err := IsStandupMember(standupID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, ErrNotStandupMember
+		}
+	}
+
+	query := `
+		INSERT INTO facilitator_substitutions (standup_id, original_user_id, substitute_user_id, start_date, end_date)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	var id int
+	err := database.DB.QueryRow(query, standupID, originalUserID, substituteUserID, startDate, endDate).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create substitution: %w", err)
+	}
+
+	sub, err := scanSubstitution(database.DB.QueryRow(
+		`SELECT `+substitutionColumns+` FROM facilitator_substitutions WHERE id = ?`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get substitution: %w", err)
+	}
+
+	return &sub, nil
+}
+
+// GetSubstitutions lists a standup's substitutions, earliest first
+func GetSubstitutions(standupID int) ([]database.FacilitatorSubstitution, error) {
+	query := `
+		SELECT ` + substitutionColumns + `
+		FROM facilitator_substitutions
+		WHERE standup_id = ?
+		ORDER BY start_date, id
+	`
+
+	rows, err := database.DB.Query(query, standupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get substitutions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []database.FacilitatorSubstitution{}
+	for rows.Next() {
+		sub, err := scanSubstitution(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan substitution: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+// DeleteSubstitution removes a substitution from a standup
+func DeleteSubstitution(standupID, id int) error {
+	result, err := database.DB.Exec(
+		"DELETE FROM facilitator_substitutions WHERE id = ? AND standup_id = ?", id, standupID)
+	if err != nil {
+		return fmt.Errorf("failed to delete substitution: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrSubstitutionNotFound
+	}
+
+	return nil
+}
+
+// activeSubstitutions maps original user ID to substitute user ID for substitutions covering day.
+// When several overlap, the most recently created one wins.
+func activeSubstitutions(standupID int, day string) (map[int]int, error) {
+	query := `
+		SELECT original_user_id, substitute_user_id
+		FROM facilitator_substitutions
+		WHERE standup_id = ? AND start_date <= ? AND end_date >= ?
+		ORDER BY id
+	`
+
+	rows, err := database.DB.Query(query, standupID, day, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active substitutions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := make(map[int]int)
+	for rows.Next() {
+		var originalID, substituteID int
+		if err := rows.Scan(&originalID, &substituteID); err != nil {
+			return nil, fmt.Errorf("failed to scan substitution: %w", err)
+		}
+		subs[originalID] = substituteID
+	}
+
+	return subs, rows.Err()
+}
+
+// rotationCandidates returns the members whose turn can be taken on day: the eligible users who
+// can facilitate, plus members (typically on leave) whose turn is covered by an eligible substitute.
+// The map gives the substitute standing in for each covered member. If no eligible user can
+// facilitate, every eligible user is a candidate so the standup still gets a facilitator.
+func rotationCandidates(standupID int, eligibleUsers []database.User, day string) ([]database.User, map[int]*database.User, error) {
+	subs, err := activeSubstitutions(standupID, day)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings, err := GetStandupMemberSettings(standupID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidates := []database.User{}
+	for _, user := range eligibleUsers {
+		if member, ok := settings[user.ID]; !ok || member.CanFacilitate {
+			candidates = append(candidates, user)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = append(candidates, eligibleUsers...)
+	}
+
+	substitutes := make(map[int]*database.User)
+	for originalID, substituteID := range subs {
+		if member, ok := settings[originalID]; ok && !member.CanFacilitate {
+			// The original member has no turn to cover
+			continue
+		}
+
+		substitute := findUser(eligibleUsers, substituteID)
+		if substitute == nil {
+			// The substitute is away too, so the turn is skipped as usual
+			continue
+		}
+		substitutes[originalID] = substitute
+
+		if findUser(candidates, originalID) == nil {
+			original, err := GetUserByID(originalID)
+			if err != nil {
+				return nil, nil, err
+			}
+			candidates = append(candidates, *original)
+		}
+	}
+
+	return candidates, substitutes, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google-chat-bot/database"
+)
+
+func TestCreateSubstitutionValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		original   string
+		substitute string
+		start, end string
+		wantErr    error
+	}{
+		{"valid", "alice", "bob", "2025-03-10", "2025-03-14", nil},
+		{"single day", "alice", "bob", "2025-03-10", "2025-03-10", nil},
+		{"same user", "alice", "alice", "2025-03-10", "2025-03-14", ErrInvalidSubstitution},
+		{"ends before it starts", "alice", "bob", "2025-03-14", "2025-03-10", ErrInvalidSubstitution},
+		{"original not a member", "dave", "bob", "2025-03-10", "2025-03-14", ErrNotStandupMember},
+		{"substitute not a member", "alice", "dave", "2025-03-10", "2025-03-14", ErrNotStandupMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			users := map[string]*database.User{}
+			for _, name := range []string{"alice", "bob", "dave"} {
+				users[name] = mustCreateUser(t, name)
+			}
+			standup := mustCreateStandup(t, "daily", users["alice"], users["bob"])
+
+			sub, err := CreateSubstitution(standup.ID, users[tt.original].ID, users[tt.substitute].ID, tt.start, tt.end)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (sub.OriginalUserID != users[tt.original].ID || sub.SubstituteUserID != users[tt.substitute].ID) {
+				t.Errorf("substitution = %+v", sub)
+			}
+		})
+	}
+}
+
+func TestSubstituteTakesFacilitatorSlot(t *testing.T) {
+	tests := []struct {
+		name            string
+		day             string
+		onLeave         []string
+		wantSlot        string
+		wantFacilitator string
+	}{
+		{"before the range", "2025-03-09", nil, "alice", "alice"},
+		{"first day", "2025-03-10", nil, "alice", "carol"},
+		{"last day", "2025-03-12", nil, "alice", "carol"},
+		{"after the range", "2025-03-13", nil, "alice", "alice"},
+		{"original on leave", "2025-03-11", []string{"alice"}, "alice", "carol"},
+		{"substitute on leave", "2025-03-11", []string{"carol"}, "alice", "alice"},
+		{"both on leave", "2025-03-11", []string{"alice", "carol"}, "bob", "bob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			day, _ := time.Parse("2006-01-02", tt.day)
+			setNow(day.Add(8 * time.Hour))
+
+			users := map[string]*database.User{}
+			for _, name := range []string{"alice", "bob", "carol"} {
+				users[name] = mustCreateUser(t, name)
+			}
+			standup := mustCreateStandup(t, "daily", users["alice"], users["bob"], users["carol"])
+			if _, err := CreateSubstitution(standup.ID, users["alice"].ID, users["carol"].ID, "2025-03-10", "2025-03-12"); err != nil {
+				t.Fatalf("CreateSubstitution: %v", err)
+			}
+			for _, name := range tt.onLeave {
+				mustCreateApprovedLeave(t, users[name], "vacation", tt.day, tt.day)
+			}
+
+			eligible, err := database.GetEligibleUsersForStandup(standup.ID, Today())
+			if err != nil {
+				t.Fatalf("GetEligibleUsersForStandup: %v", err)
+			}
+			slot, facilitator, err := GetCurrentFacilitatorSlot(standup.ID, eligible)
+			if err != nil {
+				t.Fatalf("GetCurrentFacilitatorSlot: %v", err)
+			}
+			if slot.DisplayName != tt.wantSlot || facilitator.DisplayName != tt.wantFacilitator {
+				t.Errorf("slot %s facilitated by %s, want slot %s facilitated by %s",
+					slot.DisplayName, facilitator.DisplayName, tt.wantSlot, tt.wantFacilitator)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"google-chat-bot/database"
+	"google-chat-bot/integrations"
+)
+
+// StandupExport is a self-contained standup definition that can be imported into another instance.
+// Members are identified by google_chat_user_id since internal user IDs differ between instances.
+type StandupExport struct {
+	Name               string   `json:"name"`
+	Message            string   `json:"message"`
+	RunAt              string   `json:"run_at"`
+	WebhookURL         string   `json:"webhook_url,omitempty"`
+	WebhookURLs        []string `json:"webhook_urls,omitempty"`
+	Membership         string   `json:"membership,omitempty"`
+	SkipWhenAlone      bool     `json:"skip_when_alone,omitempty"`
+	ShowReturning      bool     `json:"show_returning,omitempty"`
+	FullTeamMessage    string   `json:"full_team_message,omitempty"`
+	ManualSendRotates  *bool    `json:"manual_send_rotates,omitempty"` // nil (older exports) keeps the default, true
+	Timezone           string   `json:"timezone,omitempty"`
+	FacilitatorOnly    bool     `json:"facilitator_only,omitempty"`
+	DaysOfWeek         string   `json:"days_of_week,omitempty"`
+	DailyThread        bool     `json:"daily_thread,omitempty"`
+	EmptyRetryMinutes  int      `json:"empty_retry_minutes,omitempty"`
+	MessageFormat      string   `json:"message_format,omitempty"`
+	Template           string   `json:"template,omitempty"`
+	MentionFacilitator bool     `json:"mention_facilitator,omitempty"`
+	ThreadKey          string   `json:"thread_key,omitempty"`
+	Owner              string   `json:"owner,omitempty"` // google_chat_user_id of the owner
+	Members            []string `json:"members"`         // google_chat_user_id values in rotation order
+
+	// Per-member settings by google_chat_user_id; members without an entry (e.g. in older
+	// exports) get the defaults: no alias, can_facilitate on
+	MemberSettings map[string]MemberSettingsExport `json:"member_settings,omitempty"`
+}
+
+// MemberSettingsExport is a member's per-standup settings in a StandupExport
+type MemberSettingsExport struct {
+	Alias         string `json:"alias,omitempty"`
+	CanFacilitate bool   `json:"can_facilitate"`
+}
+
+// UnknownMembersError is returned by ImportStandup when some members do not exist on this instance
+type UnknownMembersError struct {
+	ChatIDs []string
+}
+
+func (e *UnknownMembersError) Error() string {
+	return fmt.Sprintf("unknown members: %s", strings.Join(e.ChatIDs, ", "))
+}
+
+// ExportStandup returns a standup's settings and explicit roster as an importable definition
+func ExportStandup(id int) (*StandupExport, error) {
+	standup, err := GetStandupByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Export the stored roster (not the computed all_active set) so it round-trips exactly
+	rows, err := database.DB.Query(`
+		SELECT u.google_chat_user_id, sm.alias, sm.can_facilitate
+		FROM standup_members sm
+		INNER JOIN users u ON u.id = sm.user_id
+		WHERE sm.standup_id = ?
+		ORDER BY sm.display_order, u.display_name
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get standup roster: %w", err)
+	}
+	defer rows.Close()
+
+	export := &StandupExport{
+		Name:               standup.Name,
+		Message:            standup.Message,
+		RunAt:              standup.RunAt,
+		WebhookURL:         standup.WebhookURL,
+		WebhookURLs:        standup.WebhookURLs,
+		Membership:         standup.Membership,
+		SkipWhenAlone:      standup.SkipWhenAlone,
+		ShowReturning:      standup.ShowReturning,
+		FullTeamMessage:    standup.FullTeamMessage,
+		ManualSendRotates:  &standup.ManualSendRotates,
+		Timezone:           standup.Timezone,
+		FacilitatorOnly:    standup.FacilitatorOnly,
+		DaysOfWeek:         standup.DaysOfWeek,
+		DailyThread:        standup.DailyThread,
+		EmptyRetryMinutes:  standup.EmptyRetryMinutes,
+		MessageFormat:      standup.MessageFormat,
+		Template:           standup.Template,
+		MentionFacilitator: standup.MentionFacilitator,
+		ThreadKey:          standup.ThreadKey,
+		Members:            []string{},
+		MemberSettings:     map[string]MemberSettingsExport{},
+	}
+
+	if standup.OwnerUserID != nil {
+		owner, err := GetUserByID(*standup.OwnerUserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get standup owner: %w", err)
+		}
+		export.Owner = owner.GoogleChatUserID
+	}
+
+	for rows.Next() {
+		var chatID string
+		var settings MemberSettingsExport
+		if err := rows.Scan(&chatID, &settings.Alias, &settings.CanFacilitate); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		export.Members = append(export.Members, chatID)
+		export.MemberSettings[chatID] = settings
+	}
+
+	return export, rows.Err()
+}
+
+// ImportStandup recreates an exported standup, resolving members by google_chat_user_id.
+// Nothing is created if any member is unknown; the error lists every missing chat ID.
+// Webhook URLs still redacted by the export are dropped (the standup falls back to the
+// global webhook); the returned warnings say which ones to set again.
+func ImportStandup(export StandupExport, createdBy string) (*database.Standup, []string, error) {
+	if export.Membership != "" && export.Membership != database.MembershipExplicit && export.Membership != database.MembershipAllActive {
+		return nil, nil, ErrInvalidMembership
+	}
+
+	if _, err := NormalizeDaysOfWeek(export.DaysOfWeek); err != nil {
+		return nil, nil, err
+	}
+
+	if export.MessageFormat != "" && !ValidMessageFormat(export.MessageFormat) {
+		return nil, nil, ErrInvalidMessageFormat
+	}
+
+	if err := ValidateReminderTemplate(export.Template); err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	webhookURL := export.WebhookURL
+	if integrations.IsRedactedWebhookURL(webhookURL) {
+		webhookURL = ""
+		warnings = append(warnings, "webhook_url was redacted in the export and was dropped; the standup posts to the global webhook until it is set again")
+	}
+	var webhookURLs []string
+	for _, extraURL := range export.WebhookURLs {
+		if integrations.IsRedactedWebhookURL(extraURL) {
+			warnings = append(warnings, fmt.Sprintf("webhook_urls entry %s was redacted in the export and was dropped; add it again to post there", extraURL))
+			continue
+		}
+		webhookURLs = append(webhookURLs, extraURL)
+	}
+
+	var userIDs []int
+	var unknown []string
+	userIDsByChatID := make(map[string]int)
+	for _, chatID := range export.Members {
+		var userID int
+		err := database.DB.QueryRow("SELECT id FROM users WHERE google_chat_user_id = ?", chatID).Scan(&userID)
+		if err != nil {
+			unknown = append(unknown, chatID)
+			continue
+		}
+		userIDs = append(userIDs, userID)
+		userIDsByChatID[chatID] = userID
+	}
+
+	var ownerID int
+	if export.Owner != "" {
+		err := database.DB.QueryRow("SELECT id FROM users WHERE google_chat_user_id = ?", export.Owner).Scan(&ownerID)
+		if err != nil {
+			unknown = append(unknown, export.Owner)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return nil, nil, &UnknownMembersError{ChatIDs: unknown}
+	}
+
+	memberSettings := make(map[int]database.StandupMember)
+	for chatID, settings := range export.MemberSettings {
+		userID, ok := userIDsByChatID[chatID]
+		if !ok {
+			// Settings for someone not on the roster have nothing to apply to
+			continue
+		}
+		memberSettings[userID] = database.StandupMember{Alias: settings.Alias, CanFacilitate: settings.CanFacilitate}
+	}
+
+	standup, err := CreateStandupWithSettings(export.Name, export.Message, export.RunAt, export.Timezone, createdBy, StandupSettings{
+		Members:            userIDs,
+		MemberSettings:     memberSettings,
+		WebhookURL:         webhookURL,
+		WebhookURLs:        webhookURLs,
+		Membership:         export.Membership,
+		SkipWhenAlone:      export.SkipWhenAlone,
+		ShowReturning:      export.ShowReturning,
+		FullTeamMessage:    export.FullTeamMessage,
+		ManualSendRotates:  export.ManualSendRotates,
+		FacilitatorOnly:    export.FacilitatorOnly,
+		DaysOfWeek:         export.DaysOfWeek,
+		OwnerUserID:        ownerID,
+		DailyThread:        export.DailyThread,
+		EmptyRetryMinutes:  export.EmptyRetryMinutes,
+		MessageFormat:      export.MessageFormat,
+		Template:           export.Template,
+		MentionFacilitator: export.MentionFacilitator,
+		ThreadKey:          export.ThreadKey,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return standup, warnings, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	setupTestDB(t)
+	alice := mustCreateUser(t, "alice")
+	bob := mustCreateUser(t, "bob")
+	standup := mustCreateStandup(t, "daily", bob, alice)
+
+	if err := SetStandupThreadKey(standup.ID, "team-standup"); err != nil {
+		t.Fatalf("SetStandupThreadKey: %v", err)
+	}
+	if err := SetStandupMemberAlias(standup.ID, alice.ID, "Ali"); err != nil {
+		t.Fatalf("SetStandupMemberAlias: %v", err)
+	}
+	if err := SetStandupMemberCanFacilitate(standup.ID, bob.ID, false); err != nil {
+		t.Fatalf("SetStandupMemberCanFacilitate: %v", err)
+	}
+
+	export, err := ExportStandup(standup.ID)
+	if err != nil {
+		t.Fatalf("ExportStandup: %v", err)
+	}
+
+	if export.ThreadKey != "team-standup" {
+		t.Errorf("exported thread_key = %q, want team-standup", export.ThreadKey)
+	}
+	wantSettings := map[string]MemberSettingsExport{
+		"users/alice": {Alias: "Ali", CanFacilitate: true},
+		"users/bob":   {CanFacilitate: false},
+	}
+	if !reflect.DeepEqual(export.MemberSettings, wantSettings) {
+		t.Errorf("exported member settings = %+v, want %+v", export.MemberSettings, wantSettings)
+	}
+
+	// Import from the JSON a client would download and post back
+	body, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded StandupExport
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	decoded.Name = "daily copy"
+
+	imported, warnings, err := ImportStandup(decoded, "test")
+	if err != nil {
+		t.Fatalf("ImportStandup: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %v, want none", warnings)
+	}
+
+	if imported.ThreadKey != "team-standup" {
+		t.Errorf("imported thread_key = %q, want team-standup", imported.ThreadKey)
+	}
+
+	settings, err := GetStandupMemberSettings(imported.ID)
+	if err != nil {
+		t.Fatalf("GetStandupMemberSettings: %v", err)
+	}
+	if got := settings[alice.ID]; got.Alias != "Ali" || !got.CanFacilitate {
+		t.Errorf("imported alice = alias %q, can_facilitate %v; want Ali, true", got.Alias, got.CanFacilitate)
+	}
+	if got := settings[bob.ID]; got.Alias != "" || got.CanFacilitate {
+		t.Errorf("imported bob = alias %q, can_facilitate %v; want no alias, false", got.Alias, got.CanFacilitate)
+	}
+
+	members, err := GetStandupMembers(imported.ID)
+	if err != nil {
+		t.Fatalf("GetStandupMembers: %v", err)
+	}
+	if len(members) != 2 || members[0].ID != bob.ID || members[1].ID != alice.ID {
+		t.Errorf("imported members = %v, want bob then alice", members)
+	}
+}
+
+func TestImportOlderExportKeepsMemberDefaults(t *testing.T) {
+	setupTestDB(t)
+	mustCreateUser(t, "alice")
+
+	var export StandupExport
+	if err := json.Unmarshal([]byte(`{"name": "daily", "message": "Standup time!", "run_at": "09:00", "members": ["users/alice"]}`), &export); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	imported, _, err := ImportStandup(export, "test")
+	if err != nil {
+		t.Fatalf("ImportStandup: %v", err)
+	}
+
+	settings, err := GetStandupMemberSettings(imported.ID)
+	if err != nil {
+		t.Fatalf("GetStandupMemberSettings: %v", err)
+	}
+	for _, member := range settings {
+		if member.Alias != "" || !member.CanFacilitate {
+			t.Errorf("member %d = alias %q, can_facilitate %v; want defaults", member.UserID, member.Alias, member.CanFacilitate)
+		}
+	}
+}